@@ -0,0 +1,152 @@
+package dyn
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Shopify/go-dyn/pkg/dyn"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceDynAPIFields maps the request fields the Dyn API names in its
+// messages to the attributes they're set from, where the names differ.
+var resourceDynAPIFields = map[string]string{
+	"dsf_monitor_id":       "monitor_id",
+	"dsf_record_set_id":    "record_set_id",
+	"dsf_response_pool_id": "response_pool_id",
+	"response_pools":       "response_pool_ids",
+	"service_id":           "traffic_director_id",
+}
+
+// resourceDynAPIPermissions maps the commands the provider calls to the Dyn
+// permission they fall under. The permission of a call is that name followed
+// by the action of its method, as in "DSFUpdate". Record commands such as
+// ARecord or CNAMERecord are all covered by the Record permissions.
+var resourceDynAPIPermissions = map[string]string{
+	"DSF":             "DSF",
+	"DSFMonitor":      "DSFMonitor",
+	"DSFNode":         "DSF",
+	"DSFRecord":       "DSF",
+	"DSFRecordSet":    "DSF",
+	"DSFResponsePool": "DSF",
+	"DSFRuleset":      "DSF",
+	"NodeList":        "Zone",
+	"Zone":            "Zone",
+	"ZoneNoteReport":  "Zone",
+}
+
+// resourceDynAPIError returns the diagnostic of an error. When it's a Dyn API
+// error about invalid or missing data, the diagnostic points at the attribute
+// that was rejected; when the user wasn't allowed to make the call, it names
+// the permission the call needs.
+func resourceDynAPIError(err error, s map[string]*schema.Schema) diag.Diagnostic {
+	diagnostic := diag.Diagnostic{
+		Severity: diag.Error,
+		Summary:  err.Error(),
+	}
+
+	var apiErr *dyn.Error
+	if !errors.As(err, &apiErr) {
+		return diagnostic
+	}
+
+	if apiErr.HasCode(dyn.ErrorCodePermissionDenied) {
+		diagnostic.Detail = fmt.Sprintf("The Dyn user isn't allowed to %s %s objects (%s %s).",
+			resourceDynAPIVerb(apiErr.Method), apiErr.Command(), apiErr.Method, apiErr.Resource)
+		if permission := resourceDynAPIPermission(apiErr.Method, apiErr.Command()); permission != "" {
+			diagnostic.Detail += fmt.Sprintf(" Grant it the %s permission in the Dyn portal.", permission)
+		} else {
+			diagnostic.Detail += " Check the permission groups of the user in the Dyn portal."
+		}
+		return diagnostic
+	}
+
+	for _, m := range apiErr.Errors() {
+		if m.ErrorCode != dyn.ErrorCodeInvalidData && m.ErrorCode != dyn.ErrorCodeMissingData {
+			continue
+		}
+
+		if path := resourceDynAPIPath(m, s); path != nil {
+			diagnostic.AttributePath = path
+			break
+		}
+	}
+
+	return diagnostic
+}
+
+// resourceDynAPIWarning returns the diagnostic of a warning the Dyn API gave
+// for a call that succeeded.
+func resourceDynAPIWarning(method, resource string, m dyn.Message, s map[string]*schema.Schema) diag.Diagnostic {
+	return diag.Diagnostic{
+		Severity:      diag.Warning,
+		Summary:       fmt.Sprintf("Dyn API warning: %s", m.Info),
+		Detail:        fmt.Sprintf("Dyn warned about the %s %s request.", method, resource),
+		AttributePath: resourceDynAPIPath(m, s),
+	}
+}
+
+// resourceDynAPIPath returns the path of the attribute of the schema a Dyn API
+// message is about, or nil when it doesn't name a field the schema has an
+// attribute for.
+func resourceDynAPIPath(m dyn.Message, s map[string]*schema.Schema) cty.Path {
+	field := m.Field()
+	if field == "" {
+		return nil
+	}
+
+	if attribute, ok := resourceDynAPIFields[field]; ok {
+		field = attribute
+	}
+
+	if _, ok := s[field]; !ok {
+		return nil
+	}
+
+	return cty.GetAttrPath(field)
+}
+
+// resourceDynAPIPermission returns the Dyn permission a call needs, or an
+// empty string when the command isn't one the provider knows the permission
+// of.
+func resourceDynAPIPermission(method, command string) string {
+	permission, ok := resourceDynAPIPermissions[command]
+	if !ok && strings.HasSuffix(command, "Record") {
+		permission, ok = "Record", true
+	}
+	if !ok {
+		return ""
+	}
+
+	switch method {
+	case http.MethodGet:
+		return permission + "Get"
+	case http.MethodPost:
+		return permission + "Add"
+	case http.MethodPut:
+		return permission + "Update"
+	case http.MethodDelete:
+		return permission + "Delete"
+	}
+
+	return ""
+}
+
+func resourceDynAPIVerb(method string) string {
+	switch method {
+	case http.MethodGet:
+		return "read"
+	case http.MethodPost:
+		return "create"
+	case http.MethodPut:
+		return "update"
+	case http.MethodDelete:
+		return "delete"
+	}
+
+	return strings.ToLower(method)
+}
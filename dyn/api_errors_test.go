@@ -0,0 +1,108 @@
+package dyn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Shopify/go-dyn/pkg/dyn"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestResourceDynAPIError(t *testing.T) {
+	s := map[string]*schema.Schema{
+		"label":             {Type: schema.TypeString},
+		"response_pool_ids": {Type: schema.TypeList},
+	}
+
+	apiError := func(code, info string) error {
+		return fmt.Errorf("Failed to update Dyn Traffic Director Record Set: %w", &dyn.Error{
+			Method:   "PUT",
+			Resource: "DSFRecordSet/service/record-set",
+			Status:   "400 Bad Request",
+			Messages: []dyn.Message{
+				{Level: "WARN", Info: "ttl: TTL is low"},
+				{Level: "ERROR", ErrorCode: code, Info: info},
+			},
+		})
+	}
+
+	tests := []struct {
+		err    error
+		path   cty.Path
+		detail string
+	}{
+		{apiError(dyn.ErrorCodeInvalidData, "label: Label must be unique"), cty.GetAttrPath("label"), ""},
+		{apiError(dyn.ErrorCodeMissingData, "response_pools: Required"), cty.GetAttrPath("response_pool_ids"), ""},
+		{apiError(dyn.ErrorCodeInvalidData, "automation: Unknown value"), nil, ""},
+		{apiError(dyn.ErrorCodePermissionDenied, "You do not have permission"), nil, "The Dyn user isn't allowed to update DSFRecordSet objects (PUT DSFRecordSet/service/record-set). Grant it the DSFUpdate permission in the Dyn portal."},
+		{errors.New("label: not from the API"), nil, ""},
+	}
+
+	for _, test := range tests {
+		d := resourceDynAPIError(test.err, s)
+		if d.Severity != diag.Error || d.Summary != test.err.Error() {
+			t.Errorf("expected an error diagnostic for %q, got %#v", test.err, d)
+		}
+
+		if !d.AttributePath.Equals(test.path) {
+			t.Errorf("expected %q to point at %#v, got %#v", test.err, test.path, d.AttributePath)
+		}
+
+		if !strings.HasPrefix(d.Detail, test.detail) {
+			t.Errorf("expected the detail of %q to start with %q, got %q", test.err, test.detail, d.Detail)
+		}
+	}
+}
+
+func TestResourceDynAPIPermission(t *testing.T) {
+	tests := []struct {
+		method, command, permission string
+	}{
+		{"POST", "DSFResponsePool", "DSFAdd"},
+		{"DELETE", "DSFMonitor", "DSFMonitorDelete"},
+		{"GET", "NodeList", "ZoneGet"},
+		{"PUT", "CNAMERecord", "RecordUpdate"},
+		{"GET", "Job", ""},
+	}
+
+	for _, test := range tests {
+		if permission := resourceDynAPIPermission(test.method, test.command); permission != test.permission {
+			t.Errorf("expected %s %s to need %q, got %q", test.method, test.command, test.permission, permission)
+		}
+	}
+}
+
+func TestResourceDynAPIWarnings(t *testing.T) {
+	r := resourceDynOperations(&schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"label": {Type: schema.TypeString, Optional: true},
+		},
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			warn := resourceDynWarningFunc(ctx)
+			warn("PUT", "DSFRecordSet/service/record-set", dyn.Message{Level: "WARN", Info: "label: Label is long"})
+			warn("PUT", "DSFRecordSet/service/record-set", dyn.Message{Level: "WARN", Info: "Zone is frozen"})
+
+			return resourceDynError(ctx, errors.New("Couldn't read"))
+		},
+	}, "Traffic Director Record Set")
+
+	diags := r.ReadContext(context.Background(), r.TestResourceData(), nil)
+	if len(diags) != 3 || diags[0].Severity != diag.Error {
+		t.Fatalf("expected the error followed by two warnings, got %#v", diags)
+	}
+
+	for _, d := range diags[1:] {
+		if d.Severity != diag.Warning || d.Detail != "Dyn warned about the PUT DSFRecordSet/service/record-set request." {
+			t.Errorf("unexpected warning: %#v", d)
+		}
+	}
+
+	if !diags[1].AttributePath.Equals(cty.GetAttrPath("label")) || diags[2].AttributePath != nil {
+		t.Errorf("expected only the first warning to point at label, got %#v and %#v", diags[1].AttributePath, diags[2].AttributePath)
+	}
+}
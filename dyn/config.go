@@ -1,6 +1,7 @@
 package dyn
 
 import (
+	"context"
 	"fmt"
 	"log"
 
@@ -15,16 +16,18 @@ type Config struct {
 	Password     string
 }
 
-// Client() returns a new client for accessing dyn.
-func (c *Config) Client() (*dyn.Client, error) {
-	client := dyn.NewClient()
+// Client() returns a new client for accessing dyn, logged in within ctx.
+func (c *Config) Client(ctx context.Context) (*dyn.Client, error) {
+	client := dyn.NewClient().WithContext(ctx)
 	// if logging.IsDebugOrHigher() {
 	// client.Verbose(true)
 	// }
 
+	client.OnWarning = resourceDynWarningFunc(ctx)
+
 	err := client.LogIn(c.CustomerName, c.Username, c.Password)
 	if err != nil {
-		return nil, fmt.Errorf("Error setting up Dyn client: %s", err)
+		return nil, fmt.Errorf("Error setting up Dyn client: %w", err)
 	}
 
 	log.Printf("[INFO] Dyn client configured for customer: %s, user: %s", c.CustomerName, c.Username)
@@ -0,0 +1,45 @@
+package dyn
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceDynGeolocationCodes() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceDynGeolocationCodesRead,
+
+		Schema: map[string]*schema.Schema{
+			"regions": {
+				Type:     schema.TypeMap,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Computed: true,
+			},
+
+			"countries": {
+				Type:     schema.TypeMap,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Computed: true,
+			},
+
+			"provinces": {
+				Type:     schema.TypeMap,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceDynGeolocationCodesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// The codes are embedded in the provider, so there is nothing to fetch;
+	// each map goes from the name to the code to use in a ruleset.
+	d.SetId("dyn-geolocation-codes")
+	d.Set("regions", geolocationCodesByName(geolocationRegions))
+	d.Set("countries", geolocationCodesByName(geolocationCountries))
+	d.Set("provinces", geolocationCodesByName(geolocationProvinces))
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package dyn
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceDynTrafficDirector() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceDynTrafficDirectorRead,
+
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"label": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			"ttl": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"active": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+
+			"pending_change": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"node": {
+				Type: schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"zone": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"fqdn": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+				Computed: true,
+			},
+
+			"ruleset_ids": {
+				Type:     schema.TypeList,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Computed: true,
+			},
+
+			"response_pool_ids": {
+				Type:     schema.TypeList,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Computed: true,
+			},
+		},
+	}
+}
+
+// dataSourceDynTrafficDirectorLookup returns the id or label argument of a
+// Traffic Director data source, exactly one of which must be set.
+func dataSourceDynTrafficDirectorLookup(d *schema.ResourceData) (string, error) {
+	label, labelExists := d.GetOk("label")
+	id, idExists := d.GetOk("id")
+
+	if labelExists && idExists {
+		return "", fmt.Errorf("label and id arguments cannot be used together")
+	}
+	if !labelExists && !idExists {
+		return "", fmt.Errorf("Either label or id must be set")
+	}
+
+	if idExists {
+		return id.(string), nil
+	}
+	return label.(string), nil
+}
+
+func dataSourceDynTrafficDirectorRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	clientList := meta.(accessControlledClientList)
+	client, err := clientList.AcquireContext(ctx)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+	defer clientList.Release(client)
+
+	idOrLabel, err := dataSourceDynTrafficDirectorLookup(d)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+
+	td, err := getTrafficDirector(client, idOrLabel)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+
+	nodes := make([]map[string]interface{}, len(td.Nodes))
+	for idx, node := range td.Nodes {
+		nodes[idx] = map[string]interface{}{
+			"zone": node.Zone,
+			"fqdn": node.FQDN,
+		}
+	}
+
+	rulesets := sortedTrafficDirectorRulesets(td)
+	rulesetIDs := make([]string, len(rulesets))
+	for idx, ruleset := range rulesets {
+		rulesetIDs[idx] = ruleset.RulesetID
+	}
+
+	tree := newTrafficDirectorTree(td)
+	responsePoolIDs := make([]string, len(tree.ResponsePools))
+	for idx, responsePool := range tree.ResponsePools {
+		responsePoolIDs[idx] = responsePool.ID
+	}
+
+	d.SetId(td.ServiceID)
+	d.Set("label", td.Label)
+	d.Set("ttl", td.TTL)
+	d.Set("active", td.Active)
+	d.Set("pending_change", td.PendingChange)
+	d.Set("node", nodes)
+	d.Set("ruleset_ids", rulesetIDs)
+	d.Set("response_pool_ids", responsePoolIDs)
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package dyn
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceDynTrafficDirectorLint() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceDynTrafficDirectorLintRead,
+
+		Schema: map[string]*schema.Schema{
+			"traffic_director_id": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"fail_on_errors": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"finding": {
+				Type: schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"severity": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"code": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"object_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"object_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"message": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+				Computed: true,
+			},
+
+			"error_count": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"warning_count": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceDynTrafficDirectorLintRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	clientList := meta.(accessControlledClientList)
+	client, err := clientList.AcquireContext(ctx)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+	defer clientList.Release(client)
+
+	tdID := d.Get("traffic_director_id").(string)
+
+	log.Printf("[DEBUG] Getting Traffic Director (%s) to lint", tdID)
+	td, err := client.GetTrafficDirector(tdID)
+	if err != nil {
+		return resourceDynError(ctx, fmt.Errorf("Couldn't find Dyn Traffic Director: %w", err))
+	}
+	td, err = resourceDynTrafficDirectorWithResponsePools(client, td)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+
+	findings := lintTrafficDirector(td)
+	if d.Get("fail_on_errors").(bool) {
+		if err := trafficDirectorFindingsError(tdID, findings); err != nil {
+			return resourceDynError(ctx, err)
+		}
+	}
+
+	errorCount, warningCount := 0, 0
+	findingsList := make([]map[string]interface{}, len(findings))
+	for idx, finding := range findings {
+		log.Printf("[DEBUG] Traffic Director (%s) lint: %s", tdID, finding)
+		switch finding.Severity {
+		case trafficDirectorLintError:
+			errorCount++
+		case trafficDirectorLintWarning:
+			warningCount++
+		}
+
+		findingsList[idx] = map[string]interface{}{
+			"severity":    finding.Severity,
+			"code":        finding.Code,
+			"object_type": finding.ObjectType,
+			"object_id":   finding.ObjectID,
+			"message":     finding.Message,
+		}
+	}
+
+	d.SetId(tdID)
+	d.Set("finding", findingsList)
+	d.Set("error_count", errorCount)
+	d.Set("warning_count", warningCount)
+
+	return nil
+}
@@ -1,7 +1,10 @@
 package dyn
 
 import (
-	"github.com/hashicorp/terraform/helper/schema"
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	"github.com/Shopify/go-dyn/pkg/dyn"
 
@@ -11,7 +14,7 @@ import (
 
 func dataSourceDynTrafficDirectorMonitor() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceDynTrafficDirectorMonitorRead,
+		ReadContext: dataSourceDynTrafficDirectorMonitorRead,
 
 		Schema: map[string]*schema.Schema{
 			"id": {
@@ -74,15 +77,28 @@ func dataSourceDynTrafficDirectorMonitor() *schema.Resource {
 				Type:     schema.TypeInt,
 				Computed: true,
 			},
+
+			"timeout": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"services": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Computed: true,
+			},
 		},
 	}
 }
 
-func dataSourceDynTrafficDirectorMonitorRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceDynTrafficDirectorMonitorRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	clientList := meta.(accessControlledClientList)
-	client, err := clientList.Acquire()
+	client, err := clientList.AcquireContext(ctx)
 	if err != nil {
-		return err
+		return resourceDynError(ctx, err)
 	}
 	defer clientList.Release(client)
 
@@ -90,10 +106,10 @@ func dataSourceDynTrafficDirectorMonitorRead(d *schema.ResourceData, meta interf
 	id, idExists := d.GetOk("id")
 
 	if labelExists && idExists {
-		return fmt.Errorf("label and id arguments cannot be used together")
+		return resourceDynError(ctx, fmt.Errorf("label and id arguments cannot be used together"))
 	}
 	if !labelExists && !idExists {
-		return fmt.Errorf("Either label or id must be set")
+		return resourceDynError(ctx, fmt.Errorf("Either label or id must be set"))
 	}
 
 	var tdm *dyn.TrafficDirectorMonitor
@@ -106,7 +122,7 @@ func dataSourceDynTrafficDirectorMonitorRead(d *schema.ResourceData, meta interf
 		tdm, err = client.FindTrafficDirectorMonitor(label.(string))
 	}
 	if err != nil {
-		return fmt.Errorf("Couldn't find Dyn Traffic Director Monitor: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Couldn't find Dyn Traffic Director Monitor: %w", err))
 	}
 
 	d.SetId(tdm.MonitorID)
@@ -121,6 +137,8 @@ func dataSourceDynTrafficDirectorMonitorRead(d *schema.ResourceData, meta interf
 	d.Set("expected", tdm.Options.Expected)
 	d.Set("path", tdm.Options.Path)
 	d.Set("port", tdm.Options.Port)
+	d.Set("timeout", tdm.Options.Timeout)
+	d.Set("services", tdm.Services)
 
 	return nil
 }
@@ -0,0 +1,129 @@
+package dyn
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+
+	"github.com/Shopify/go-dyn/pkg/dyn"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceDynTrafficDirectorMonitors() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceDynTrafficDirectorMonitorsRead,
+
+		Schema: map[string]*schema.Schema{
+			"label_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp,
+			},
+
+			"ids": {
+				Type:     schema.TypeList,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Computed: true,
+			},
+
+			"labels": {
+				Type:     schema.TypeList,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Computed: true,
+			},
+
+			"monitor": {
+				Type: schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"label": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"active": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"protocol": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"probe_interval": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"services": {
+							Type:     schema.TypeList,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+							Computed: true,
+						},
+					},
+				},
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceDynTrafficDirectorMonitorsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	clientList := meta.(accessControlledClientList)
+	client, err := clientList.AcquireContext(ctx)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+	defer clientList.Release(client)
+
+	labelRegex := d.Get("label_regex").(string)
+	re, err := regexp.Compile(labelRegex)
+	if err != nil {
+		return resourceDynError(ctx, fmt.Errorf("Failed to parse label_regex: %w", err))
+	}
+
+	log.Printf("[DEBUG] Listing Traffic Director Monitors matching: %s", labelRegex)
+	tdms := make([]*dyn.TrafficDirectorMonitor, 0)
+	if _, err := client.EachTrafficDirectorMonitor(ctx, func(tdm *dyn.TrafficDirectorMonitor) error {
+		if re.MatchString(tdm.Label) {
+			tdms = append(tdms, tdm)
+		}
+		return nil
+	}); err != nil {
+		return resourceDynError(ctx, fmt.Errorf("Couldn't list Dyn Traffic Director Monitors: %w", err))
+	}
+
+	sort.Slice(tdms, func(i, j int) bool {
+		if tdms[i].Label != tdms[j].Label {
+			return tdms[i].Label < tdms[j].Label
+		}
+		return tdms[i].MonitorID < tdms[j].MonitorID
+	})
+
+	ids := make([]string, len(tdms))
+	labels := make([]string, len(tdms))
+	tdmList := make([]map[string]interface{}, len(tdms))
+	for idx, tdm := range tdms {
+		ids[idx] = tdm.MonitorID
+		labels[idx] = tdm.Label
+		tdmList[idx] = map[string]interface{}{
+			"id":             tdm.MonitorID,
+			"label":          tdm.Label,
+			"active":         tdm.Active,
+			"protocol":       tdm.Protocol,
+			"probe_interval": tdm.ProbeInterval,
+			"services":       tdm.Services,
+		}
+	}
+
+	d.SetId(fmt.Sprintf("dyn-traffic-director-monitors/%s", labelRegex))
+	d.Set("ids", ids)
+	d.Set("labels", labels)
+	d.Set("monitor", tdmList)
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package dyn
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceDynTrafficDirectorRecord() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceDynTrafficDirectorRecordRead,
+
+		Schema: map[string]*schema.Schema{
+			"traffic_director": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"response_pool": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"record_set": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"label": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			"traffic_director_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"record_set_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"master_line": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"weight": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"endpoints": {
+				Type:     schema.TypeList,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Computed: true,
+			},
+
+			"endpoint_up_count": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"eligible": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+
+			"automation": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceDynTrafficDirectorRecordRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	clientList := meta.(accessControlledClientList)
+	client, err := clientList.AcquireContext(ctx)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+	defer clientList.Release(client)
+
+	idOrLabel, err := dataSourceDynTrafficDirectorLookup(d)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+
+	td, err := getTrafficDirector(client, d.Get("traffic_director").(string))
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+
+	tdrs, tdr, err := findTrafficDirectorRecordInService(td, d.Get("response_pool").(string), d.Get("record_set").(string), idOrLabel)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+
+	d.SetId(tdr.RecordID)
+	d.Set("traffic_director_id", td.ServiceID)
+	d.Set("record_set_id", tdrs.RecordSetID)
+	d.Set("label", tdr.Label)
+	d.Set("master_line", tdr.MasterLine)
+	d.Set("weight", tdr.Weight)
+	d.Set("endpoints", tdr.Endpoints)
+	d.Set("endpoint_up_count", tdr.EndpointUpCount)
+	d.Set("eligible", tdr.Eligible)
+	d.Set("automation", tdr.Automation)
+	d.Set("status", tdr.Status)
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package dyn
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceDynTrafficDirectorRecordSet() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceDynTrafficDirectorRecordSetRead,
+
+		Schema: map[string]*schema.Schema{
+			"traffic_director": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"response_pool": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"label": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			"traffic_director_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"response_pool_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"rdata_class": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"ttl": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"monitor_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"eligible": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+
+			"automation": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"record_ids": {
+				Type:     schema.TypeList,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceDynTrafficDirectorRecordSetRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	clientList := meta.(accessControlledClientList)
+	client, err := clientList.AcquireContext(ctx)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+	defer clientList.Release(client)
+
+	idOrLabel, err := dataSourceDynTrafficDirectorLookup(d)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+
+	td, err := getTrafficDirector(client, d.Get("traffic_director").(string))
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+
+	tdrp, tdrs, err := findTrafficDirectorRecordSetInService(td, d.Get("response_pool").(string), idOrLabel)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+
+	recordIDs := make([]string, len(tdrs.Records))
+	for idx, record := range tdrs.Records {
+		recordIDs[idx] = record.RecordID
+	}
+
+	d.SetId(tdrs.RecordSetID)
+	d.Set("traffic_director_id", td.ServiceID)
+	d.Set("response_pool_id", tdrp.ResponsePoolID)
+	d.Set("label", tdrs.Label)
+	d.Set("rdata_class", tdrs.RDataClass)
+	d.Set("ttl", tdrs.TTL)
+	d.Set("monitor_id", tdrs.MonitorID)
+	d.Set("eligible", tdrs.Eligible)
+	d.Set("automation", tdrs.Automation)
+	d.Set("status", tdrs.Status)
+	d.Set("record_ids", recordIDs)
+
+	return nil
+}
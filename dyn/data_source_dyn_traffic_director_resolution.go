@@ -0,0 +1,154 @@
+package dyn
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceDynTrafficDirectorResolution() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceDynTrafficDirectorResolutionRead,
+
+		Schema: map[string]*schema.Schema{
+			"traffic_director_id": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"country": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateGeolocationCode("country", geolocationCountries, geolocationCountryAliases),
+			},
+
+			"region": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateGeolocationCode("region", geolocationRegions, nil),
+			},
+
+			"province": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateGeolocationCode("province", geolocationProvinces, nil),
+			},
+
+			"ruleset_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"ruleset_label": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"response_pool_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"response_pool_label": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"record_set_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"record_set_label": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"answer": {
+				Type: schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"record_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"label": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"master_line": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"weight": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"probability": {
+							Type:     schema.TypeFloat,
+							Computed: true,
+						},
+					},
+				},
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceDynTrafficDirectorResolutionRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	clientList := meta.(accessControlledClientList)
+	client, err := clientList.AcquireContext(ctx)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+	defer clientList.Release(client)
+
+	tdID := d.Get("traffic_director_id").(string)
+	query := trafficDirectorClient{
+		Country:  d.Get("country").(string),
+		Region:   d.Get("region").(string),
+		Province: d.Get("province").(string),
+	}
+
+	log.Printf("[DEBUG] Getting Traffic Director (%s) to simulate resolution for %+v", tdID, query)
+	td, err := client.GetTrafficDirector(tdID)
+	if err != nil {
+		return resourceDynError(ctx, fmt.Errorf("Couldn't find Dyn Traffic Director: %w", err))
+	}
+
+	resolution := simulateTrafficDirectorResolution(td, query)
+
+	d.SetId(fmt.Sprintf("%s/%s/%s/%s", tdID, query.Country, query.Region, query.Province))
+	d.Set("ruleset_id", "")
+	d.Set("ruleset_label", "")
+	d.Set("response_pool_id", "")
+	d.Set("response_pool_label", "")
+	d.Set("record_set_id", "")
+	d.Set("record_set_label", "")
+	if resolution.RecordSet != nil {
+		d.Set("ruleset_id", resolution.Ruleset.RulesetID)
+		d.Set("ruleset_label", resolution.Ruleset.Label)
+		d.Set("response_pool_id", resolution.ResponsePool.ResponsePoolID)
+		d.Set("response_pool_label", resolution.ResponsePool.Label)
+		d.Set("record_set_id", resolution.RecordSet.RecordSetID)
+		d.Set("record_set_label", resolution.RecordSet.Label)
+	}
+
+	answers := make([]map[string]interface{}, len(resolution.Answers))
+	for idx, answer := range resolution.Answers {
+		answers[idx] = map[string]interface{}{
+			"record_id":   answer.Record.RecordID,
+			"label":       answer.Record.Label,
+			"master_line": answer.Record.MasterLine,
+			"weight":      answer.Record.Weight,
+			"probability": answer.Probability,
+		}
+	}
+	d.Set("answer", answers)
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package dyn
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceDynTrafficDirectorResponsePool() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceDynTrafficDirectorResponsePoolRead,
+
+		Schema: map[string]*schema.Schema{
+			"traffic_director": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"label": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			"traffic_director_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"eligible": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+
+			"automation": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"ruleset_ids": {
+				Type:     schema.TypeList,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Computed: true,
+			},
+
+			"record_set_ids": {
+				Type:     schema.TypeList,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceDynTrafficDirectorResponsePoolRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	clientList := meta.(accessControlledClientList)
+	client, err := clientList.AcquireContext(ctx)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+	defer clientList.Release(client)
+
+	idOrLabel, err := dataSourceDynTrafficDirectorLookup(d)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+
+	td, err := getTrafficDirector(client, d.Get("traffic_director").(string))
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+
+	tdrp, err := findTrafficDirectorResponsePool(td, idOrLabel)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+
+	rulesetIDs := make([]string, 0)
+	for _, ruleset := range sortedTrafficDirectorRulesets(td) {
+		for _, responsePool := range ruleset.ResponsePools {
+			if responsePool.ResponsePoolID == tdrp.ResponsePoolID {
+				rulesetIDs = append(rulesetIDs, ruleset.RulesetID)
+				break
+			}
+		}
+	}
+
+	recordSetIDs := make([]string, len(tdrp.RecordSets))
+	for idx, recordSet := range tdrp.RecordSets {
+		recordSetIDs[idx] = recordSet.RecordSetID
+	}
+
+	d.SetId(tdrp.ResponsePoolID)
+	d.Set("traffic_director_id", td.ServiceID)
+	d.Set("label", tdrp.Label)
+	d.Set("eligible", tdrp.Eligible)
+	d.Set("automation", tdrp.Automation)
+	d.Set("status", tdrp.Status)
+	d.Set("ruleset_ids", rulesetIDs)
+	d.Set("record_set_ids", recordSetIDs)
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package dyn
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceDynTrafficDirectorRuleset() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceDynTrafficDirectorRulesetRead,
+
+		Schema: map[string]*schema.Schema{
+			"traffic_director": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"label": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			"traffic_director_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"criteria_type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"ordering": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"response_pool_ids": {
+				Type:     schema.TypeList,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Computed: true,
+			},
+
+			"geolocation": {
+				Type: schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"region": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"country": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"province": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceDynTrafficDirectorRulesetRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	clientList := meta.(accessControlledClientList)
+	client, err := clientList.AcquireContext(ctx)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+	defer clientList.Release(client)
+
+	idOrLabel, err := dataSourceDynTrafficDirectorLookup(d)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+
+	td, err := getTrafficDirector(client, d.Get("traffic_director").(string))
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+
+	tdrs, err := findTrafficDirectorRuleset(td, idOrLabel)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+
+	responsePoolIDs := make([]string, len(tdrs.ResponsePools))
+	for idx, responsePool := range tdrs.ResponsePools {
+		responsePoolIDs[idx] = responsePool.ResponsePoolID
+	}
+
+	d.SetId(tdrs.RulesetID)
+	d.Set("traffic_director_id", td.ServiceID)
+	d.Set("label", tdrs.Label)
+	d.Set("criteria_type", tdrs.CriteriaType)
+	d.Set("ordering", tdrs.Ordering)
+	d.Set("response_pool_ids", responsePoolIDs)
+	d.Set("geolocation", flattenTrafficDirectorRulesetGeolocation(tdrs))
+
+	return nil
+}
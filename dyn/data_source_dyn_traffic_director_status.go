@@ -0,0 +1,227 @@
+package dyn
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceDynTrafficDirectorStatus() *schema.Resource {
+	statusSchema := func(extra map[string]*schema.Schema) *schema.Schema {
+		fields := map[string]*schema.Schema{
+			"id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"label": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"last_monitored": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"pending_change": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"eligible": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+		}
+		for k, v := range extra {
+			fields[k] = v
+		}
+
+		return &schema.Schema{
+			Type:     schema.TypeList,
+			Elem:     &schema.Resource{Schema: fields},
+			Computed: true,
+		}
+	}
+
+	countsSchema := func() *schema.Schema {
+		return &schema.Schema{
+			Type:     schema.TypeMap,
+			Elem:     &schema.Schema{Type: schema.TypeInt},
+			Computed: true,
+		}
+	}
+
+	return &schema.Resource{
+		ReadContext: dataSourceDynTrafficDirectorStatusRead,
+
+		Schema: map[string]*schema.Schema{
+			"traffic_director_id": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"healthy_statuses": {
+				Type:     schema.TypeSet,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Optional: true,
+			},
+
+			"pending_change": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"response_pool": statusSchema(nil),
+
+			"record_set": statusSchema(map[string]*schema.Schema{
+				"response_pool_id": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"monitor_id": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+			}),
+
+			"record": statusSchema(map[string]*schema.Schema{
+				"record_set_id": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"master_line": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+			}),
+
+			"response_pool_status_counts": countsSchema(),
+			"record_set_status_counts":    countsSchema(),
+			"record_status_counts":        countsSchema(),
+
+			"unhealthy_record_set_ids": {
+				Type:     schema.TypeList,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Computed: true,
+			},
+
+			"healthy": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether every eligible record set, in response pools used by a ruleset or not, has one of the healthy statuses and the service has no pending change. The statuses of response pools and records aren't checked, so a record set that stays up with some of its records down counts as healthy.",
+			},
+		},
+	}
+}
+
+func dataSourceDynTrafficDirectorStatusRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	clientList := meta.(accessControlledClientList)
+	client, err := clientList.AcquireContext(ctx)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+	defer clientList.Release(client)
+
+	tdID := d.Get("traffic_director_id").(string)
+	healthy := d.Get("healthy_statuses").(*schema.Set)
+	if healthy.Len() == 0 {
+		healthy = schema.NewSet(schema.HashString, []interface{}{"ok"})
+	}
+
+	log.Printf("[DEBUG] Getting Traffic Director (%s) status", tdID)
+	td, err := client.GetTrafficDirector(tdID)
+	if err != nil {
+		return resourceDynError(ctx, fmt.Errorf("Couldn't find Dyn Traffic Director: %w", err))
+	}
+	td, err = resourceDynTrafficDirectorWithResponsePools(client, td)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+
+	// Response pools come out of go-dyn in no particular order.
+	sort.Slice(td.ResponsePools, func(i, j int) bool {
+		a, b := td.ResponsePools[i], td.ResponsePools[j]
+		if a.Label != b.Label {
+			return a.Label < b.Label
+		}
+		return a.ResponsePoolID < b.ResponsePoolID
+	})
+
+	responsePools := make([]map[string]interface{}, 0, len(td.ResponsePools))
+	recordSets := make([]map[string]interface{}, 0)
+	records := make([]map[string]interface{}, 0)
+	responsePoolCounts := make(map[string]interface{})
+	recordSetCounts := make(map[string]interface{})
+	recordCounts := make(map[string]interface{})
+	unhealthy := make([]string, 0)
+
+	count := func(counts map[string]interface{}, status string) {
+		if status == "" {
+			status = "unknown"
+		}
+		n, _ := counts[status].(int)
+		counts[status] = n + 1
+	}
+
+	for _, responsePool := range td.ResponsePools {
+		count(responsePoolCounts, responsePool.Status)
+		responsePools = append(responsePools, map[string]interface{}{
+			"id":             responsePool.ResponsePoolID,
+			"label":          responsePool.Label,
+			"status":         responsePool.Status,
+			"last_monitored": responsePool.LastMonitored,
+			"pending_change": responsePool.PendingChange,
+			"eligible":       responsePool.Eligible,
+		})
+
+		for _, recordSet := range responsePool.RecordSets {
+			count(recordSetCounts, recordSet.Status)
+			if recordSet.Eligible && !healthy.Contains(recordSet.Status) {
+				unhealthy = append(unhealthy, recordSet.RecordSetID)
+			}
+			recordSets = append(recordSets, map[string]interface{}{
+				"id":               recordSet.RecordSetID,
+				"label":            recordSet.Label,
+				"status":           recordSet.Status,
+				"last_monitored":   recordSet.LastMonitored,
+				"pending_change":   recordSet.PendingChange,
+				"eligible":         recordSet.Eligible,
+				"response_pool_id": responsePool.ResponsePoolID,
+				"monitor_id":       recordSet.MonitorID,
+			})
+
+			for _, record := range recordSet.Records {
+				count(recordCounts, record.Status)
+				records = append(records, map[string]interface{}{
+					"id":             record.RecordID,
+					"label":          record.Label,
+					"status":         record.Status,
+					"last_monitored": record.LastMonitored,
+					"pending_change": record.PendingChange,
+					"eligible":       record.Eligible,
+					"record_set_id":  recordSet.RecordSetID,
+					"master_line":    record.MasterLine,
+				})
+			}
+		}
+	}
+
+	d.SetId(tdID)
+	d.Set("pending_change", td.PendingChange)
+	d.Set("response_pool", responsePools)
+	d.Set("record_set", recordSets)
+	d.Set("record", records)
+	d.Set("response_pool_status_counts", responsePoolCounts)
+	d.Set("record_set_status_counts", recordSetCounts)
+	d.Set("record_status_counts", recordCounts)
+	d.Set("unhealthy_record_set_ids", unhealthy)
+	d.Set("healthy", len(unhealthy) == 0 && td.PendingChange == "")
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package dyn
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestDataSourceDynTrafficDirectorStatusRead(t *testing.T) {
+	pool := func(id, label, status string) map[string]interface{} {
+		p := testTrafficDirectorClonePool(id, label, "s-"+id, "r-"+id)
+		recordSet := p["rs_chains"].([]interface{})[0].(map[string]interface{})["record_sets"].([]interface{})[0].(map[string]interface{})
+		recordSet["status"] = status
+		recordSet["eligible"] = "true"
+		return p
+	}
+
+	clientList := testDynClientList(t, func(method, resource string, body map[string]interface{}) (interface{}, int) {
+		switch method + " " + resource {
+		case "GET DSF/td":
+			return map[string]interface{}{
+				"service_id": "td",
+				"label":      "td",
+				"rulesets": []interface{}{map[string]interface{}{
+					"dsf_ruleset_id": "ruleset",
+					"label":          "default",
+					"response_pools": []interface{}{map[string]interface{}{"dsf_response_pool_id": "p1", "label": "primary"}},
+				}},
+			}, http.StatusOK
+		case "GET DSFResponsePool/td":
+			return []interface{}{
+				pool("p2", "unused", "down"),
+				pool("p3", "primary", "ok"),
+				pool("p1", "primary", "ok"),
+			}, http.StatusOK
+		}
+		return nil, http.StatusNotFound
+	})
+
+	r := dataSourceDynTrafficDirectorStatus()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"traffic_director_id": "td"})
+	if diags := r.ReadContext(context.Background(), d, clientList); diags.HasError() {
+		t.Fatalf("unexpected read error: %v", diags)
+	}
+
+	for idx, id := range []string{"p1", "p3", "p2"} {
+		if got := d.Get(fmt.Sprintf("response_pool.%d.id", idx)); got != id {
+			t.Errorf("expected response pool %d to be %s, got %v", idx, id, got)
+		}
+	}
+	if unhealthy := d.Get("unhealthy_record_set_ids").([]interface{}); len(unhealthy) != 1 || unhealthy[0] != "s-p2" {
+		t.Errorf("expected the record set of the unused response pool to be unhealthy, got %v", unhealthy)
+	}
+	if d.Get("healthy").(bool) {
+		t.Errorf("expected the service not to be healthy")
+	}
+}
@@ -0,0 +1,143 @@
+package dyn
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+
+	"github.com/Shopify/go-dyn/pkg/dyn"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceDynTrafficDirectors() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceDynTrafficDirectorsRead,
+
+		Schema: map[string]*schema.Schema{
+			"label_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp,
+			},
+
+			"ids": {
+				Type:     schema.TypeList,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Computed: true,
+			},
+
+			"labels": {
+				Type:     schema.TypeList,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Computed: true,
+			},
+
+			"traffic_director": {
+				Type: schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"label": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"active": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"ttl": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"node": {
+							Type: schema.TypeList,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"zone": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"fqdn": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+								},
+							},
+							Computed: true,
+						},
+					},
+				},
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceDynTrafficDirectorsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	clientList := meta.(accessControlledClientList)
+	client, err := clientList.AcquireContext(ctx)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+	defer clientList.Release(client)
+
+	labelRegex := d.Get("label_regex").(string)
+	re, err := regexp.Compile(labelRegex)
+	if err != nil {
+		return resourceDynError(ctx, fmt.Errorf("Failed to parse label_regex: %w", err))
+	}
+
+	log.Printf("[DEBUG] Listing Traffic Directors matching: %s", labelRegex)
+	tds := make([]*dyn.TrafficDirector, 0)
+	if _, err := client.EachTrafficDirector(ctx, func(td *dyn.TrafficDirector) error {
+		if re.MatchString(td.Label) {
+			tds = append(tds, td)
+		}
+		return nil
+	}); err != nil {
+		return resourceDynError(ctx, fmt.Errorf("Couldn't list Dyn Traffic Directors: %w", err))
+	}
+
+	sort.Slice(tds, func(i, j int) bool {
+		if tds[i].Label != tds[j].Label {
+			return tds[i].Label < tds[j].Label
+		}
+		return tds[i].ServiceID < tds[j].ServiceID
+	})
+
+	ids := make([]string, len(tds))
+	labels := make([]string, len(tds))
+	tdList := make([]map[string]interface{}, len(tds))
+	for idx, td := range tds {
+		nodes := make([]map[string]interface{}, len(td.Nodes))
+		for nIdx, node := range td.Nodes {
+			nodes[nIdx] = map[string]interface{}{
+				"zone": node.Zone,
+				"fqdn": node.FQDN,
+			}
+		}
+
+		ids[idx] = td.ServiceID
+		labels[idx] = td.Label
+		tdList[idx] = map[string]interface{}{
+			"id":     td.ServiceID,
+			"label":  td.Label,
+			"active": td.Active,
+			"ttl":    td.TTL,
+			"node":   nodes,
+		}
+	}
+
+	d.SetId(fmt.Sprintf("dyn-traffic-directors/%s", labelRegex))
+	d.Set("ids", ids)
+	d.Set("labels", labels)
+	d.Set("traffic_director", tdList)
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package dyn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Shopify/go-dyn/pkg/dyn"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceDynOperation is what resourceDynError knows about the operation an
+// error comes from. It also collects the warnings the Dyn API gave during the
+// operation, see resourceDynWarningFunc.
+type resourceDynOperation struct {
+	Operation string
+	Kind      string
+	Object    string
+	Timeout   time.Duration
+	Schema    map[string]*schema.Schema
+
+	mutex    sync.Mutex
+	warnings diag.Diagnostics
+}
+
+type resourceDynOperationKey struct{}
+
+// resourceDynOperations wraps the CRUD functions of a resource or data source
+// so that the diagnostics they return with resourceDynError say which
+// operation and object they're about, and include the warnings of the Dyn API.
+// kind names the object, as in "Traffic Director Ruleset".
+func resourceDynOperations(r *schema.Resource, kind string) *schema.Resource {
+	r.CreateContext = resourceDynOperationFunc(r.CreateContext, r.Schema, schema.TimeoutCreate, kind)
+	r.ReadContext = resourceDynOperationFunc(r.ReadContext, r.Schema, schema.TimeoutRead, kind)
+	r.UpdateContext = resourceDynOperationFunc(r.UpdateContext, r.Schema, schema.TimeoutUpdate, kind)
+	r.DeleteContext = resourceDynOperationFunc(r.DeleteContext, r.Schema, schema.TimeoutDelete, kind)
+
+	return r
+}
+
+func resourceDynOperationFunc(f func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics, s map[string]*schema.Schema, operation, kind string) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	if f == nil {
+		return nil
+	}
+
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		// The object is described before the operation, since a failed
+		// create or a delete may clear the ID.
+		op := &resourceDynOperation{
+			Operation: operation,
+			Kind:      kind,
+			Object:    resourceDynTimeoutObject(d),
+			Timeout:   d.Timeout(operation),
+			Schema:    s,
+		}
+
+		diags := f(context.WithValue(ctx, resourceDynOperationKey{}, op), d, meta)
+
+		op.mutex.Lock()
+		defer op.mutex.Unlock()
+
+		return append(diags, op.warnings...)
+	}
+}
+
+// resourceDynWarningFunc returns what a client bound to ctx does with the
+// warnings of the Dyn API: they're logged, and returned as diagnostics of the
+// operation ctx belongs to, if any.
+func resourceDynWarningFunc(ctx context.Context) func(method, resource string, m dyn.Message) {
+	op, _ := ctx.Value(resourceDynOperationKey{}).(*resourceDynOperation)
+
+	return func(method, resource string, m dyn.Message) {
+		log.Printf("[WARN] Dyn API %s %s: %s", method, resource, m.Info)
+
+		if op == nil {
+			return
+		}
+
+		op.mutex.Lock()
+		defer op.mutex.Unlock()
+
+		op.warnings = append(op.warnings, resourceDynAPIWarning(method, resource, m, op.Schema))
+	}
+}
+
+// resourceDynError returns the diagnostics of an error a CRUD function ran
+// into. When the operation ran out of time they say which operation and
+// object it was, and Dyn API errors point at the attribute they're about, see
+// resourceDynAPIError.
+func resourceDynError(ctx context.Context, err error) diag.Diagnostics {
+	if err == nil {
+		return nil
+	}
+
+	op, ok := ctx.Value(resourceDynOperationKey{}).(*resourceDynOperation)
+	if !ok {
+		return diag.Diagnostics{resourceDynAPIError(err, nil)}
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Timed out after %s trying to %s Dyn %s %s", op.Timeout, op.Operation, op.Kind, op.Object),
+			Detail:   err.Error(),
+		}}
+	}
+
+	return diag.Diagnostics{resourceDynAPIError(err, op.Schema)}
+}
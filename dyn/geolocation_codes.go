@@ -0,0 +1,648 @@
+package dyn
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/agext/levenshtein"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// geolocationRegions maps the Dyn Traffic Director region codes to their
+// names.
+var geolocationRegions = map[string]string{
+	"11": "US West",
+	"12": "US Central",
+	"13": "US East",
+	"14": "Asia",
+	"15": "EU West",
+	"16": "EU Central",
+	"17": "EU East",
+	"18": "Australia",
+	"19": "South America",
+	"20": "Africa",
+}
+
+// geolocationProvinces maps the US state and Canadian province codes
+// supported by Dyn Traffic Director to their names.
+var geolocationProvinces = map[string]string{
+	"AL": "Alabama",
+	"AK": "Alaska",
+	"AZ": "Arizona",
+	"AR": "Arkansas",
+	"CA": "California",
+	"CO": "Colorado",
+	"CT": "Connecticut",
+	"DE": "Delaware",
+	"DC": "District of Columbia",
+	"FL": "Florida",
+	"GA": "Georgia",
+	"HI": "Hawaii",
+	"ID": "Idaho",
+	"IL": "Illinois",
+	"IN": "Indiana",
+	"IA": "Iowa",
+	"KS": "Kansas",
+	"KY": "Kentucky",
+	"LA": "Louisiana",
+	"ME": "Maine",
+	"MD": "Maryland",
+	"MA": "Massachusetts",
+	"MI": "Michigan",
+	"MN": "Minnesota",
+	"MS": "Mississippi",
+	"MO": "Missouri",
+	"MT": "Montana",
+	"NE": "Nebraska",
+	"NV": "Nevada",
+	"NH": "New Hampshire",
+	"NJ": "New Jersey",
+	"NM": "New Mexico",
+	"NY": "New York",
+	"NC": "North Carolina",
+	"ND": "North Dakota",
+	"OH": "Ohio",
+	"OK": "Oklahoma",
+	"OR": "Oregon",
+	"PA": "Pennsylvania",
+	"RI": "Rhode Island",
+	"SC": "South Carolina",
+	"SD": "South Dakota",
+	"TN": "Tennessee",
+	"TX": "Texas",
+	"UT": "Utah",
+	"VT": "Vermont",
+	"VA": "Virginia",
+	"WA": "Washington",
+	"WV": "West Virginia",
+	"WI": "Wisconsin",
+	"WY": "Wyoming",
+	"AB": "Alberta",
+	"BC": "British Columbia",
+	"MB": "Manitoba",
+	"NB": "New Brunswick",
+	"NL": "Newfoundland and Labrador",
+	"NS": "Nova Scotia",
+	"NT": "Northwest Territories",
+	"NU": "Nunavut",
+	"ON": "Ontario",
+	"PE": "Prince Edward Island",
+	"QC": "Quebec",
+	"SK": "Saskatchewan",
+	"YT": "Yukon",
+}
+
+// geolocationCountryAliases maps common non-ISO country codes to their ISO
+// 3166-1 equivalent.
+var geolocationCountryAliases = map[string]string{
+	"UK": "GB",
+	"EL": "GR",
+}
+
+// geolocationCountries maps ISO 3166-1 alpha-2 country codes to their names.
+var geolocationCountries = map[string]string{
+	"AD": "Andorra",
+	"AE": "United Arab Emirates",
+	"AF": "Afghanistan",
+	"AG": "Antigua & Barbuda",
+	"AI": "Anguilla",
+	"AL": "Albania",
+	"AM": "Armenia",
+	"AO": "Angola",
+	"AQ": "Antarctica",
+	"AR": "Argentina",
+	"AS": "American Samoa",
+	"AT": "Austria",
+	"AU": "Australia",
+	"AW": "Aruba",
+	"AX": "Åland Islands",
+	"AZ": "Azerbaijan",
+	"BA": "Bosnia & Herzegovina",
+	"BB": "Barbados",
+	"BD": "Bangladesh",
+	"BE": "Belgium",
+	"BF": "Burkina Faso",
+	"BG": "Bulgaria",
+	"BH": "Bahrain",
+	"BI": "Burundi",
+	"BJ": "Benin",
+	"BL": "St Barthelemy",
+	"BM": "Bermuda",
+	"BN": "Brunei",
+	"BO": "Bolivia",
+	"BQ": "Caribbean NL",
+	"BR": "Brazil",
+	"BS": "Bahamas",
+	"BT": "Bhutan",
+	"BV": "Bouvet Island",
+	"BW": "Botswana",
+	"BY": "Belarus",
+	"BZ": "Belize",
+	"CA": "Canada",
+	"CC": "Cocos (Keeling) Islands",
+	"CD": "Congo (Dem. Rep.)",
+	"CF": "Central African Rep.",
+	"CG": "Congo (Rep.)",
+	"CH": "Switzerland",
+	"CI": "Côte d'Ivoire",
+	"CK": "Cook Islands",
+	"CL": "Chile",
+	"CM": "Cameroon",
+	"CN": "China",
+	"CO": "Colombia",
+	"CR": "Costa Rica",
+	"CU": "Cuba",
+	"CV": "Cape Verde",
+	"CW": "Curaçao",
+	"CX": "Christmas Island",
+	"CY": "Cyprus",
+	"CZ": "Czech Republic",
+	"DE": "Germany",
+	"DJ": "Djibouti",
+	"DK": "Denmark",
+	"DM": "Dominica",
+	"DO": "Dominican Republic",
+	"DZ": "Algeria",
+	"EC": "Ecuador",
+	"EE": "Estonia",
+	"EG": "Egypt",
+	"EH": "Western Sahara",
+	"ER": "Eritrea",
+	"ES": "Spain",
+	"ET": "Ethiopia",
+	"FI": "Finland",
+	"FJ": "Fiji",
+	"FK": "Falkland Islands",
+	"FM": "Micronesia",
+	"FO": "Faroe Islands",
+	"FR": "France",
+	"GA": "Gabon",
+	"GB": "United Kingdom",
+	"GD": "Grenada",
+	"GE": "Georgia",
+	"GF": "French Guiana",
+	"GG": "Guernsey",
+	"GH": "Ghana",
+	"GI": "Gibraltar",
+	"GL": "Greenland",
+	"GM": "Gambia",
+	"GN": "Guinea",
+	"GP": "Guadeloupe",
+	"GQ": "Equatorial Guinea",
+	"GR": "Greece",
+	"GS": "South Georgia & the South Sandwich Islands",
+	"GT": "Guatemala",
+	"GU": "Guam",
+	"GW": "Guinea-Bissau",
+	"GY": "Guyana",
+	"HK": "Hong Kong",
+	"HM": "Heard Island & McDonald Islands",
+	"HN": "Honduras",
+	"HR": "Croatia",
+	"HT": "Haiti",
+	"HU": "Hungary",
+	"ID": "Indonesia",
+	"IE": "Ireland",
+	"IL": "Israel",
+	"IM": "Isle of Man",
+	"IN": "India",
+	"IO": "British Indian Ocean Territory",
+	"IQ": "Iraq",
+	"IR": "Iran",
+	"IS": "Iceland",
+	"IT": "Italy",
+	"JE": "Jersey",
+	"JM": "Jamaica",
+	"JO": "Jordan",
+	"JP": "Japan",
+	"KE": "Kenya",
+	"KG": "Kyrgyzstan",
+	"KH": "Cambodia",
+	"KI": "Kiribati",
+	"KM": "Comoros",
+	"KN": "St Kitts & Nevis",
+	"KP": "Korea (North)",
+	"KR": "Korea (South)",
+	"KW": "Kuwait",
+	"KY": "Cayman Islands",
+	"KZ": "Kazakhstan",
+	"LA": "Laos",
+	"LB": "Lebanon",
+	"LC": "St Lucia",
+	"LI": "Liechtenstein",
+	"LK": "Sri Lanka",
+	"LR": "Liberia",
+	"LS": "Lesotho",
+	"LT": "Lithuania",
+	"LU": "Luxembourg",
+	"LV": "Latvia",
+	"LY": "Libya",
+	"MA": "Morocco",
+	"MC": "Monaco",
+	"MD": "Moldova",
+	"ME": "Montenegro",
+	"MF": "St Martin (French)",
+	"MG": "Madagascar",
+	"MH": "Marshall Islands",
+	"MK": "North Macedonia",
+	"ML": "Mali",
+	"MM": "Myanmar (Burma)",
+	"MN": "Mongolia",
+	"MO": "Macau",
+	"MP": "Northern Mariana Islands",
+	"MQ": "Martinique",
+	"MR": "Mauritania",
+	"MS": "Montserrat",
+	"MT": "Malta",
+	"MU": "Mauritius",
+	"MV": "Maldives",
+	"MW": "Malawi",
+	"MX": "Mexico",
+	"MY": "Malaysia",
+	"MZ": "Mozambique",
+	"NA": "Namibia",
+	"NC": "New Caledonia",
+	"NE": "Niger",
+	"NF": "Norfolk Island",
+	"NG": "Nigeria",
+	"NI": "Nicaragua",
+	"NL": "Netherlands",
+	"NO": "Norway",
+	"NP": "Nepal",
+	"NR": "Nauru",
+	"NU": "Niue",
+	"NZ": "New Zealand",
+	"OM": "Oman",
+	"PA": "Panama",
+	"PE": "Peru",
+	"PF": "French Polynesia",
+	"PG": "Papua New Guinea",
+	"PH": "Philippines",
+	"PK": "Pakistan",
+	"PL": "Poland",
+	"PM": "St Pierre & Miquelon",
+	"PN": "Pitcairn",
+	"PR": "Puerto Rico",
+	"PS": "Palestine",
+	"PT": "Portugal",
+	"PW": "Palau",
+	"PY": "Paraguay",
+	"QA": "Qatar",
+	"RE": "Réunion",
+	"RO": "Romania",
+	"RS": "Serbia",
+	"RU": "Russia",
+	"RW": "Rwanda",
+	"SA": "Saudi Arabia",
+	"SB": "Solomon Islands",
+	"SC": "Seychelles",
+	"SD": "Sudan",
+	"SE": "Sweden",
+	"SG": "Singapore",
+	"SH": "St Helena",
+	"SI": "Slovenia",
+	"SJ": "Svalbard & Jan Mayen",
+	"SK": "Slovakia",
+	"SL": "Sierra Leone",
+	"SM": "San Marino",
+	"SN": "Senegal",
+	"SO": "Somalia",
+	"SR": "Suriname",
+	"SS": "South Sudan",
+	"ST": "Sao Tome & Principe",
+	"SV": "El Salvador",
+	"SX": "St Maarten (Dutch)",
+	"SY": "Syria",
+	"SZ": "Eswatini (Swaziland)",
+	"TC": "Turks & Caicos Islands",
+	"TD": "Chad",
+	"TF": "French S. Terr.",
+	"TG": "Togo",
+	"TH": "Thailand",
+	"TJ": "Tajikistan",
+	"TK": "Tokelau",
+	"TL": "East Timor",
+	"TM": "Turkmenistan",
+	"TN": "Tunisia",
+	"TO": "Tonga",
+	"TR": "Turkey",
+	"TT": "Trinidad & Tobago",
+	"TV": "Tuvalu",
+	"TW": "Taiwan",
+	"TZ": "Tanzania",
+	"UA": "Ukraine",
+	"UG": "Uganda",
+	"UM": "US minor outlying islands",
+	"US": "United States",
+	"UY": "Uruguay",
+	"UZ": "Uzbekistan",
+	"VA": "Vatican City",
+	"VC": "St Vincent",
+	"VE": "Venezuela",
+	"VG": "Virgin Islands (UK)",
+	"VI": "Virgin Islands (US)",
+	"VN": "Vietnam",
+	"VU": "Vanuatu",
+	"WF": "Wallis & Futuna",
+	"WS": "Samoa",
+	"YE": "Yemen",
+	"YT": "Mayotte",
+	"ZA": "South Africa",
+	"ZM": "Zambia",
+	"ZW": "Zimbabwe",
+}
+
+// geolocationCountryRegions maps country codes to the Dyn Traffic Director
+// region they're part of, so that clients only known by their country can be
+// matched against region rulesets. Countries that Dyn splits between regions
+// or doesn't place in any, like the United States, Canada and the rest of
+// North and Central America, aren't listed.
+var geolocationCountryRegions = map[string]string{
+	"AD": "15",
+	"AE": "14",
+	"AF": "14",
+	"AL": "17",
+	"AM": "14",
+	"AO": "20",
+	"AR": "19",
+	"AS": "18",
+	"AT": "16",
+	"AU": "18",
+	"AX": "16",
+	"AZ": "14",
+	"BA": "17",
+	"BD": "14",
+	"BE": "15",
+	"BF": "20",
+	"BG": "17",
+	"BH": "14",
+	"BI": "20",
+	"BJ": "20",
+	"BN": "14",
+	"BO": "19",
+	"BR": "19",
+	"BT": "14",
+	"BW": "20",
+	"BY": "17",
+	"CC": "14",
+	"CD": "20",
+	"CF": "20",
+	"CG": "20",
+	"CH": "16",
+	"CI": "20",
+	"CK": "18",
+	"CL": "19",
+	"CM": "20",
+	"CN": "14",
+	"CO": "19",
+	"CV": "20",
+	"CX": "14",
+	"CY": "17",
+	"CZ": "17",
+	"DE": "16",
+	"DJ": "20",
+	"DK": "16",
+	"DZ": "20",
+	"EC": "19",
+	"EE": "17",
+	"EG": "20",
+	"EH": "20",
+	"ER": "20",
+	"ES": "15",
+	"ET": "20",
+	"FI": "16",
+	"FJ": "18",
+	"FK": "19",
+	"FM": "18",
+	"FO": "15",
+	"FR": "15",
+	"GA": "20",
+	"GB": "15",
+	"GE": "14",
+	"GF": "19",
+	"GG": "15",
+	"GH": "20",
+	"GI": "15",
+	"GM": "20",
+	"GN": "20",
+	"GQ": "20",
+	"GR": "17",
+	"GS": "19",
+	"GU": "18",
+	"GW": "20",
+	"GY": "19",
+	"HK": "14",
+	"HR": "16",
+	"HU": "17",
+	"ID": "14",
+	"IE": "15",
+	"IL": "14",
+	"IM": "15",
+	"IN": "14",
+	"IO": "14",
+	"IQ": "14",
+	"IR": "14",
+	"IS": "15",
+	"IT": "16",
+	"JE": "15",
+	"JO": "14",
+	"JP": "14",
+	"KE": "20",
+	"KG": "14",
+	"KH": "14",
+	"KI": "18",
+	"KM": "20",
+	"KP": "14",
+	"KR": "14",
+	"KW": "14",
+	"KZ": "14",
+	"LA": "14",
+	"LB": "14",
+	"LI": "16",
+	"LK": "14",
+	"LR": "20",
+	"LS": "20",
+	"LT": "17",
+	"LU": "15",
+	"LV": "17",
+	"LY": "20",
+	"MA": "20",
+	"MC": "15",
+	"MD": "17",
+	"ME": "17",
+	"MG": "20",
+	"MH": "18",
+	"MK": "17",
+	"ML": "20",
+	"MM": "14",
+	"MN": "14",
+	"MO": "14",
+	"MP": "18",
+	"MR": "20",
+	"MT": "16",
+	"MU": "20",
+	"MV": "14",
+	"MW": "20",
+	"MY": "14",
+	"MZ": "20",
+	"NA": "20",
+	"NC": "18",
+	"NE": "20",
+	"NF": "18",
+	"NG": "20",
+	"NL": "15",
+	"NO": "16",
+	"NP": "14",
+	"NR": "18",
+	"NU": "18",
+	"NZ": "18",
+	"OM": "14",
+	"PE": "19",
+	"PF": "18",
+	"PG": "18",
+	"PH": "14",
+	"PK": "14",
+	"PL": "17",
+	"PN": "18",
+	"PS": "14",
+	"PT": "15",
+	"PW": "18",
+	"PY": "19",
+	"QA": "14",
+	"RE": "20",
+	"RO": "17",
+	"RS": "17",
+	"RU": "17",
+	"RW": "20",
+	"SA": "14",
+	"SB": "18",
+	"SC": "20",
+	"SD": "20",
+	"SE": "16",
+	"SG": "14",
+	"SH": "20",
+	"SI": "16",
+	"SJ": "16",
+	"SK": "17",
+	"SL": "20",
+	"SM": "16",
+	"SN": "20",
+	"SO": "20",
+	"SR": "19",
+	"SS": "20",
+	"ST": "20",
+	"SY": "14",
+	"SZ": "20",
+	"TD": "20",
+	"TG": "20",
+	"TH": "14",
+	"TJ": "14",
+	"TK": "18",
+	"TL": "14",
+	"TM": "14",
+	"TN": "20",
+	"TO": "18",
+	"TR": "17",
+	"TV": "18",
+	"TW": "14",
+	"TZ": "20",
+	"UA": "17",
+	"UG": "20",
+	"UM": "18",
+	"UY": "19",
+	"UZ": "14",
+	"VA": "16",
+	"VE": "19",
+	"VN": "14",
+	"VU": "18",
+	"WF": "18",
+	"WS": "18",
+	"YE": "14",
+	"YT": "20",
+	"ZA": "20",
+	"ZM": "20",
+	"ZW": "20",
+}
+
+// geolocationCountryRegion returns the region code of a country code, or an
+// empty string when the country isn't part of a single region.
+func geolocationCountryRegion(country string) string {
+	country = strings.ToUpper(country)
+	if alias, ok := geolocationCountryAliases[country]; ok {
+		country = alias
+	}
+
+	return geolocationCountryRegions[country]
+}
+
+// validateGeolocationCode returns a SchemaValidateFunc which checks that the
+// value is one of the given codes, suggesting the closest code otherwise.
+// Empty values are accepted.
+func validateGeolocationCode(kind string, codes map[string]string, aliases map[string]string) schema.SchemaValidateFunc {
+	return func(i interface{}, k string) (s []string, es []error) {
+		v, ok := i.(string)
+		if !ok {
+			es = append(es, fmt.Errorf("expected type of %s to be string", k))
+			return
+		}
+
+		if v == "" {
+			return
+		}
+		if _, ok := codes[v]; ok {
+			return
+		}
+
+		if suggestion := geolocationCodeSuggestion(v, codes, aliases); suggestion != "" {
+			es = append(es, fmt.Errorf("%s: %q is not a valid Dyn %s code, did you mean %q (%s)?", k, v, kind, suggestion, codes[suggestion]))
+		} else {
+			es = append(es, fmt.Errorf("%s: %q is not a valid Dyn %s code", k, v, kind))
+		}
+		return
+	}
+}
+
+// geolocationCodeSuggestion returns the code the given value most likely
+// meant, either because it's a known alias, a code with the wrong case, or
+// (close to) the name of a code. It returns the empty string otherwise.
+func geolocationCodeSuggestion(given string, codes map[string]string, aliases map[string]string) string {
+	upper := strings.ToUpper(given)
+	if code, ok := aliases[upper]; ok {
+		return code
+	}
+	if _, ok := codes[upper]; ok {
+		return upper
+	}
+
+	codesByName := make(map[string]string, len(codes))
+	names := make([]string, 0, len(codes))
+	for code, name := range codes {
+		codesByName[strings.ToLower(name)] = code
+		names = append(names, strings.ToLower(name))
+	}
+	sort.Strings(names)
+
+	lower := strings.ToLower(given)
+	if code, ok := codesByName[lower]; ok {
+		return code
+	}
+	for _, name := range names {
+		// The same threshold Terraform uses for its own suggestions.
+		if levenshtein.Distance(lower, name, nil) < 3 {
+			return codesByName[name]
+		}
+	}
+
+	return ""
+}
+
+// geolocationCodesByName inverts a code table, mapping names to codes.
+func geolocationCodesByName(codes map[string]string) map[string]string {
+	byName := make(map[string]string, len(codes))
+	for code, name := range codes {
+		byName[name] = code
+	}
+
+	return byName
+}
@@ -0,0 +1,64 @@
+package dyn
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateGeolocationCode(t *testing.T) {
+	validateCountry := validateGeolocationCode("country", geolocationCountries, geolocationCountryAliases)
+	validateRegion := validateGeolocationCode("region", geolocationRegions, nil)
+
+	cases := []struct {
+		validate   func(interface{}, string) ([]string, []error)
+		value      string
+		suggestion string
+	}{
+		{validateCountry, "GB", ""},
+		{validateCountry, "", ""},
+		{validateCountry, "UK", `"GB"`},
+		{validateCountry, "de", `"DE"`},
+		{validateCountry, "Germnay", `"DE"`},
+		{validateCountry, "ZZ", "-"},
+		{validateRegion, "15", ""},
+		{validateRegion, "EU West", `"15"`},
+	}
+
+	for _, tc := range cases {
+		_, errs := tc.validate(tc.value, "geolocation")
+		if tc.suggestion == "" {
+			if len(errs) > 0 {
+				t.Errorf("%q: unexpected errors: %v", tc.value, errs)
+			}
+			continue
+		}
+
+		if len(errs) != 1 {
+			t.Fatalf("%q: expected one error, got %v", tc.value, errs)
+		}
+		if tc.suggestion == "-" {
+			if strings.Contains(errs[0].Error(), "did you mean") {
+				t.Errorf("%q: expected no suggestion, got %s", tc.value, errs[0])
+			}
+		} else if !strings.Contains(errs[0].Error(), "did you mean "+tc.suggestion) {
+			t.Errorf("%q: expected suggestion %s, got %s", tc.value, tc.suggestion, errs[0])
+		}
+	}
+}
+
+func TestGeolocationCountryRegions(t *testing.T) {
+	for country, region := range geolocationCountryRegions {
+		if _, ok := geolocationCountries[country]; !ok {
+			t.Errorf("unknown country %s", country)
+		}
+		if _, ok := geolocationRegions[region]; !ok {
+			t.Errorf("unknown region %s for country %s", region, country)
+		}
+	}
+
+	for country, region := range map[string]string{"de": "16", "UK": "15", "JP": "14", "US": ""} {
+		if r := geolocationCountryRegion(country); r != region {
+			t.Errorf("expected %s to be in region %q, got %q", country, region, r)
+		}
+	}
+}
@@ -1,14 +1,15 @@
 package dyn
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
-	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/nesv/go-dynect/dynect"
 )
 
-func resourceDynRecordImportState(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceDynRecordImportState(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	results := make([]*schema.ResourceData, 1, 1)
 
 	client := meta.(*dynect.ConvenientClient)
@@ -1,18 +1,19 @@
 package dyn
 
 import (
-	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/hashicorp/terraform/terraform"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	"github.com/Shopify/go-dyn/pkg/dyn"
 
+	"context"
 	"fmt"
 	"log"
 	"sync"
 )
 
-// Provider returns a terraform.ResourceProvider.
-func Provider() terraform.ResourceProvider {
+// Provider returns a *schema.Provider.
+func Provider() *schema.Provider {
 	return &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"customer_name": {
@@ -45,32 +46,74 @@ func Provider() terraform.ResourceProvider {
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
-			"dyn_traffic_director_monitor": dataSourceDynTrafficDirectorMonitor(),
+			"dyn_traffic_director":               resourceDynOperations(dataSourceDynTrafficDirector(), "Traffic Director"),
+			"dyn_traffic_director_ruleset":       resourceDynOperations(dataSourceDynTrafficDirectorRuleset(), "Traffic Director Ruleset"),
+			"dyn_traffic_director_response_pool": resourceDynOperations(dataSourceDynTrafficDirectorResponsePool(), "Traffic Director Response Pool"),
+			"dyn_traffic_director_record_set":    resourceDynOperations(dataSourceDynTrafficDirectorRecordSet(), "Traffic Director Record Set"),
+			"dyn_traffic_director_record":        resourceDynOperations(dataSourceDynTrafficDirectorRecord(), "Traffic Director Record"),
+			"dyn_traffic_directors":              resourceDynOperations(dataSourceDynTrafficDirectors(), "Traffic Directors"),
+			"dyn_traffic_director_monitors":      resourceDynOperations(dataSourceDynTrafficDirectorMonitors(), "Traffic Director Monitors"),
+			"dyn_traffic_director_monitor":       resourceDynOperations(dataSourceDynTrafficDirectorMonitor(), "Traffic Director Monitor"),
+			"dyn_geolocation_codes":              resourceDynOperations(dataSourceDynGeolocationCodes(), "Geolocation Codes"),
+			"dyn_traffic_director_resolution":    resourceDynOperations(dataSourceDynTrafficDirectorResolution(), "Traffic Director Resolution"),
+			"dyn_traffic_director_lint":          resourceDynOperations(dataSourceDynTrafficDirectorLint(), "Traffic Director Lint"),
+			"dyn_traffic_director_status":        resourceDynOperations(dataSourceDynTrafficDirectorStatus(), "Traffic Director Status"),
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
-			//"dyn_record": resourceDynRecord(),
-			"dyn_traffic_director":               resourceDynTrafficDirector(),
-			"dyn_traffic_director_response_pool": resourceDynTrafficDirectorResponsePool(),
-			"dyn_traffic_director_ruleset":       resourceDynTrafficDirectorRuleset(),
-			"dyn_traffic_director_record_set":    resourceDynTrafficDirectorRecordSet(),
-			"dyn_traffic_director_record":        resourceDynTrafficDirectorRecord(),
-			"dyn_traffic_director_monitor":       resourceDynTrafficDirectorMonitor(),
+			//"dyn_record": resourceDynTimeouts(resourceDynRecord(), "Record"),
+			"dyn_traffic_director":               resourceDynTimeouts(resourceDynTrafficDirector(), "Traffic Director"),
+			"dyn_traffic_director_response_pool": resourceDynTimeouts(resourceDynTrafficDirectorResponsePool(), "Traffic Director Response Pool"),
+			"dyn_traffic_director_ruleset":       resourceDynTimeouts(resourceDynTrafficDirectorRuleset(), "Traffic Director Ruleset"),
+			"dyn_traffic_director_record_set":    resourceDynTimeouts(resourceDynTrafficDirectorRecordSet(), "Traffic Director Record Set"),
+			"dyn_traffic_director_record":        resourceDynTimeouts(resourceDynTrafficDirectorRecord(), "Traffic Director Record"),
+			"dyn_traffic_director_monitor":       resourceDynTimeouts(resourceDynTrafficDirectorMonitor(), "Traffic Director Monitor"),
+			"dyn_traffic_director_maintenance":   resourceDynTimeouts(resourceDynTrafficDirectorMaintenance(), "Traffic Director Maintenance"),
+			"dyn_traffic_director_ruleset_order": resourceDynTimeouts(resourceDynTrafficDirectorRulesetOrder(), "Traffic Director Ruleset Order"),
+			"dyn_traffic_director_node":          resourceDynTimeouts(resourceDynTrafficDirectorNode(), "Traffic Director Node"),
+			"dyn_traffic_director_service":       resourceDynTimeouts(resourceDynTrafficDirectorService(), "Traffic Director Service"),
+			"dyn_traffic_director_json":          resourceDynTimeouts(resourceDynTrafficDirectorJSON(), "Traffic Director JSON"),
+			"dyn_traffic_director_clone":         resourceDynTimeouts(resourceDynTrafficDirectorClone(), "Traffic Director Clone"),
+			"dyn_traffic_director_weight_shift":  resourceDynTimeouts(resourceDynTrafficDirectorWeightShift(), "Traffic Director Weight Shift"),
 		},
 
-		ConfigureFunc: providerConfigure,
+		ConfigureContextFunc: providerConfigure,
 	}
 }
 
 type accessControlledClientList struct {
-	Mutex     *sync.Mutex
-	Semaphore chan int
-	Clients   []*dyn.Client
+	Mutex            *sync.Mutex
+	Semaphore        chan int
+	Clients          []*dyn.Client
+	TrafficDirectors *trafficDirectorCache
+
+	// StopContext is cancelled when Terraform stops the provider. The
+	// contexts Terraform gives operations aren't, so clients are bound to
+	// both.
+	StopContext context.Context
 }
 
 func (acc accessControlledClientList) Acquire() (*dyn.Client, error) {
+	ctx := acc.StopContext
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return acc.AcquireContext(ctx)
+}
+
+// AcquireContext waits for a free client until ctx is done, and returns it
+// bound to ctx so that its calls are abandoned along with the operation, or
+// when Terraform stops the provider.
+func (acc accessControlledClientList) AcquireContext(ctx context.Context) (*dyn.Client, error) {
+	ctx = acc.stoppable(ctx)
+
 	log.Printf("[DEBUG] Trying to acquire token to grab a client")
-	acc.Semaphore <- 1
+	select {
+	case acc.Semaphore <- 1:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("Couldn't acquire a Dyn client: %w", ctx.Err())
+	}
 	log.Printf("[DEBUG] Token acquired, will now try to find a free client")
 
 	acc.Mutex.Lock()
@@ -92,7 +135,24 @@ func (acc accessControlledClientList) Acquire() (*dyn.Client, error) {
 	}
 	log.Printf("[DEBUG] Grabbed client %#v", acquiredClient)
 
-	return acquiredClient, nil
+	client := acquiredClient.WithContext(ctx)
+	client.OnWarning = resourceDynWarningFunc(ctx)
+
+	return client, nil
+}
+
+// stoppable returns a context that's done when ctx is, or when Terraform stops
+// the provider.
+func (acc accessControlledClientList) stoppable(ctx context.Context) context.Context {
+	if acc.StopContext == nil || acc.StopContext == ctx {
+		return ctx
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(acc.StopContext, cancel)
+	context.AfterFunc(ctx, func() { stop() })
+
+	return ctx
 }
 
 func (acc accessControlledClientList) Release(acquiredClient *dyn.Client) error {
@@ -119,7 +179,7 @@ func (acc accessControlledClientList) Release(acquiredClient *dyn.Client) error
 	return nil
 }
 
-func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 	config := Config{
 		CustomerName: d.Get("customer_name").(string),
 		Username:     d.Get("username").(string),
@@ -131,15 +191,22 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	instances := d.Get("instances").(int)
 
 	clientsList := accessControlledClientList{
-		Mutex:     &sync.Mutex{},
-		Semaphore: make(chan int, instances),
-		Clients:   make([]*dyn.Client, instances),
+		Mutex:            &sync.Mutex{},
+		Semaphore:        make(chan int, instances),
+		Clients:          make([]*dyn.Client, instances),
+		TrafficDirectors: newTrafficDirectorCache(),
+	}
+
+	// The context given here is only good while the provider is being
+	// configured, but the SDK hands out one that lasts until it's stopped.
+	if stopContext, ok := schema.StopContext(ctx); ok {
+		clientsList.StopContext = stopContext
 	}
 
 	for i := 0; i < instances; i++ {
-		client, err := config.Client()
+		client, err := config.Client(ctx)
 		if err != nil {
-			return nil, err
+			return nil, diag.FromErr(err)
 		}
 		clientsList.Clients[i] = client
 	}
@@ -1,33 +1,32 @@
 package dyn
 
 import (
+	"context"
 	"os"
+	"sync"
 	"testing"
+	"time"
 
-	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/hashicorp/terraform/terraform"
+	"github.com/Shopify/go-dyn/pkg/dyn"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
-var testAccProviders map[string]terraform.ResourceProvider
+var testAccProviders map[string]*schema.Provider
 var testAccProvider *schema.Provider
 
 func init() {
-	testAccProvider = Provider().(*schema.Provider)
-	testAccProviders = map[string]terraform.ResourceProvider{
+	testAccProvider = Provider()
+	testAccProviders = map[string]*schema.Provider{
 		"dyn": testAccProvider,
 	}
 }
 
 func TestProvider(t *testing.T) {
-	if err := Provider().(*schema.Provider).InternalValidate(); err != nil {
+	if err := Provider().InternalValidate(); err != nil {
 		t.Fatalf("err: %s", err)
 	}
 }
 
-func TestProvider_impl(t *testing.T) {
-	var _ terraform.ResourceProvider = Provider()
-}
-
 func testAccPreCheck(t *testing.T) {
 	if v := os.Getenv("DYN_CUSTOMER_NAME"); v == "" {
 		t.Fatal("DYN_CUSTOMER_NAME must be set for acceptance tests")
@@ -45,3 +44,48 @@ func testAccPreCheck(t *testing.T) {
 		t.Fatal("DYN_ZONE must be set for acceptance tests. The domain is used to ` and destroy record against.")
 	}
 }
+
+func TestAcquireContext(t *testing.T) {
+	clientList := accessControlledClientList{
+		Mutex:     &sync.Mutex{},
+		Semaphore: make(chan int, 1),
+		Clients:   []*dyn.Client{dyn.NewClient()},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	client, err := clientList.AcquireContext(ctx)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if client.Context() != ctx {
+		t.Fatalf("expected the client to be bound to the operation's context")
+	}
+	clientList.Release(client)
+
+	stopCtx, stop := context.WithCancel(context.Background())
+	clientList.StopContext = stopCtx
+
+	client, err = clientList.AcquireContext(ctx)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	stop()
+	select {
+	case <-client.Context().Done():
+	case <-time.After(time.Second):
+		t.Fatalf("expected the client to be cancelled when the provider is stopped")
+	}
+
+	waitCtx, waitCancel := context.WithTimeout(ctx, time.Millisecond)
+	defer waitCancel()
+	if _, err := clientList.AcquireContext(waitCtx); err == nil {
+		t.Fatalf("expected to give up waiting for a client")
+	}
+
+	clientList.Release(client)
+	if _, err := clientList.AcquireContext(ctx); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
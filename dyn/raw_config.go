@@ -0,0 +1,12 @@
+package dyn
+
+import "github.com/hashicorp/go-cty/cty"
+
+// resourceDynConfigured reports whether the attribute at path is set in the
+// configuration of the resource, as given by GetRawConfig. Optional and
+// computed attributes read back from Dyn look the same as configured ones
+// through Get, this tells them apart so that only what's configured is sent.
+func resourceDynConfigured(config cty.Value, path cty.Path) bool {
+	v, err := path.Apply(config)
+	return err == nil && !v.IsNull()
+}
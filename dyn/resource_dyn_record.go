@@ -1,12 +1,14 @@
 package dyn
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
 	"sync"
 
-	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/nesv/go-dynect/dynect"
 )
 
@@ -14,12 +16,12 @@ var mutex = &sync.Mutex{}
 
 func resourceDynRecord() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceDynRecordCreate,
-		Read:   resourceDynRecordRead,
-		Update: resourceDynRecordUpdate,
-		Delete: resourceDynRecordDelete,
+		CreateContext: resourceDynRecordCreate,
+		ReadContext:   resourceDynRecordRead,
+		UpdateContext: resourceDynRecordUpdate,
+		DeleteContext: resourceDynRecordDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceDynRecordImportState,
+			StateContext: resourceDynRecordImportState,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -83,7 +85,7 @@ func resourceDynRecord() *schema.Resource {
 	}
 }
 
-func resourceDynRecordCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceDynRecordCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	mutex.Lock()
 
 	client := meta.(*dynect.ConvenientClient)
@@ -101,29 +103,29 @@ func resourceDynRecordCreate(d *schema.ResourceData, meta interface{}) error {
 	err := client.CreateRecord(record)
 	if err != nil {
 		mutex.Unlock()
-		return fmt.Errorf("Failed to create Dyn record: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Failed to create Dyn record: %w", err))
 	}
 
 	// publish the zone
 	err = client.PublishZone(record.Zone)
 	if err != nil {
 		mutex.Unlock()
-		return fmt.Errorf("Failed to publish Dyn zone: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Failed to publish Dyn zone: %w", err))
 	}
 
 	// get the record ID
 	err = client.GetRecordID(record)
 	if err != nil {
 		mutex.Unlock()
-		return fmt.Errorf("%s", err)
+		return resourceDynError(ctx, fmt.Errorf("%w", err))
 	}
 	d.SetId(record.ID)
 
 	mutex.Unlock()
-	return resourceDynRecordRead(d, meta)
+	return resourceDynRecordRead(ctx, d, meta)
 }
 
-func resourceDynRecordRead(d *schema.ResourceData, meta interface{}) error {
+func resourceDynRecordRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	mutex.Lock()
 	defer mutex.Unlock()
 
@@ -140,7 +142,7 @@ func resourceDynRecordRead(d *schema.ResourceData, meta interface{}) error {
 
 	err := client.GetRecord(record)
 	if err != nil {
-		return fmt.Errorf("Couldn't find Dyn record: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Couldn't find Dyn record: %w", err))
 	}
 
 	d.Set("zone", record.Zone)
@@ -153,7 +155,7 @@ func resourceDynRecordRead(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
-func resourceDynRecordUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceDynRecordUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	mutex.Lock()
 
 	client := meta.(*dynect.ConvenientClient)
@@ -172,29 +174,29 @@ func resourceDynRecordUpdate(d *schema.ResourceData, meta interface{}) error {
 	err := client.UpdateRecord(record)
 	if err != nil {
 		mutex.Unlock()
-		return fmt.Errorf("Failed to update Dyn record: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Failed to update Dyn record: %w", err))
 	}
 
 	// publish the zone
 	err = client.PublishZone(record.Zone)
 	if err != nil {
 		mutex.Unlock()
-		return fmt.Errorf("Failed to publish Dyn zone: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Failed to publish Dyn zone: %w", err))
 	}
 
 	// get the record ID
 	err = client.GetRecordID(record)
 	if err != nil {
 		mutex.Unlock()
-		return fmt.Errorf("%s", err)
+		return resourceDynError(ctx, fmt.Errorf("%w", err))
 	}
 	d.SetId(record.ID)
 
 	mutex.Unlock()
-	return resourceDynRecordRead(d, meta)
+	return resourceDynRecordRead(ctx, d, meta)
 }
 
-func resourceDynRecordDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceDynRecordDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	mutex.Lock()
 	defer mutex.Unlock()
 
@@ -213,13 +215,13 @@ func resourceDynRecordDelete(d *schema.ResourceData, meta interface{}) error {
 	// delete the record
 	err := client.DeleteRecord(record)
 	if err != nil {
-		return fmt.Errorf("Failed to delete Dyn record: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Failed to delete Dyn record: %w", err))
 	}
 
 	// publish the zone
 	err = client.PublishZone(record.Zone)
 	if err != nil {
-		return fmt.Errorf("Failed to publish Dyn zone: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Failed to publish Dyn zone: %w", err))
 	}
 
 	return nil
@@ -1,24 +1,30 @@
 package dyn
 
 import (
+	"context"
 	"fmt"
 	"log"
 
 	"github.com/Shopify/go-dyn/pkg/dyn"
-	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func resourceDynTrafficDirector() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceDynTrafficDirectorCreate,
-		Read:   resourceDynTrafficDirectorRead,
-		Update: resourceDynTrafficDirectorUpdate,
-		Delete: resourceDynTrafficDirectorDelete,
+	r := &schema.Resource{
+		CreateContext: resourceDynTrafficDirectorCreate,
+		ReadContext:   resourceDynTrafficDirectorRead,
+		UpdateContext: resourceDynTrafficDirectorUpdate,
+		DeleteContext: resourceDynTrafficDirectorDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceDynTrafficDirectorImportState,
+			StateContext: resourceDynTrafficDirectorImportState,
 		},
 
+		SchemaVersion: 1,
+
 		Schema: map[string]*schema.Schema{
+			"on_existing": onExistingSchema(),
+
 			"label": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -29,24 +35,116 @@ func resourceDynTrafficDirector() *schema.Resource {
 				Optional: true,
 			},
 
-			"node": {
-				Type: schema.TypeList,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"zone": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-						"fqdn": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-					},
-				},
+			"active": {
+				Type:     schema.TypeBool,
 				Optional: true,
+				Default:  true,
 			},
+
+			"node": trafficDirectorNodeSchema(),
 		},
 	}
+	r.StateUpgraders = trafficDirectorStateUpgraders(r)
+
+	return r
+}
+
+// trafficDirectorNodeSchema returns the node block of the resources that
+// manage a whole service. The block only owns the nodes it lists: those are
+// attached and detached one by one, and nodes attached otherwise, like with
+// dyn_traffic_director_node, are left alone and aren't read back into it. A
+// node shouldn't be both listed here and attached with
+// dyn_traffic_director_node, or each would detach it when the other is
+// changed.
+func trafficDirectorNodeSchema() *schema.Schema {
+	return &schema.Schema{
+		Type: schema.TypeSet,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"zone": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"fqdn": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+			},
+		},
+		Optional: true,
+	}
+}
+
+// flattenTrafficDirectorNodes returns the nodes of a service that are in
+// declared, or all of them when declared is nil.
+func flattenTrafficDirectorNodes(nodes []dyn.TrafficDirectorNode, declared *schema.Set) []interface{} {
+	flattened := make([]interface{}, 0, len(nodes))
+	for _, node := range nodes {
+		m := map[string]interface{}{
+			"zone": node.Zone,
+			"fqdn": node.FQDN,
+		}
+		if declared == nil || declared.Contains(m) {
+			flattened = append(flattened, m)
+		}
+	}
+
+	return flattened
+}
+
+// trafficDirectorNodeChanges returns the nodes to attach and detach to go
+// from the old to the new node block, given those attached to the service.
+func trafficDirectorNodeChanges(oldNodes, newNodes *schema.Set, live []dyn.TrafficDirectorNode) ([]dyn.TrafficDirectorNode, []dyn.TrafficDirectorNode) {
+	attached := schema.NewSet(oldNodes.F, flattenTrafficDirectorNodes(live, nil))
+	expand := func(s *schema.Set) []dyn.TrafficDirectorNode {
+		nodes := make([]dyn.TrafficDirectorNode, 0, s.Len())
+		for _, mInterface := range s.List() {
+			m := mInterface.(map[string]interface{})
+			nodes = append(nodes, dyn.TrafficDirectorNode{Zone: m["zone"].(string), FQDN: m["fqdn"].(string)})
+		}
+		return nodes
+	}
+
+	attach := newNodes.Difference(attached)
+	detach := oldNodes.Difference(newNodes).Intersection(attached)
+
+	return expand(attach), expand(detach)
+}
+
+// resourceDynTrafficDirectorUpdateNodes attaches and detaches the nodes that
+// were added to or removed from the node block.
+func resourceDynTrafficDirectorUpdateNodes(clientList accessControlledClientList, client *dyn.Client, d *schema.ResourceData) error {
+	if !d.HasChange("node") {
+		return nil
+	}
+
+	live, err := client.GetTrafficDirectorNodes(d.Id())
+	if err != nil {
+		return fmt.Errorf("Couldn't get Dyn Traffic Director (%s) Nodes: %w", d.Id(), err)
+	}
+
+	oldNodes, newNodes := d.GetChange("node")
+	attach, detach := trafficDirectorNodeChanges(oldNodes.(*schema.Set), newNodes.(*schema.Set), live)
+
+	for _, node := range detach {
+		log.Printf("[DEBUG] Detaching Traffic Director (%s) Node: zone: %s; fqdn: %s", d.Id(), node.Zone, node.FQDN)
+		err := client.DeleteTrafficDirectorNode(d.Id(), node.Zone, node.FQDN)
+		clientList.InvalidateTrafficDirector(d.Id())
+		if err != nil {
+			return fmt.Errorf("Failed to detach Dyn Traffic Director (%s) Node (%s): %w", d.Id(), node.FQDN, err)
+		}
+	}
+
+	for _, node := range attach {
+		log.Printf("[DEBUG] Attaching Traffic Director (%s) Node: zone: %s; fqdn: %s", d.Id(), node.Zone, node.FQDN)
+		_, err := client.AddTrafficDirectorNode(d.Id(), node.Zone, node.FQDN)
+		clientList.InvalidateTrafficDirector(d.Id())
+		if err != nil {
+			return fmt.Errorf("Failed to attach Dyn Traffic Director (%s) Node (%s): %w", d.Id(), node.FQDN, err)
+		}
+	}
+
+	return nil
 }
 
 func resourceDynTrafficDirectorOptions(d *schema.ResourceData) dyn.TrafficDirectorOptionSetter {
@@ -55,69 +153,92 @@ func resourceDynTrafficDirectorOptions(d *schema.ResourceData) dyn.TrafficDirect
 			req.TTL = d.Get("ttl").(int)
 		}
 
-		nodeInterface := d.Get("node").([]interface{})
-		for _, mInterface := range nodeInterface {
-			m := mInterface.(map[string]interface{})
+		if d.Get("active").(bool) {
+			req.Active = "Y"
+		} else {
+			req.Active = "N"
+		}
 
-			entry := make(map[string]string)
-			for k, v := range m {
-				entry[k] = v.(string)
+		// Sending nodes replaces all of them, so they're only sent when
+		// the service is created. Afterwards they're attached and detached
+		// by resourceDynTrafficDirectorUpdateNodes.
+		if d.Id() == "" {
+			nodeInterface := d.Get("node").(*schema.Set).List()
+			for _, mInterface := range nodeInterface {
+				m := mInterface.(map[string]interface{})
+
+				entry := make(map[string]string)
+				for k, v := range m {
+					entry[k] = v.(string)
+				}
+				req.AddNode(entry)
 			}
-			req.AddNode(entry)
 		}
 	}
 }
 
-func resourceDynTrafficDirectorCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceDynTrafficDirectorCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	clientList := meta.(accessControlledClientList)
-	client, err := clientList.Acquire()
+	client, err := clientList.AcquireContext(ctx)
 	if err != nil {
-		return err
+		return resourceDynError(ctx, err)
 	}
 
 	label := d.Get("label").(string)
 	optionsSetter := resourceDynTrafficDirectorOptions(d)
 
+	id, err := existingTrafficDirector(client, d.Get("on_existing").(string), label)
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, err)
+	}
+	if id != "" {
+		log.Printf("[DEBUG] Adopting existing Dyn Traffic Director (%s) labeled: %s", id, label)
+		d.SetId(id)
+		clientList.Release(client)
+		return resourceDynTrafficDirectorUpdate(ctx, d, meta)
+	}
+
 	log.Printf("[DEBUG] Dyn Traffic Director create configuration: label: %s", label)
 
 	td, err := client.CreateTrafficDirector(label, optionsSetter)
 	if err != nil {
 		clientList.Release(client)
-		return fmt.Errorf("Failed to create Dyn Traffic Director: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Failed to create Dyn Traffic Director: %w", err))
 	}
 
 	d.SetId(td.ServiceID)
 	clientList.Release(client)
-	return resourceDynTrafficDirectorRead(d, meta)
+	return resourceDynTrafficDirectorRead(ctx, d, meta)
 }
 
-func resourceDynTrafficDirectorRead(d *schema.ResourceData, meta interface{}) error {
+func resourceDynTrafficDirectorRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	clientList := meta.(accessControlledClientList)
-	client, err := clientList.Acquire()
+	client, err := clientList.AcquireContext(ctx)
 	if err != nil {
-		return err
+		return resourceDynError(ctx, err)
 	}
 	defer clientList.Release(client)
 
 	log.Printf("[DEBUG] Getting Traffic Director using id: %s", d.Id())
-	td, err := client.GetTrafficDirector(d.Id())
+	td, err := clientList.GetTrafficDirector(client, d.Id())
 	if err != nil {
-		return fmt.Errorf("Couldn't find Dyn Traffic Director: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Couldn't find Dyn Traffic Director: %w", err))
 	}
 
 	err = resourceDynTrafficDirectorToResourceData(td, d)
 	if err != nil {
-		return fmt.Errorf("Couldn't convert Dyn Traffic Director: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Couldn't convert Dyn Traffic Director: %w", err))
 	}
 
 	return nil
 }
 
-func resourceDynTrafficDirectorUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceDynTrafficDirectorUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	clientList := meta.(accessControlledClientList)
-	client, err := clientList.Acquire()
+	client, err := clientList.AcquireContext(ctx)
 	if err != nil {
-		return err
+		return resourceDynError(ctx, err)
 	}
 
 	label := d.Get("label").(string)
@@ -126,58 +247,65 @@ func resourceDynTrafficDirectorUpdate(d *schema.ResourceData, meta interface{})
 	log.Printf("[DEBUG] Dyn Traffic Director update configuration for id %s: label: %s", d.Id(), label)
 
 	td, err := client.UpdateTrafficDirector(d.Id(), label, optionsSetter)
+	clientList.InvalidateTrafficDirector(d.Id())
 	if err != nil {
 		clientList.Release(client)
-		return fmt.Errorf("Failed to update Dyn Traffic Director: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Failed to update Dyn Traffic Director: %w", err))
 	}
 
 	d.SetId(td.ServiceID)
+
+	err = resourceDynTrafficDirectorUpdateNodes(clientList, client, d)
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, err)
+	}
+
 	clientList.Release(client)
-	return resourceDynTrafficDirectorRead(d, meta)
+	return resourceDynTrafficDirectorRead(ctx, d, meta)
 }
 
-func resourceDynTrafficDirectorDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceDynTrafficDirectorDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	clientList := meta.(accessControlledClientList)
-	client, err := clientList.Acquire()
+	client, err := clientList.AcquireContext(ctx)
 	if err != nil {
-		return err
+		return resourceDynError(ctx, err)
 	}
 	defer clientList.Release(client)
 
 	log.Printf("[DEBUG] Deleting Traffic Director using id: %s", d.Id())
 	err = client.DeleteTrafficDirector(d.Id())
+	clientList.InvalidateTrafficDirector(d.Id())
 	if err != nil {
-		return fmt.Errorf("Couldn't delete Dyn Traffic Director: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Couldn't delete Dyn Traffic Director: %w", err))
 	}
 
 	d.SetId("")
 	return nil
 }
 
-func resourceDynTrafficDirectorImportState(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceDynTrafficDirectorImportState(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	results := make([]*schema.ResourceData, 1, 1)
 
 	clientList := meta.(accessControlledClientList)
-	client, err := clientList.Acquire()
+	ctx, cancel := context.WithTimeout(ctx, d.Timeout(schema.TimeoutRead))
+	defer cancel()
+	client, err := clientList.AcquireContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 	defer clientList.Release(client)
 
-	log.Printf("[DEBUG] Trying to get Traffic Director using id: %s", d.Id())
-	td, err := client.GetTrafficDirector(d.Id())
+	td, err := getTrafficDirector(client, d.Id())
 	if err != nil {
-		log.Printf("[DEBUG] Error: %s / Trying to get Traffic Director using label: %s", err, d.Id())
-		td, err = client.FindTrafficDirector(d.Id())
-		if err != nil {
-			return nil, fmt.Errorf("Couldn't find Dyn Traffic Director: %s", err)
-		}
+		return nil, err
 	}
 
 	d.SetId(td.ServiceID)
+	d.Set("node", flattenTrafficDirectorNodes(td.Nodes, nil))
 	err = resourceDynTrafficDirectorToResourceData(td, d)
 	if err != nil {
-		return nil, fmt.Errorf("Couldn't convert Dyn Traffic Director: %s", err)
+		return nil, fmt.Errorf("Couldn't convert Dyn Traffic Director: %w", err)
 	}
 	results[0] = d
 
@@ -189,15 +317,7 @@ func resourceDynTrafficDirectorToResourceData(td *dyn.TrafficDirector, d *schema
 	d.Set("active", td.Active)
 	d.Set("ttl", td.TTL)
 
-	nodes := make([]map[string]string, len(td.Nodes))
-	for idx, node := range td.Nodes {
-		newNode := make(map[string]string)
-		newNode["zone"] = node.Zone
-		newNode["fqdn"] = node.FQDN
-
-		nodes[idx] = newNode
-	}
-	d.Set("node", nodes)
+	d.Set("node", flattenTrafficDirectorNodes(td.Nodes, d.Get("node").(*schema.Set)))
 
 	return nil
 }
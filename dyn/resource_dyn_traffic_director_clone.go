@@ -0,0 +1,408 @@
+package dyn
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/Shopify/go-dyn/pkg/dyn"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceDynTrafficDirectorClone() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDynTrafficDirectorCloneCreate,
+		ReadContext:   resourceDynTrafficDirectorCloneRead,
+		UpdateContext: resourceDynTrafficDirectorCloneUpdate,
+		DeleteContext: resourceDynTrafficDirectorCloneDelete,
+
+		Schema: map[string]*schema.Schema{
+			"source": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"label": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"node": trafficDirectorNodeSchema(),
+
+			"master_line_substitutions": {
+				Type:     schema.TypeMap,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"source_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"id_mapping": {
+				Type:     schema.TypeMap,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Computed: true,
+			},
+		},
+	}
+}
+
+// resourceDynTrafficDirectorCloneNodes sends the configured nodes. Nodes are
+// never copied from the source: a node can only be served by one service.
+func resourceDynTrafficDirectorCloneNodes(d *schema.ResourceData) dyn.TrafficDirectorOptionSetter {
+	return func(req *dyn.TrafficDirectorCURequest) {
+		for _, mInterface := range d.Get("node").(*schema.Set).List() {
+			m := mInterface.(map[string]interface{})
+			req.AddNode(map[string]string{
+				"zone": m["zone"].(string),
+				"fqdn": m["fqdn"].(string),
+			})
+		}
+	}
+}
+
+// trafficDirectorTreeIDMapping maps the IDs of the objects of a tree to the
+// IDs of the matching objects of another tree of the same shape. Siblings are
+// matched by label, then record sets by rdata class and records by master
+// line, and only by position when that still leaves them ambiguous, since
+// the two trees come from separate reads that may not list objects in the
+// same order. Objects without a counterpart are left out.
+func trafficDirectorTreeIDMapping(from, to *trafficDirectorTree) map[string]string {
+	mapping := make(map[string]string)
+	add := func(fromID, toID string) {
+		if fromID != "" && toID != "" {
+			mapping[fromID] = toID
+		}
+	}
+	sameLabel := func(a, b string) bool {
+		return a != "" && a == b
+	}
+
+	rulesets := trafficDirectorTreePairs(len(from.Rulesets), len(to.Rulesets), func(i, j int) bool {
+		return sameLabel(from.Rulesets[i].Label, to.Rulesets[j].Label)
+	})
+	for idx, ruleset := range from.Rulesets {
+		if rulesets[idx] >= 0 {
+			add(ruleset.ID, to.Rulesets[rulesets[idx]].ID)
+		}
+	}
+
+	responsePools := trafficDirectorTreePairs(len(from.ResponsePools), len(to.ResponsePools), func(i, j int) bool {
+		return sameLabel(from.ResponsePools[i].Label, to.ResponsePools[j].Label)
+	})
+	for idx, responsePool := range from.ResponsePools {
+		if responsePools[idx] < 0 {
+			continue
+		}
+		toResponsePool := to.ResponsePools[responsePools[idx]]
+		add(responsePool.ID, toResponsePool.ID)
+
+		recordSets := trafficDirectorTreePairs(len(responsePool.RecordSets), len(toResponsePool.RecordSets), func(i, j int) bool {
+			return sameLabel(responsePool.RecordSets[i].Label, toResponsePool.RecordSets[j].Label)
+		}, func(i, j int) bool {
+			return responsePool.RecordSets[i].RDataClass == toResponsePool.RecordSets[j].RDataClass
+		})
+		for rsidx, recordSet := range responsePool.RecordSets {
+			if recordSets[rsidx] < 0 {
+				continue
+			}
+			toRecordSet := toResponsePool.RecordSets[recordSets[rsidx]]
+			add(recordSet.ID, toRecordSet.ID)
+
+			records := trafficDirectorTreePairs(len(recordSet.Records), len(toRecordSet.Records), func(i, j int) bool {
+				return sameLabel(recordSet.Records[i].Label, toRecordSet.Records[j].Label)
+			}, func(i, j int) bool {
+				return masterLinesEquivalent(recordSet.Records[i].MasterLine, toRecordSet.Records[j].MasterLine)
+			})
+			for ridx, record := range recordSet.Records {
+				if records[ridx] >= 0 {
+					add(record.ID, toRecordSet.Records[records[ridx]].ID)
+				}
+			}
+		}
+	}
+
+	return mapping
+}
+
+// trafficDirectorTreePairs pairs two lists of n and m siblings. The matchers
+// are tried in turn, each pairing the objects that match exactly one
+// unpaired object of the other list which matches nothing else either. The
+// objects left over are then paired in order. It returns the index in the
+// second list of each object of the first, or -1 when it has no counterpart.
+func trafficDirectorTreePairs(n, m int, matchers ...func(i, j int) bool) []int {
+	pairs := make([]int, n)
+	for i := range pairs {
+		pairs[i] = -1
+	}
+	paired := make([]bool, m)
+
+	for _, matches := range matchers {
+		for i := 0; i < n; i++ {
+			if pairs[i] >= 0 {
+				continue
+			}
+
+			candidate := -1
+			for j := 0; j < m && candidate != -2; j++ {
+				if paired[j] || !matches(i, j) {
+					continue
+				}
+				if candidate >= 0 {
+					candidate = -2
+				} else {
+					candidate = j
+				}
+			}
+			if candidate < 0 {
+				continue
+			}
+
+			unique := true
+			for k := 0; k < n && unique; k++ {
+				if k != i && pairs[k] < 0 && matches(k, candidate) {
+					unique = false
+				}
+			}
+			if unique {
+				pairs[i] = candidate
+				paired[candidate] = true
+			}
+		}
+	}
+
+	j := 0
+	for i := 0; i < n; i++ {
+		if pairs[i] >= 0 {
+			continue
+		}
+		for j < m && paired[j] {
+			j++
+		}
+		if j == m {
+			break
+		}
+		pairs[i] = j
+		paired[j] = true
+	}
+
+	return pairs
+}
+
+// resourceDynTrafficDirectorCloneTree returns the tree of a service including
+// the response pools that no ruleset points at, which Dyn leaves out of the
+// service itself.
+func resourceDynTrafficDirectorCloneTree(client *dyn.Client, td *dyn.TrafficDirector) (*trafficDirectorTree, error) {
+	td, err := resourceDynTrafficDirectorWithResponsePools(client, td)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTrafficDirectorTree(td), nil
+}
+
+// resourceDynTrafficDirectorWithResponsePools returns a copy of the service
+// with every one of its response pools.
+func resourceDynTrafficDirectorWithResponsePools(client *dyn.Client, td *dyn.TrafficDirector) (*dyn.TrafficDirector, error) {
+	log.Printf("[DEBUG] Getting Traffic Director (%s) Response Pools", td.ServiceID)
+	responsePools, err := client.GetTrafficDirectorResponsePools(td.ServiceID)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't get Dyn Traffic Director (%s) Response Pools: %w", td.ServiceID, err)
+	}
+
+	withPools := *td
+	withPools.ResponsePools = responsePools
+	return &withPools, nil
+}
+
+// substituteMasterLines replaces the master lines of the records of the tree
+// that are substituted by record ID or by master line.
+func (t *trafficDirectorTree) substituteMasterLines(substitutions map[string]interface{}) {
+	for _, responsePool := range t.ResponsePools {
+		for _, recordSet := range responsePool.RecordSets {
+			for _, record := range recordSet.Records {
+				if masterLine, ok := substitutions[record.ID]; ok {
+					record.MasterLine = masterLine.(string)
+				} else if masterLine, ok := substitutions[record.MasterLine]; ok {
+					record.MasterLine = masterLine.(string)
+				}
+			}
+		}
+	}
+}
+
+// unreferencedResponsePools returns the response pools of the tree that no
+// ruleset points at.
+func (t *trafficDirectorTree) unreferencedResponsePools() []*trafficDirectorTreeResponsePool {
+	referenced := make(map[string]bool)
+	for _, ruleset := range t.Rulesets {
+		for _, label := range ruleset.ResponsePools {
+			referenced[label] = true
+		}
+	}
+
+	unreferenced := make([]*trafficDirectorTreeResponsePool, 0)
+	for _, responsePool := range t.ResponsePools {
+		if !referenced[responsePool.Label] {
+			unreferenced = append(unreferenced, responsePool)
+		}
+	}
+
+	return unreferenced
+}
+
+func resourceDynTrafficDirectorCloneCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	clientList := meta.(accessControlledClientList)
+	client, err := clientList.AcquireContext(ctx)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+
+	source := d.Get("source").(string)
+	label := d.Get("label").(string)
+
+	log.Printf("[DEBUG] Trying to get source Traffic Director using id: %s", source)
+	src, err := client.GetTrafficDirector(source)
+	if err != nil {
+		log.Printf("[DEBUG] Error: %s / Trying to get source Traffic Director using label: %s", err, source)
+		src, err = client.FindTrafficDirector(source)
+		if err != nil {
+			clientList.Release(client)
+			return resourceDynError(ctx, fmt.Errorf("Couldn't find source Dyn Traffic Director: %w", err))
+		}
+	}
+
+	// The copy starts out as the source tree without any of its IDs, with
+	// the requested master lines substituted. Monitors are shared between
+	// services, so record sets keep pointing at the monitors of the source.
+	src, err = resourceDynTrafficDirectorWithResponsePools(client, src)
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, err)
+	}
+	// The source tree gets the substitutions too, so that its records can
+	// be matched with those of the clone by master line.
+	substitutions := d.Get("master_line_substitutions").(map[string]interface{})
+	sourceTree := newTrafficDirectorTree(src)
+	sourceTree.substituteMasterLines(substitutions)
+	copyTree := newTrafficDirectorTree(src)
+	copyTree.substituteMasterLines(substitutions)
+	for _, ruleset := range copyTree.Rulesets {
+		ruleset.ID = ""
+	}
+	for _, responsePool := range copyTree.ResponsePools {
+		responsePool.ID = ""
+		for _, recordSet := range responsePool.RecordSets {
+			recordSet.ID = ""
+			for _, record := range recordSet.Records {
+				record.ID = ""
+			}
+		}
+	}
+
+	log.Printf("[DEBUG] Dyn Traffic Director clone configuration: source: %s, label: %s", src.ServiceID, label)
+
+	active := "N"
+	if src.Active {
+		active = "Y"
+	}
+	td, err := client.CreateTrafficDirector(label, func(req *dyn.TrafficDirectorCURequest) {
+		req.TTL = src.TTL
+		req.Active = active
+	}, resourceDynTrafficDirectorCloneNodes(d), trafficDirectorRulesets(copyTree))
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, fmt.Errorf("Failed to create Dyn Traffic Director clone of %s: %w", src.ServiceID, err))
+	}
+	d.SetId(td.ServiceID)
+	d.Set("source_id", src.ServiceID)
+
+	// Response pools that no ruleset points at can't be part of the
+	// service request, they're created on their own.
+	for _, responsePool := range copyTree.unreferencedResponsePools() {
+		log.Printf("[DEBUG] Creating Traffic Director clone (%s) Response Pool: %s", td.ServiceID, responsePool.Label)
+		req := responsePool.request()
+		_, err := client.CreateTrafficDirectorResponsePool(td.ServiceID, responsePool.Label, func(tdrpreq *dyn.TrafficDirectorResponsePoolCURequest) {
+			tdrpreq.RecordSetChains = req.RecordSetChains
+		})
+		if err != nil {
+			clientList.Release(client)
+			return resourceDynError(ctx, fmt.Errorf("Failed to create Dyn Traffic Director clone (%s) Response Pool (%s): %w", td.ServiceID, responsePool.Label, err))
+		}
+	}
+
+	log.Printf("[DEBUG] Getting Traffic Director clone (%s) to map IDs", td.ServiceID)
+	clone, err := client.GetTrafficDirector(td.ServiceID)
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, fmt.Errorf("Couldn't find Dyn Traffic Director: %w", err))
+	}
+	cloneTree, err := resourceDynTrafficDirectorCloneTree(client, clone)
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, err)
+	}
+	d.Set("id_mapping", trafficDirectorTreeIDMapping(sourceTree, cloneTree))
+
+	clientList.Release(client)
+	return resourceDynTrafficDirectorCloneRead(ctx, d, meta)
+}
+
+func resourceDynTrafficDirectorCloneRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	clientList := meta.(accessControlledClientList)
+	client, err := clientList.AcquireContext(ctx)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+	defer clientList.Release(client)
+
+	log.Printf("[DEBUG] Getting Traffic Director clone using id: %s", d.Id())
+	td, err := clientList.GetTrafficDirector(client, d.Id())
+	if err != nil {
+		return resourceDynError(ctx, fmt.Errorf("Couldn't find Dyn Traffic Director: %w", err))
+	}
+
+	d.Set("label", td.Label)
+
+	d.Set("node", flattenTrafficDirectorNodes(td.Nodes, d.Get("node").(*schema.Set)))
+
+	return nil
+}
+
+func resourceDynTrafficDirectorCloneUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	clientList := meta.(accessControlledClientList)
+	client, err := clientList.AcquireContext(ctx)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+
+	label := d.Get("label").(string)
+	if d.HasChange("label") {
+		log.Printf("[DEBUG] Dyn Traffic Director clone update configuration for id %s: label: %s", d.Id(), label)
+
+		_, err = client.UpdateTrafficDirector(d.Id(), label)
+		clientList.InvalidateTrafficDirector(d.Id())
+		if err != nil {
+			clientList.Release(client)
+			return resourceDynError(ctx, fmt.Errorf("Failed to update Dyn Traffic Director: %w", err))
+		}
+	}
+
+	err = resourceDynTrafficDirectorUpdateNodes(clientList, client, d)
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, err)
+	}
+
+	clientList.Release(client)
+	return resourceDynTrafficDirectorCloneRead(ctx, d, meta)
+}
+
+func resourceDynTrafficDirectorCloneDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return resourceDynTrafficDirectorDelete(ctx, d, meta)
+}
@@ -0,0 +1,167 @@
+package dyn
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func testTrafficDirectorClonePool(id, label, recordSetID string, recordIDs ...string) map[string]interface{} {
+	records := make([]interface{}, len(recordIDs))
+	for idx, recordID := range recordIDs {
+		// Every record has the same label, only their position tells them apart.
+		records[idx] = map[string]interface{}{"dsf_record_id": recordID, "label": "web", "master_line": "192.0.2.1"}
+	}
+
+	return map[string]interface{}{
+		"dsf_response_pool_id": id,
+		"label":                label,
+		"rs_chains": []interface{}{map[string]interface{}{
+			"record_sets": []interface{}{map[string]interface{}{
+				"dsf_record_set_id": recordSetID,
+				"label":             "web",
+				"rdata_class":       "A",
+				"dsf_monitor_id":    "monitor",
+				"records":           records,
+			}},
+		}},
+	}
+}
+
+func TestResourceDynTrafficDirectorCloneCreate(t *testing.T) {
+	service := func(id string, poolID string) map[string]interface{} {
+		return map[string]interface{}{
+			"service_id": id,
+			"label":      id,
+			"active":     "Y",
+			"ttl":        "30",
+			"rulesets": []interface{}{map[string]interface{}{
+				"dsf_ruleset_id": id + "-ruleset",
+				"label":          "default",
+				"ordering":       "0",
+				"response_pools": []interface{}{map[string]interface{}{"dsf_response_pool_id": poolID, "label": "primary"}},
+			}},
+		}
+	}
+
+	var created []map[string]interface{}
+	clientList := testDynClientList(t, func(method, resource string, body map[string]interface{}) (interface{}, int) {
+		switch method + " " + resource {
+		case "GET DSF/src":
+			return service("src", "p1"), http.StatusOK
+		case "GET DSFResponsePool/src":
+			return []interface{}{
+				testTrafficDirectorClonePool("p1", "primary", "s1", "r1", "r2"),
+				testTrafficDirectorClonePool("p2", "unused", "s2", "r3"),
+			}, http.StatusOK
+		case "POST DSF":
+			created = append(created, body)
+			return service("clone", "c-p1"), http.StatusOK
+		case "POST DSFResponsePool/clone":
+			created = append(created, body)
+			return testTrafficDirectorClonePool("c-p2", "unused", "c-s2", "c-r3"), http.StatusOK
+		case "GET DSF/clone":
+			return service("clone", "c-p1"), http.StatusOK
+		case "GET DSFResponsePool/clone":
+			return []interface{}{
+				testTrafficDirectorClonePool("c-p1", "primary", "c-s1", "c-r1", "c-r2"),
+				testTrafficDirectorClonePool("c-p2", "unused", "c-s2", "c-r3"),
+			}, http.StatusOK
+		}
+		return nil, http.StatusNotFound
+	})
+
+	r := resourceDynTrafficDirectorClone()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"source": "src",
+		"label":  "clone",
+	})
+
+	if diags := r.CreateContext(context.Background(), d, clientList); diags.HasError() {
+		t.Fatalf("unexpected create error: %v", diags)
+	}
+
+	if len(created) != 2 || created[1]["label"] != "unused" {
+		t.Fatalf("expected the unreferenced response pool to be created on its own, got %v", created)
+	}
+	recordSet := created[1]["rs_chains"].([]interface{})[0].(map[string]interface{})["record_sets"].([]interface{})[0].(map[string]interface{})
+	if recordSet["dsf_monitor_id"] != "monitor" {
+		t.Errorf("expected the monitor of the source to be kept, got %v", recordSet["dsf_monitor_id"])
+	}
+
+	mapping := d.Get("id_mapping").(map[string]interface{})
+	for from, to := range map[string]string{
+		"src-ruleset": "clone-ruleset",
+		"p1":          "c-p1",
+		"r1":          "c-r1",
+		"r2":          "c-r2",
+		"p2":          "c-p2",
+		"s2":          "c-s2",
+		"r3":          "c-r3",
+	} {
+		if mapping[from] != to {
+			t.Errorf("expected %s to map to %s, got %v", from, to, mapping[from])
+		}
+	}
+}
+
+func TestTrafficDirectorTreeIDMappingReordered(t *testing.T) {
+	record := func(id, label, masterLine string) *trafficDirectorTreeRecord {
+		return &trafficDirectorTreeRecord{ID: id, Label: label, MasterLine: masterLine}
+	}
+
+	from := &trafficDirectorTree{
+		Rulesets: []*trafficDirectorTreeRuleset{{ID: "rs1", Label: "eu"}, {ID: "rs2", Label: "us"}},
+		ResponsePools: []*trafficDirectorTreeResponsePool{
+			{ID: "p1", Label: "primary", RecordSets: []*trafficDirectorTreeRecordSet{
+				{ID: "s1", RDataClass: "A", Records: []*trafficDirectorTreeRecord{
+					record("r1", "", "192.0.2.1"),
+					record("r2", "", "192.0.2.2"),
+					record("r3", "web", "192.0.2.3"),
+					record("r4", "", "192.0.2.9"),
+					record("r5", "", "192.0.2.9"),
+				}},
+				{ID: "s2", RDataClass: "AAAA"},
+			}},
+			{ID: "p2", Label: "backup"},
+		},
+	}
+	to := &trafficDirectorTree{
+		Rulesets: []*trafficDirectorTreeRuleset{{ID: "c-rs2", Label: "us"}, {ID: "c-rs1", Label: "eu"}},
+		ResponsePools: []*trafficDirectorTreeResponsePool{
+			{ID: "c-p2", Label: "backup"},
+			{ID: "c-p1", Label: "primary", RecordSets: []*trafficDirectorTreeRecordSet{
+				{ID: "c-s2", RDataClass: "AAAA"},
+				{ID: "c-s1", RDataClass: "A", Records: []*trafficDirectorTreeRecord{
+					record("c-r3", "web", "192.0.2.33"),
+					record("c-r2", "", "192.0.2.2"),
+					record("c-r4", "", "192.0.2.9"),
+					record("c-r1", "", "192.0.2.1"),
+					record("c-r5", "", "192.0.2.9"),
+				}},
+			}},
+		},
+	}
+
+	mapping := trafficDirectorTreeIDMapping(from, to)
+	for from, to := range map[string]string{
+		"rs1": "c-rs1",
+		"rs2": "c-rs2",
+		"p1":  "c-p1",
+		"p2":  "c-p2",
+		"s1":  "c-s1",
+		"s2":  "c-s2",
+		"r1":  "c-r1",
+		"r2":  "c-r2",
+		"r3":  "c-r3",
+		// Identical records are told apart by their position only.
+		"r4": "c-r4",
+		"r5": "c-r5",
+	} {
+		if mapping[from] != to {
+			t.Errorf("expected %s to map to %s, got %v", from, to, mapping[from])
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package dyn
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceDynTrafficDirectorJSON() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDynTrafficDirectorJSONCreate,
+		ReadContext:   resourceDynTrafficDirectorJSONRead,
+		UpdateContext: resourceDynTrafficDirectorJSONUpdate,
+		DeleteContext: resourceDynTrafficDirectorJSONDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceDynTrafficDirectorJSONImportState,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"definition": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateTrafficDirectorJSON,
+				// Keep the canonical form in the state, one field per
+				// line, so that plans show which fields change rather
+				// than replacing the whole document.
+				StateFunc: func(v interface{}) string {
+					canonical, err := canonicalTrafficDirectorJSON(v.(string))
+					if err != nil {
+						return v.(string)
+					}
+					return canonical
+				},
+			},
+
+			"label": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func validateTrafficDirectorJSON(i interface{}, k string) (s []string, es []error) {
+	v, ok := i.(string)
+	if !ok {
+		es = append(es, fmt.Errorf("expected type of %s to be string", k))
+		return
+	}
+
+	doc, err := parseTrafficDirectorJSON(v)
+	if err != nil {
+		es = append(es, fmt.Errorf("%s contains an invalid DSF document: %w", k, err))
+		return
+	}
+
+	if label, _ := doc["label"].(string); label == "" {
+		es = append(es, fmt.Errorf("%s must have a label", k))
+	}
+
+	return
+}
+
+// resourceDynTrafficDirectorJSONDocument returns the configured document
+// without any of the fields computed by Dyn.
+func resourceDynTrafficDirectorJSONDocument(d *schema.ResourceData) (map[string]interface{}, error) {
+	doc, err := parseTrafficDirectorJSON(d.Get("definition").(string))
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't parse definition: %w", err)
+	}
+
+	return stripTrafficDirectorJSON(doc, "").(map[string]interface{}), nil
+}
+
+func resourceDynTrafficDirectorJSONCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	clientList := meta.(accessControlledClientList)
+	client, err := clientList.AcquireContext(ctx)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+
+	doc, err := resourceDynTrafficDirectorJSONDocument(d)
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, err)
+	}
+
+	log.Printf("[DEBUG] Dyn Traffic Director JSON create configuration: label: %s", doc["label"])
+
+	td, err := client.CreateTrafficDirectorJSON(doc)
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, fmt.Errorf("Failed to create Dyn Traffic Director: %w", err))
+	}
+
+	d.SetId(td.ServiceID)
+	clientList.Release(client)
+	return resourceDynTrafficDirectorJSONRead(ctx, d, meta)
+}
+
+func resourceDynTrafficDirectorJSONRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	clientList := meta.(accessControlledClientList)
+	client, err := clientList.AcquireContext(ctx)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+	defer clientList.Release(client)
+
+	log.Printf("[DEBUG] Getting Traffic Director JSON using id: %s", d.Id())
+	raw, err := client.GetTrafficDirectorJSON(d.Id())
+	if err != nil {
+		return resourceDynError(ctx, fmt.Errorf("Couldn't find Dyn Traffic Director: %w", err))
+	}
+
+	return resourceDynError(ctx, resourceDynTrafficDirectorJSONToResourceData(raw, d))
+}
+
+func resourceDynTrafficDirectorJSONUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	clientList := meta.(accessControlledClientList)
+	client, err := clientList.AcquireContext(ctx)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+
+	doc, err := resourceDynTrafficDirectorJSONDocument(d)
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, err)
+	}
+
+	log.Printf("[DEBUG] Getting Traffic Director JSON (%s) to keep object IDs", d.Id())
+	raw, err := client.GetTrafficDirectorJSON(d.Id())
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, fmt.Errorf("Couldn't find Dyn Traffic Director: %w", err))
+	}
+
+	live, err := parseTrafficDirectorJSON(string(raw))
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, fmt.Errorf("Couldn't parse Dyn Traffic Director: %w", err))
+	}
+	adoptTrafficDirectorJSONIDs(doc, live)
+
+	log.Printf("[DEBUG] Dyn Traffic Director JSON update configuration for id %s: label: %s", d.Id(), doc["label"])
+
+	_, err = client.UpdateTrafficDirectorJSON(d.Id(), doc)
+	clientList.InvalidateTrafficDirector(d.Id())
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, fmt.Errorf("Failed to update Dyn Traffic Director: %w", err))
+	}
+
+	clientList.Release(client)
+	return resourceDynTrafficDirectorJSONRead(ctx, d, meta)
+}
+
+func resourceDynTrafficDirectorJSONDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return resourceDynTrafficDirectorDelete(ctx, d, meta)
+}
+
+func resourceDynTrafficDirectorJSONImportState(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	results := make([]*schema.ResourceData, 1, 1)
+
+	clientList := meta.(accessControlledClientList)
+	ctx, cancel := context.WithTimeout(ctx, d.Timeout(schema.TimeoutRead))
+	defer cancel()
+	client, err := clientList.AcquireContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer clientList.Release(client)
+
+	log.Printf("[DEBUG] Trying to get Traffic Director JSON using id: %s", d.Id())
+	raw, err := client.GetTrafficDirectorJSON(d.Id())
+	if err != nil {
+		log.Printf("[DEBUG] Error: %s / Trying to get Traffic Director using label: %s", err, d.Id())
+		td, err := client.FindTrafficDirector(d.Id())
+		if err != nil {
+			return nil, fmt.Errorf("Couldn't find Dyn Traffic Director: %w", err)
+		}
+		raw, err = client.GetTrafficDirectorJSON(td.ServiceID)
+		if err != nil {
+			return nil, fmt.Errorf("Couldn't find Dyn Traffic Director: %w", err)
+		}
+		d.SetId(td.ServiceID)
+	}
+
+	err = resourceDynTrafficDirectorJSONToResourceData(raw, d)
+	if err != nil {
+		return nil, err
+	}
+	results[0] = d
+
+	return results, nil
+}
+
+// resourceDynTrafficDirectorJSONToResourceData stores the live document in
+// its canonical form, limited to the fields the current definition sets.
+func resourceDynTrafficDirectorJSONToResourceData(raw json.RawMessage, d *schema.ResourceData) error {
+	live, err := parseTrafficDirectorJSON(string(raw))
+	if err != nil {
+		return fmt.Errorf("Couldn't parse Dyn Traffic Director: %w", err)
+	}
+
+	normalized := normalizeTrafficDirectorJSON(stripTrafficDirectorJSON(live, ""), "")
+	if config, err := parseTrafficDirectorJSON(d.Get("definition").(string)); err == nil {
+		normalized = projectTrafficDirectorJSON(normalized, normalizeTrafficDirectorJSON(stripTrafficDirectorJSON(config, ""), ""))
+	}
+
+	definition, err := formatTrafficDirectorJSON(normalized)
+	if err != nil {
+		return fmt.Errorf("Couldn't format Dyn Traffic Director: %w", err)
+	}
+
+	d.Set("definition", definition)
+	d.Set("label", live["label"])
+
+	return nil
+}
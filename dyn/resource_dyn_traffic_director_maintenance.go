@@ -0,0 +1,411 @@
+package dyn
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/Shopify/go-dyn/pkg/dyn"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceDynTrafficDirectorMaintenance() *schema.Resource {
+	savedStateResource := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"eligible": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"automation": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+
+	return &schema.Resource{
+		CreateContext: resourceDynTrafficDirectorMaintenanceCreate,
+		ReadContext:   resourceDynTrafficDirectorMaintenanceRead,
+		UpdateContext: resourceDynTrafficDirectorMaintenanceUpdate,
+		DeleteContext: resourceDynTrafficDirectorMaintenanceDelete,
+
+		CustomizeDiff: resourceDynTrafficDirectorMaintenanceCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"traffic_director_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"record_ids": {
+				Type: schema.TypeSet,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"response_pool_ids": {
+				Type: schema.TypeSet,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"saved_record": {
+				Type:     schema.TypeList,
+				Elem:     savedStateResource,
+				Computed: true,
+			},
+
+			"saved_response_pool": {
+				Type:     schema.TypeList,
+				Elem:     savedStateResource,
+				Computed: true,
+			},
+
+			// drained is read back as false when something made one of
+			// the records or response pools eligible again, so that the
+			// next apply drains them again.
+			"drained": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+				ValidateFunc: func(i interface{}, k string) ([]string, []error) {
+					if !i.(bool) {
+						return nil, []error{fmt.Errorf("%s can only be true, remove the maintenance to end it", k)}
+					}
+					return nil, nil
+				},
+			},
+		},
+	}
+}
+
+func resourceDynTrafficDirectorMaintenanceCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	tree, err := getTrafficDirectorDiffTree(ctx, d, meta, "record_ids", "response_pool_ids")
+	if err != nil || tree == nil {
+		return err
+	}
+
+	err = tree.checkIDs("record_ids", "Record", trafficDirectorDiffIDs(d, "record_ids"), func(id string) bool {
+		return tree.record(id) != nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return tree.checkIDs("response_pool_ids", "Response Pool", trafficDirectorDiffIDs(d, "response_pool_ids"), func(id string) bool {
+		return tree.responsePool(id) != nil
+	})
+}
+
+// trafficDirectorMaintenanceState is the eligibility and automation of a
+// record or response pool before it was put in maintenance.
+type trafficDirectorMaintenanceState struct {
+	ID         string
+	Eligible   bool
+	Automation string
+}
+
+func (s trafficDirectorMaintenanceState) toMap() map[string]interface{} {
+	return map[string]interface{}{
+		"id":         s.ID,
+		"eligible":   s.Eligible,
+		"automation": s.Automation,
+	}
+}
+
+func trafficDirectorMaintenanceStatesFromResourceData(d *schema.ResourceData, key string) []trafficDirectorMaintenanceState {
+	statesInterface := d.Get(key).([]interface{})
+	states := make([]trafficDirectorMaintenanceState, len(statesInterface))
+	for idx, stateInterface := range statesInterface {
+		m := stateInterface.(map[string]interface{})
+		states[idx] = trafficDirectorMaintenanceState{
+			ID:         m["id"].(string),
+			Eligible:   m["eligible"].(bool),
+			Automation: m["automation"].(string),
+		}
+	}
+
+	return states
+}
+
+// trafficDirectorMaintenanceDrained reports whether a record or response pool
+// with the given eligibility and automation is drained. Automation has to stay
+// manual, or Dyn's monitoring would make it eligible again.
+func trafficDirectorMaintenanceDrained(eligible bool, automation string) bool {
+	return !eligible && automation == "manual"
+}
+
+func trafficDirectorRecordEligibility(eligible bool, automation string) dyn.TrafficDirectorRecordOptionSetter {
+	return func(req *dyn.TrafficDirectorRecordCURequest) {
+		req.Eligible = strconv.FormatBool(eligible)
+		req.Automation = automation
+	}
+}
+
+func trafficDirectorResponsePoolEligibility(eligible bool, automation string) dyn.TrafficDirectorResponsePoolOptionSetter {
+	return func(req *dyn.TrafficDirectorResponsePoolCURequest) {
+		req.Eligible = strconv.FormatBool(eligible)
+		req.Automation = automation
+	}
+}
+
+func resourceDynTrafficDirectorMaintenanceCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	clientList := meta.(accessControlledClientList)
+	client, err := clientList.AcquireContext(ctx)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+	defer clientList.Release(client)
+
+	tdID := d.Get("traffic_director_id").(string)
+	recordIDs := d.Get("record_ids").(*schema.Set).List()
+	responsePoolIDs := d.Get("response_pool_ids").(*schema.Set).List()
+
+	if len(recordIDs) == 0 && len(responsePoolIDs) == 0 {
+		return resourceDynError(ctx, fmt.Errorf("At least one of record_ids or response_pool_ids must be set"))
+	}
+
+	savedRecords := make([]trafficDirectorMaintenanceState, 0, len(recordIDs))
+	savedResponsePools := make([]trafficDirectorMaintenanceState, 0, len(responsePoolIDs))
+
+	// If anything fails half-way, put back what was already drained so
+	// that a failed apply doesn't leave the service partially disabled.
+	rollback := func(err error) error {
+		restoreErr := trafficDirectorMaintenanceRestore(clientList, client, tdID, savedRecords, savedResponsePools)
+		if restoreErr != nil {
+			log.Printf("[WARN] Couldn't restore Dyn Traffic Director (%s) after failed maintenance: %s", tdID, restoreErr)
+		}
+		return err
+	}
+
+	for _, recordIDInterface := range recordIDs {
+		saved, err := trafficDirectorMaintenanceDrainRecord(clientList, client, tdID, recordIDInterface.(string))
+		if err != nil {
+			return resourceDynError(ctx, rollback(err))
+		}
+		savedRecords = append(savedRecords, saved)
+	}
+
+	for _, responsePoolIDInterface := range responsePoolIDs {
+		saved, err := trafficDirectorMaintenanceDrainResponsePool(clientList, client, tdID, responsePoolIDInterface.(string))
+		if err != nil {
+			return resourceDynError(ctx, rollback(err))
+		}
+		savedResponsePools = append(savedResponsePools, saved)
+	}
+
+	savedRecordsList := make([]map[string]interface{}, len(savedRecords))
+	for idx, saved := range savedRecords {
+		savedRecordsList[idx] = saved.toMap()
+	}
+	savedResponsePoolsList := make([]map[string]interface{}, len(savedResponsePools))
+	for idx, saved := range savedResponsePools {
+		savedResponsePoolsList[idx] = saved.toMap()
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", tdID, id.UniqueId()))
+	d.Set("saved_record", savedRecordsList)
+	d.Set("saved_response_pool", savedResponsePoolsList)
+	d.Set("drained", true)
+
+	return nil
+}
+
+// trafficDirectorMaintenanceDrainRecord makes a record ineligible, unless it
+// already is, and returns the state it was in.
+func trafficDirectorMaintenanceDrainRecord(clientList accessControlledClientList, client *dyn.Client, tdID, recordID string) (trafficDirectorMaintenanceState, error) {
+	log.Printf("[DEBUG] Getting Traffic Director (%s) Record (%s) for maintenance", tdID, recordID)
+	tdr, err := client.GetTrafficDirectorRecord(tdID, recordID)
+	if err != nil {
+		return trafficDirectorMaintenanceState{}, fmt.Errorf("Couldn't find Dyn Traffic Director (%s) Record (%s): %w", tdID, recordID, err)
+	}
+
+	saved := trafficDirectorMaintenanceState{
+		ID:         tdr.RecordID,
+		Eligible:   tdr.Eligible,
+		Automation: tdr.Automation,
+	}
+	if trafficDirectorMaintenanceDrained(tdr.Eligible, tdr.Automation) {
+		return saved, nil
+	}
+
+	log.Printf("[DEBUG] Draining Traffic Director (%s) Record (%s)", tdID, recordID)
+	_, err = client.UpdateTrafficDirectorRecord(tdID, recordID, tdr.MasterLine, trafficDirectorRecordEligibility(false, "manual"))
+	clientList.InvalidateTrafficDirector(tdID)
+	if err != nil {
+		return saved, fmt.Errorf("Failed to drain Dyn Traffic Director (%s) Record (%s): %w", tdID, recordID, err)
+	}
+
+	return saved, nil
+}
+
+// trafficDirectorMaintenanceDrainResponsePool makes a response pool
+// ineligible, unless it already is, and returns the state it was in.
+func trafficDirectorMaintenanceDrainResponsePool(clientList accessControlledClientList, client *dyn.Client, tdID, responsePoolID string) (trafficDirectorMaintenanceState, error) {
+	log.Printf("[DEBUG] Getting Traffic Director (%s) Response Pool (%s) for maintenance", tdID, responsePoolID)
+	tdrp, err := client.GetTrafficDirectorResponsePool(tdID, responsePoolID)
+	if err != nil {
+		return trafficDirectorMaintenanceState{}, fmt.Errorf("Couldn't find Dyn Traffic Director (%s) Response Pool (%s): %w", tdID, responsePoolID, err)
+	}
+
+	saved := trafficDirectorMaintenanceState{
+		ID:         tdrp.ResponsePoolID,
+		Eligible:   tdrp.Eligible,
+		Automation: tdrp.Automation,
+	}
+	if trafficDirectorMaintenanceDrained(tdrp.Eligible, tdrp.Automation) {
+		return saved, nil
+	}
+
+	log.Printf("[DEBUG] Draining Traffic Director (%s) Response Pool (%s)", tdID, responsePoolID)
+	_, err = client.UpdateTrafficDirectorResponsePool(tdID, responsePoolID, tdrp.Label, trafficDirectorResponsePoolEligibility(false, "manual"))
+	clientList.InvalidateTrafficDirector(tdID)
+	if err != nil {
+		return saved, fmt.Errorf("Failed to drain Dyn Traffic Director (%s) Response Pool (%s): %w", tdID, responsePoolID, err)
+	}
+
+	return saved, nil
+}
+
+func resourceDynTrafficDirectorMaintenanceRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	clientList := meta.(accessControlledClientList)
+	client, err := clientList.AcquireContext(ctx)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+	defer clientList.Release(client)
+
+	tdID := d.Get("traffic_director_id").(string)
+
+	// The saved states are only ever written on create, what can change is
+	// whether everything is still drained.
+	log.Printf("[DEBUG] Getting Traffic Director (%s) for maintenance (%s)", tdID, d.Id())
+	_, err = clientList.GetTrafficDirector(client, tdID)
+	if err != nil {
+		return resourceDynError(ctx, fmt.Errorf("Couldn't find Dyn Traffic Director: %w", err))
+	}
+
+	drained := true
+	for _, recordID := range d.Get("record_ids").(*schema.Set).List() {
+		tdr, err := clientList.GetTrafficDirectorRecord(client, tdID, recordID.(string))
+		if err != nil {
+			return resourceDynError(ctx, fmt.Errorf("Couldn't find Dyn Traffic Director (%s) Record (%s): %w", tdID, recordID, err))
+		}
+		if !trafficDirectorMaintenanceDrained(tdr.Eligible, tdr.Automation) {
+			log.Printf("[DEBUG] Traffic Director (%s) Record (%s) isn't drained anymore", tdID, recordID)
+			drained = false
+		}
+	}
+	for _, responsePoolID := range d.Get("response_pool_ids").(*schema.Set).List() {
+		tdrp, err := clientList.GetTrafficDirectorResponsePool(client, tdID, responsePoolID.(string))
+		if err != nil {
+			return resourceDynError(ctx, fmt.Errorf("Couldn't find Dyn Traffic Director (%s) Response Pool (%s): %w", tdID, responsePoolID, err))
+		}
+		if !trafficDirectorMaintenanceDrained(tdrp.Eligible, tdrp.Automation) {
+			log.Printf("[DEBUG] Traffic Director (%s) Response Pool (%s) isn't drained anymore", tdID, responsePoolID)
+			drained = false
+		}
+	}
+	d.Set("drained", drained)
+
+	return nil
+}
+
+// resourceDynTrafficDirectorMaintenanceUpdate drains again what was made
+// eligible since the maintenance started. The saved states are left alone, so
+// that they're still what gets restored in the end.
+func resourceDynTrafficDirectorMaintenanceUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	clientList := meta.(accessControlledClientList)
+	client, err := clientList.AcquireContext(ctx)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+	defer clientList.Release(client)
+
+	tdID := d.Get("traffic_director_id").(string)
+
+	log.Printf("[DEBUG] Draining Traffic Director (%s) maintenance (%s) again", tdID, d.Id())
+	for _, recordID := range d.Get("record_ids").(*schema.Set).List() {
+		if _, err := trafficDirectorMaintenanceDrainRecord(clientList, client, tdID, recordID.(string)); err != nil {
+			return resourceDynError(ctx, err)
+		}
+	}
+	for _, responsePoolID := range d.Get("response_pool_ids").(*schema.Set).List() {
+		if _, err := trafficDirectorMaintenanceDrainResponsePool(clientList, client, tdID, responsePoolID.(string)); err != nil {
+			return resourceDynError(ctx, err)
+		}
+	}
+	d.Set("drained", true)
+
+	return nil
+}
+
+func resourceDynTrafficDirectorMaintenanceDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	clientList := meta.(accessControlledClientList)
+	client, err := clientList.AcquireContext(ctx)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+	defer clientList.Release(client)
+
+	tdID := d.Get("traffic_director_id").(string)
+	savedRecords := trafficDirectorMaintenanceStatesFromResourceData(d, "saved_record")
+	savedResponsePools := trafficDirectorMaintenanceStatesFromResourceData(d, "saved_response_pool")
+
+	log.Printf("[DEBUG] Ending Traffic Director (%s) maintenance (%s)", tdID, d.Id())
+	err = trafficDirectorMaintenanceRestore(clientList, client, tdID, savedRecords, savedResponsePools)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// trafficDirectorMaintenanceRestore puts back the eligibility and automation
+// that the records and response pools had before the maintenance started.
+func trafficDirectorMaintenanceRestore(clientList accessControlledClientList, client *dyn.Client, tdID string, savedRecords, savedResponsePools []trafficDirectorMaintenanceState) error {
+	for _, saved := range savedRecords {
+		tdr, err := client.GetTrafficDirectorRecord(tdID, saved.ID)
+		if err != nil {
+			return fmt.Errorf("Couldn't find Dyn Traffic Director (%s) Record (%s): %w", tdID, saved.ID, err)
+		}
+
+		log.Printf("[DEBUG] Restoring Traffic Director (%s) Record (%s): eligible: %t; automation: %s", tdID, saved.ID, saved.Eligible, saved.Automation)
+		_, err = client.UpdateTrafficDirectorRecord(tdID, saved.ID, tdr.MasterLine, trafficDirectorRecordEligibility(saved.Eligible, saved.Automation))
+		clientList.InvalidateTrafficDirector(tdID)
+		if err != nil {
+			return fmt.Errorf("Failed to restore Dyn Traffic Director (%s) Record (%s): %w", tdID, saved.ID, err)
+		}
+	}
+
+	for _, saved := range savedResponsePools {
+		tdrp, err := client.GetTrafficDirectorResponsePool(tdID, saved.ID)
+		if err != nil {
+			return fmt.Errorf("Couldn't find Dyn Traffic Director (%s) Response Pool (%s): %w", tdID, saved.ID, err)
+		}
+
+		log.Printf("[DEBUG] Restoring Traffic Director (%s) Response Pool (%s): eligible: %t; automation: %s", tdID, saved.ID, saved.Eligible, saved.Automation)
+		_, err = client.UpdateTrafficDirectorResponsePool(tdID, saved.ID, tdrp.Label, trafficDirectorResponsePoolEligibility(saved.Eligible, saved.Automation))
+		clientList.InvalidateTrafficDirector(tdID)
+		if err != nil {
+			return fmt.Errorf("Failed to restore Dyn Traffic Director (%s) Response Pool (%s): %w", tdID, saved.ID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package dyn
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Shopify/go-dyn/pkg/dyn"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// testDynClientList returns a client list whose single client talks to a fake
+// Dyn API served by handler. The resource the handler is given is the path of
+// the request relative to /REST/.
+func testDynClientList(t *testing.T, handler func(method, resource string, body map[string]interface{}) (interface{}, int)) accessControlledClientList {
+	t.Helper()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := make(map[string]interface{})
+		json.NewDecoder(r.Body).Decode(&body)
+
+		data, status := handler(r.Method, strings.TrimPrefix(r.URL.Path, "/REST/"), body)
+		w.WriteHeader(status)
+		if status != http.StatusOK {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"status": "failure",
+				"msgs":   []map[string]string{{"LVL": "ERROR", "ERR_CD": dyn.ErrorCodeNotFound, "INFO": "not found"}},
+			})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "success", "data": data})
+	}))
+	t.Cleanup(ts.Close)
+
+	client := dyn.NewClient()
+	client.BaseURL, _ = url.Parse(ts.URL)
+
+	return accessControlledClientList{
+		Mutex:     &sync.Mutex{},
+		Semaphore: make(chan int, 1),
+		Clients:   []*dyn.Client{client},
+	}
+}
+
+func TestResourceDynTrafficDirectorMaintenanceRoundTrip(t *testing.T) {
+	objects := map[string]map[string]interface{}{
+		"DSFRecord/td/r1":       {"dsf_record_id": "r1", "master_line": "192.0.2.1", "eligible": "true", "automation": "auto_down"},
+		"DSFResponsePool/td/p1": {"dsf_response_pool_id": "p1", "label": "pool", "eligible": "true", "automation": "auto"},
+	}
+	clientList := testDynClientList(t, func(method, resource string, body map[string]interface{}) (interface{}, int) {
+		if resource == "DSF/td" {
+			return map[string]interface{}{"service_id": "td", "label": "td"}, http.StatusOK
+		}
+
+		object, ok := objects[resource]
+		if !ok {
+			return nil, http.StatusNotFound
+		}
+		if method == http.MethodPut {
+			object["eligible"] = body["eligible"]
+			object["automation"] = body["automation"]
+		}
+		return object, http.StatusOK
+	})
+
+	assertState := func(resource, eligible, automation string) {
+		t.Helper()
+		if object := objects[resource]; object["eligible"] != eligible || object["automation"] != automation {
+			t.Errorf("expected %s to be eligible: %s, automation: %s, got eligible: %s, automation: %s", resource, eligible, automation, object["eligible"], object["automation"])
+		}
+	}
+
+	ctx := context.Background()
+	r := resourceDynTrafficDirectorMaintenance()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"traffic_director_id": "td",
+		"record_ids":          []interface{}{"r1"},
+		"response_pool_ids":   []interface{}{"p1"},
+	})
+
+	if diags := r.CreateContext(ctx, d, clientList); diags.HasError() {
+		t.Fatalf("unexpected create error: %v", diags)
+	}
+	assertState("DSFRecord/td/r1", "false", "manual")
+	assertState("DSFResponsePool/td/p1", "false", "manual")
+
+	// Someone puts the record back in rotation behind Terraform's back.
+	objects["DSFRecord/td/r1"]["eligible"] = "true"
+	objects["DSFRecord/td/r1"]["automation"] = "auto"
+
+	if diags := r.ReadContext(ctx, d, clientList); diags.HasError() {
+		t.Fatalf("unexpected read error: %v", diags)
+	}
+	if d.Get("drained").(bool) {
+		t.Fatalf("expected the maintenance not to be drained anymore")
+	}
+
+	if diags := r.UpdateContext(ctx, d, clientList); diags.HasError() {
+		t.Fatalf("unexpected update error: %v", diags)
+	}
+	assertState("DSFRecord/td/r1", "false", "manual")
+	if !d.Get("drained").(bool) {
+		t.Errorf("expected the maintenance to be drained again")
+	}
+	if automation := d.Get("saved_record.0.automation"); automation != "auto_down" {
+		t.Errorf("expected the saved record state to be kept, got automation %v", automation)
+	}
+
+	if diags := r.DeleteContext(ctx, d, clientList); diags.HasError() {
+		t.Fatalf("unexpected delete error: %v", diags)
+	}
+	assertState("DSFRecord/td/r1", "true", "auto_down")
+	assertState("DSFResponsePool/td/p1", "true", "auto")
+}
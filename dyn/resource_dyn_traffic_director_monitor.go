@@ -1,21 +1,32 @@
 package dyn
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"sort"
+	"strings"
 
 	"github.com/Shopify/go-dyn/pkg/dyn"
-	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func resourceDynTrafficDirectorMonitor() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceDynTrafficDirectorMonitorCreate,
-		Read:   resourceDynTrafficDirectorMonitorRead,
-		Update: resourceDynTrafficDirectorMonitorUpdate,
-		Delete: resourceDynTrafficDirectorMonitorDelete,
+	r := &schema.Resource{
+		CreateContext: resourceDynTrafficDirectorMonitorCreate,
+		ReadContext:   resourceDynTrafficDirectorMonitorRead,
+		UpdateContext: resourceDynTrafficDirectorMonitorUpdate,
+		DeleteContext: resourceDynTrafficDirectorMonitorDelete,
+
+		CustomizeDiff: resourceDynTrafficDirectorMonitorCustomizeDiff,
+
+		SchemaVersion: 1,
 
 		Schema: map[string]*schema.Schema{
+			"on_existing": onExistingSchema(),
+
 			"label": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -34,9 +45,10 @@ func resourceDynTrafficDirectorMonitor() *schema.Resource {
 			},
 
 			"protocol": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "HTTP",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      dyn.MonitorProtocolHTTP,
+				ValidateFunc: validateStringInSlice(trafficDirectorMonitorProtocols()),
 			},
 
 			"probe_interval": {
@@ -70,15 +82,91 @@ func resourceDynTrafficDirectorMonitor() *schema.Resource {
 				Optional: true,
 			},
 
+			// Dyn fills in port and timeout when they aren't given, so
+			// they're left to it unless configured.
 			"port": {
 				Type:     schema.TypeInt,
 				Optional: true,
+				Computed: true,
+			},
+
+			"timeout": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateIntAtLeast(1),
+			},
+
+			"force_detach": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"services": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Computed: true,
 			},
 		},
 	}
+	r.StateUpgraders = trafficDirectorStateUpgraders(r)
+
+	return r
+}
+
+// trafficDirectorMonitorProtocolOptions lists, for each protocol supported
+// by Dyn, the probe options that apply to it.
+var trafficDirectorMonitorProtocolOptions = map[string][]string{
+	dyn.MonitorProtocolHTTP:  {"header", "host", "expected", "path", "port", "timeout"},
+	dyn.MonitorProtocolHTTPS: {"header", "host", "expected", "path", "port", "timeout"},
+	dyn.MonitorProtocolTCP:   {"expected", "port", "timeout"},
+	dyn.MonitorProtocolSMTP:  {"port", "timeout"},
+	dyn.MonitorProtocolPing:  {"timeout"},
+}
+
+func trafficDirectorMonitorProtocols() []string {
+	protocols := make([]string, 0, len(trafficDirectorMonitorProtocolOptions))
+	for protocol := range trafficDirectorMonitorProtocolOptions {
+		protocols = append(protocols, protocol)
+	}
+	sort.Strings(protocols)
+
+	return protocols
+}
+
+func resourceDynTrafficDirectorMonitorCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("protocol") {
+		return nil
+	}
+
+	protocol := d.Get("protocol").(string)
+	supported := make(map[string]bool)
+	for _, option := range trafficDirectorMonitorProtocolOptions[protocol] {
+		supported[option] = true
+	}
+
+	// port and timeout are computed, the values Dyn filled in for another
+	// protocol mustn't count as set.
+	config := d.GetRawConfig()
+	for _, option := range trafficDirectorMonitorProtocolOptions[dyn.MonitorProtocolHTTP] {
+		_, ok := d.GetOk(option)
+		if !config.IsNull() {
+			ok = resourceDynConfigured(config, cty.GetAttrPath(option))
+		}
+		if ok && !supported[option] {
+			return fmt.Errorf("%q is not supported by %s monitors", option, protocol)
+		}
+	}
+
+	return nil
 }
 
 func resourceDynTrafficDirectorMonitorOptions(d *schema.ResourceData) dyn.TrafficDirectorMonitorOptionSetter {
+	config := d.GetRawConfig()
+
 	return func(req *dyn.TrafficDirectorMonitorCURequest) {
 		if d.Get("retries") != nil {
 			req.Retries = d.Get("retries").(int)
@@ -117,47 +205,71 @@ func resourceDynTrafficDirectorMonitorOptions(d *schema.ResourceData) dyn.Traffi
 		if path != "" {
 			req.Options.Path = path
 		}
-		if d.Get("port") != nil {
+		if resourceDynConfigured(config, cty.GetAttrPath("port")) {
 			req.Options.Port = d.Get("port").(int)
 		}
+		if resourceDynConfigured(config, cty.GetAttrPath("timeout")) {
+			req.Options.Timeout = d.Get("timeout").(int)
+		}
 	}
 }
 
-func resourceDynTrafficDirectorMonitorCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceDynTrafficDirectorMonitorCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	clientList := meta.(accessControlledClientList)
-	client, err := clientList.Acquire()
+	client, err := clientList.AcquireContext(ctx)
 	if err != nil {
-		return err
+		return resourceDynError(ctx, err)
 	}
 
 	label := d.Get("label").(string)
 	optionsSetter := resourceDynTrafficDirectorMonitorOptions(d)
 
+	existing, err := client.FindTrafficDirectorMonitors(label)
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, fmt.Errorf("Failed to look up Dyn Traffic Director Monitors labeled %q: %w", label, err))
+	}
+	ids := make([]string, len(existing))
+	for idx, tdm := range existing {
+		ids[idx] = tdm.MonitorID
+	}
+	id, err := existingTrafficDirectorObject(d.Get("on_existing").(string), "Traffic Director Monitor", label, ids)
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, err)
+	}
+	if id != "" {
+		log.Printf("[DEBUG] Adopting existing Dyn Traffic Director Monitor (%s) labeled: %s", id, label)
+		d.SetId(id)
+		clientList.Release(client)
+		return resourceDynTrafficDirectorMonitorUpdate(ctx, d, meta)
+	}
+
 	log.Printf("[DEBUG] Dyn Traffic Director Monitor create configuration: label: %s", label)
 
 	tdm, err := client.CreateTrafficDirectorMonitor(label, optionsSetter)
 	if err != nil {
 		clientList.Release(client)
-		return fmt.Errorf("Failed to create Dyn Traffic Director Monitor: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Failed to create Dyn Traffic Director Monitor: %w", err))
 	}
 
 	d.SetId(tdm.MonitorID)
 	clientList.Release(client)
-	return resourceDynTrafficDirectorMonitorRead(d, meta)
+	return resourceDynTrafficDirectorMonitorRead(ctx, d, meta)
 }
 
-func resourceDynTrafficDirectorMonitorRead(d *schema.ResourceData, meta interface{}) error {
+func resourceDynTrafficDirectorMonitorRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	clientList := meta.(accessControlledClientList)
-	client, err := clientList.Acquire()
+	client, err := clientList.AcquireContext(ctx)
 	if err != nil {
-		return err
+		return resourceDynError(ctx, err)
 	}
 	defer clientList.Release(client)
 
 	log.Printf("[DEBUG] Getting Traffic Director Monitor (%s)", d.Id())
 	tdm, err := client.GetTrafficDirectorMonitor(d.Id())
 	if err != nil {
-		return fmt.Errorf("Couldn't find Dyn Traffic Director Monitor: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Couldn't find Dyn Traffic Director Monitor: %w", err))
 	}
 
 	d.Set("label", tdm.Label)
@@ -171,15 +283,17 @@ func resourceDynTrafficDirectorMonitorRead(d *schema.ResourceData, meta interfac
 	d.Set("expected", tdm.Options.Expected)
 	d.Set("path", tdm.Options.Path)
 	d.Set("port", tdm.Options.Port)
+	d.Set("timeout", tdm.Options.Timeout)
+	d.Set("services", tdm.Services)
 
 	return nil
 }
 
-func resourceDynTrafficDirectorMonitorUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceDynTrafficDirectorMonitorUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	clientList := meta.(accessControlledClientList)
-	client, err := clientList.Acquire()
+	client, err := clientList.AcquireContext(ctx)
 	if err != nil {
-		return err
+		return resourceDynError(ctx, err)
 	}
 
 	label := d.Get("label").(string)
@@ -190,28 +304,92 @@ func resourceDynTrafficDirectorMonitorUpdate(d *schema.ResourceData, meta interf
 	td, err := client.UpdateTrafficDirectorMonitor(d.Id(), label, optionsSetter)
 	if err != nil {
 		clientList.Release(client)
-		return fmt.Errorf("Failed to update Dyn Traffic Director Monitor: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Failed to update Dyn Traffic Director Monitor: %w", err))
 	}
 
 	d.SetId(td.MonitorID)
 	clientList.Release(client)
-	return resourceDynTrafficDirectorMonitorRead(d, meta)
+	return resourceDynTrafficDirectorMonitorRead(ctx, d, meta)
 }
 
-func resourceDynTrafficDirectorMonitorDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceDynTrafficDirectorMonitorDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	clientList := meta.(accessControlledClientList)
-	client, err := clientList.Acquire()
+	client, err := clientList.AcquireContext(ctx)
 	if err != nil {
-		return err
+		return resourceDynError(ctx, err)
 	}
 	defer clientList.Release(client)
 
+	log.Printf("[DEBUG] Getting Traffic Director Monitor (%s) services before deletion", d.Id())
+	tdm, err := client.GetTrafficDirectorMonitor(d.Id())
+	if err != nil {
+		return resourceDynError(ctx, fmt.Errorf("Couldn't find Dyn Traffic Director Monitor: %w", err))
+	}
+
+	if len(tdm.Services) > 0 {
+		if !d.Get("force_detach").(bool) {
+			return resourceDynError(ctx, fmt.Errorf("Couldn't delete Dyn Traffic Director Monitor (%s): still used by Traffic Director service(s) %s; "+
+				"remove it from their record sets first or set force_detach", d.Id(), trafficDirectorServicesDescription(client, tdm.Services)))
+		}
+
+		err = resourceDynTrafficDirectorMonitorDetach(clientList, client, tdm)
+		if err != nil {
+			return resourceDynError(ctx, fmt.Errorf("Couldn't detach Dyn Traffic Director Monitor (%s): %w", d.Id(), err))
+		}
+	}
+
 	log.Printf("[DEBUG] Deleting Traffic Director Monitor (%s)", d.Id())
 	err = client.DeleteTrafficDirectorMonitor(d.Id())
 	if err != nil {
-		return fmt.Errorf("Couldn't delete Dyn Traffic Director Monitor: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Couldn't delete Dyn Traffic Director Monitor: %w", err))
 	}
 
 	d.SetId("")
 	return nil
 }
+
+// resourceDynTrafficDirectorMonitorDetach removes the monitor from every
+// record set of the services using it.
+func resourceDynTrafficDirectorMonitorDetach(clientList accessControlledClientList, client *dyn.Client, tdm *dyn.TrafficDirectorMonitor) error {
+	for _, serviceID := range tdm.Services {
+		log.Printf("[DEBUG] Getting Traffic Director (%s) to detach Monitor (%s)", serviceID, tdm.MonitorID)
+		td, err := client.GetTrafficDirector(serviceID)
+		if err != nil {
+			return fmt.Errorf("Couldn't find Dyn Traffic Director (%s): %w", serviceID, err)
+		}
+
+		for _, responsePool := range td.ResponsePools {
+			for _, recordSet := range responsePool.RecordSets {
+				if recordSet.MonitorID != tdm.MonitorID {
+					continue
+				}
+
+				log.Printf("[DEBUG] Detaching Monitor (%s) from Traffic Director (%s) Record Set (%s)", tdm.MonitorID, serviceID, recordSet.RecordSetID)
+				err = client.DetachTrafficDirectorRecordSetMonitor(serviceID, recordSet.RecordSetID)
+				clientList.InvalidateTrafficDirector(serviceID)
+				if err != nil {
+					return fmt.Errorf("Couldn't detach from Dyn Traffic Director (%s) Record Set (%s): %w", serviceID, recordSet.RecordSetID, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// trafficDirectorServicesDescription formats the given service IDs as
+// "label (id)" for error messages, falling back to the bare ID when the
+// service can't be read.
+func trafficDirectorServicesDescription(client *dyn.Client, serviceIDs []string) string {
+	descriptions := make([]string, len(serviceIDs))
+	for idx, serviceID := range serviceIDs {
+		descriptions[idx] = serviceID
+
+		td, err := client.GetTrafficDirector(serviceID)
+		if err == nil && td.Label != "" {
+			descriptions[idx] = fmt.Sprintf("%s (%s)", td.Label, serviceID)
+		}
+	}
+
+	return strings.Join(descriptions, ", ")
+}
@@ -0,0 +1,39 @@
+package dyn
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestResourceDynTrafficDirectorMonitorDynDefaults(t *testing.T) {
+	r := resourceDynTrafficDirectorMonitor()
+	state := &terraform.InstanceState{
+		ID: "monitor",
+		Attributes: map[string]string{
+			"id":             "monitor",
+			"on_existing":    onExistingError,
+			"force_detach":   "false",
+			"label":          "web",
+			"protocol":       "HTTP",
+			"response_count": "0",
+			"retries":        "0",
+			"probe_interval": "60",
+			"path":           "/health",
+			"services.#":     "0",
+			// Filled in by Dyn.
+			"port":    "80",
+			"timeout": "10",
+		},
+	}
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{"label": "web", "path": "/health"})
+	diff, err := r.Diff(context.Background(), state, config, nil)
+	if err != nil {
+		t.Fatalf("unexpected diff error: %s", err)
+	}
+	if !diff.Empty() {
+		t.Errorf("expected the port and timeout of Dyn not to show a diff, got %v", diff)
+	}
+}
@@ -0,0 +1,150 @@
+package dyn
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceDynTrafficDirectorNode() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDynTrafficDirectorNodeCreate,
+		ReadContext:   resourceDynTrafficDirectorNodeRead,
+		DeleteContext: resourceDynTrafficDirectorNodeDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceDynTrafficDirectorNodeImportState,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"traffic_director_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"zone": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"fqdn": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+func resourceDynTrafficDirectorNodeCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	clientList := meta.(accessControlledClientList)
+	client, err := clientList.AcquireContext(ctx)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+
+	tdID := d.Get("traffic_director_id").(string)
+	zone := d.Get("zone").(string)
+	fqdn := d.Get("fqdn").(string)
+
+	log.Printf("[DEBUG] Dyn Traffic Director (%s) Node create configuration: zone: %s; fqdn: %s", tdID, zone, fqdn)
+
+	_, err = client.AddTrafficDirectorNode(tdID, zone, fqdn)
+	clientList.InvalidateTrafficDirector(tdID)
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, fmt.Errorf("Failed to attach Dyn Traffic Director Node: %w", err))
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s/%s", tdID, zone, fqdn))
+	clientList.Release(client)
+	return resourceDynTrafficDirectorNodeRead(ctx, d, meta)
+}
+
+func resourceDynTrafficDirectorNodeRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	clientList := meta.(accessControlledClientList)
+	client, err := clientList.AcquireContext(ctx)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+	defer clientList.Release(client)
+
+	tdID := d.Get("traffic_director_id").(string)
+	zone := d.Get("zone").(string)
+	fqdn := d.Get("fqdn").(string)
+
+	log.Printf("[DEBUG] Getting Traffic Director (%s) Nodes", tdID)
+	nodes, err := client.GetTrafficDirectorNodes(tdID)
+	if err != nil {
+		return resourceDynError(ctx, fmt.Errorf("Couldn't find Dyn Traffic Director Nodes: %w", err))
+	}
+
+	for _, node := range nodes {
+		if node.Zone == zone && strings.TrimSuffix(node.FQDN, ".") == strings.TrimSuffix(fqdn, ".") {
+			return nil
+		}
+	}
+
+	log.Printf("[WARN] Dyn Traffic Director (%s) Node %s/%s is gone, removing from state", tdID, zone, fqdn)
+	d.SetId("")
+	return nil
+}
+
+func resourceDynTrafficDirectorNodeDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	clientList := meta.(accessControlledClientList)
+	client, err := clientList.AcquireContext(ctx)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+	defer clientList.Release(client)
+
+	tdID := d.Get("traffic_director_id").(string)
+	zone := d.Get("zone").(string)
+	fqdn := d.Get("fqdn").(string)
+
+	log.Printf("[DEBUG] Detaching Traffic Director (%s) Node: zone: %s; fqdn: %s", tdID, zone, fqdn)
+	err = client.DeleteTrafficDirectorNode(tdID, zone, fqdn)
+	clientList.InvalidateTrafficDirector(tdID)
+	if err != nil {
+		return resourceDynError(ctx, fmt.Errorf("Couldn't detach Dyn Traffic Director Node: %w", err))
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func resourceDynTrafficDirectorNodeImportState(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	results := make([]*schema.ResourceData, 1, 1)
+
+	clientList := meta.(accessControlledClientList)
+	ctx, cancel := context.WithTimeout(ctx, d.Timeout(schema.TimeoutRead))
+	defer cancel()
+	client, err := clientList.AcquireContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer clientList.Release(client)
+
+	values := strings.Split(d.Id(), "/")
+	if len(values) != 3 {
+		return nil, fmt.Errorf("invalid id provided, expected format: {traffic_director}/{zone}/{fqdn}")
+	}
+
+	td, err := getTrafficDirector(client, values[0])
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s/%s", td.ServiceID, values[1], values[2]))
+	d.Set("traffic_director_id", td.ServiceID)
+	d.Set("zone", values[1])
+	d.Set("fqdn", values[2])
+	results[0] = d
+
+	return results, nil
+}
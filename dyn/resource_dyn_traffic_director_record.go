@@ -1,39 +1,56 @@
 package dyn
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
 
 	"github.com/Shopify/go-dyn/pkg/dyn"
-	"github.com/hashicorp/terraform/helper/schema"
-	// "github.com/hashicorp/terraform/helper/validation"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	// "github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceDynTrafficDirectorRecord() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceDynTrafficDirectorRecordCreate,
-		Read:   resourceDynTrafficDirectorRecordRead,
-		Update: resourceDynTrafficDirectorRecordUpdate,
-		Delete: resourceDynTrafficDirectorRecordDelete,
+	r := &schema.Resource{
+		CreateContext: resourceDynTrafficDirectorRecordCreate,
+		ReadContext:   resourceDynTrafficDirectorRecordRead,
+		UpdateContext: resourceDynTrafficDirectorRecordUpdate,
+		DeleteContext: resourceDynTrafficDirectorRecordDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceDynTrafficDirectorRecordImportState,
+			StateContext: resourceDynTrafficDirectorRecordImportState,
 		},
 
+		CustomizeDiff: resourceDynTrafficDirectorRecordCustomizeDiff,
+
+		SchemaVersion: 1,
+
 		Schema: map[string]*schema.Schema{
+			"on_existing": onExistingSchema(),
+
 			"traffic_director_id": {
 				Type:     schema.TypeString,
 				Required: true,
+				ForceNew: true,
 			},
 
 			"record_set_id": {
 				Type:     schema.TypeString,
 				Required: true,
+				ForceNew: true,
 			},
 
 			"master_line": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: trafficDirectorRecordRDataTypes(),
+				DiffSuppressFunc: func(k, oldV, newV string, d *schema.ResourceData) bool {
+					return masterLinesEquivalent(oldV, newV)
+				},
 			},
 
 			"label": {
@@ -41,10 +58,13 @@ func resourceDynTrafficDirectorRecord() *schema.Resource {
 				Optional: true,
 			},
 
+			// weight, automation and eligible are left to Dyn when they
+			// aren't configured, so that dyn_traffic_director_maintenance
+			// and dyn_traffic_director_weight_shift can change them.
 			"weight": {
 				Type:     schema.TypeInt,
-				Default:  -1,
 				Optional: true,
+				Computed: true,
 			},
 
 			"endpoints": {
@@ -64,33 +84,121 @@ func resourceDynTrafficDirectorRecord() *schema.Resource {
 			"automation": {
 				Type:     schema.TypeString,
 				Optional: true,
-				Default:  "auto",
+				Computed: true,
 				// ValidateFunc: validation.StringInSlice([]string{"auto", "auto_down", "manual"}, false),
 			},
 
 			"eligible": {
 				Type:     schema.TypeBool,
 				Optional: true,
-				Default:  true,
+				Computed: true,
 			},
 		},
 	}
+
+	for rdataType, blockSchema := range trafficDirectorRecordRDataSchema() {
+		r.Schema[rdataType] = blockSchema
+	}
+	r.StateUpgraders = trafficDirectorStateUpgraders(r)
+
+	return r
+}
+
+// resourceDynTrafficDirectorRecordMasterLine returns the master line to send
+// to Dyn, rendered from the typed rdata block when one is set.
+func resourceDynTrafficDirectorRecordMasterLine(d *schema.ResourceData) (string, error) {
+	rdataType, rdata := trafficDirectorRecordRData(d.GetOk)
+	if rdataType == "" {
+		return d.Get("master_line").(string), nil
+	}
+
+	return trafficDirectorRecordMasterLine(rdataType, rdata)
+}
+
+func resourceDynTrafficDirectorRecordCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	for _, rdataType := range trafficDirectorRecordRDataTypes() {
+		if !d.NewValueKnown(rdataType) {
+			return d.SetNewComputed("master_line")
+		}
+	}
+
+	rdataType, rdata := trafficDirectorRecordRData(d.GetOk)
+	if rdataType == "" {
+		// master_line is computed, so once in state it can't be told
+		// apart from a configured one through Get.
+		if config := d.GetRawConfig(); !config.IsNull() && !resourceDynConfigured(config, cty.GetAttrPath("master_line")) {
+			return fmt.Errorf("One of master_line or %s must be set", strings.Join(trafficDirectorRecordRDataTypes(), ", "))
+		}
+		return resourceDynTrafficDirectorRecordCheckTree(ctx, d, meta, trafficDirectorDiffString(d, "master_line"), "master_line")
+	}
+
+	masterLine, err := trafficDirectorRecordMasterLine(rdataType, rdata)
+	if err != nil {
+		return fmt.Errorf("%s: %w", rdataType, err)
+	}
+
+	if err := resourceDynTrafficDirectorRecordCheckTree(ctx, d, meta, masterLine, rdataType); err != nil {
+		return err
+	}
+
+	if !masterLinesEquivalent(d.Get("master_line").(string), masterLine) {
+		return d.SetNew("master_line", masterLine)
+	}
+
+	return nil
+}
+
+// resourceDynTrafficDirectorRecordCheckTree checks the record against the
+// record set it goes into, reporting master line problems against key.
+func resourceDynTrafficDirectorRecordCheckTree(ctx context.Context, d *schema.ResourceDiff, meta interface{}, masterLine, key string) error {
+	keys := append([]string{"record_set_id", "label", "master_line"}, trafficDirectorRecordRDataTypes()...)
+	tree, err := getTrafficDirectorDiffTree(ctx, d, meta, keys...)
+	if err != nil || tree == nil {
+		return err
+	}
+	tree.Adopting = trafficDirectorDiffAdopting(d)
+
+	return tree.checkRecord(d.Id(), trafficDirectorDiffString(d, "record_set_id"), trafficDirectorDiffString(d, "label"), masterLine, key)
+}
+
+// resourceDynTrafficDirectorRecordCheckRDataClass checks the master line that's
+// about to be sent against the rdata_class of the record set.
+func resourceDynTrafficDirectorRecordCheckRDataClass(clientList accessControlledClientList, client *dyn.Client, d *schema.ResourceData, tdID, rsID, masterLine string) error {
+	key := "master_line"
+	if rdataType, _ := trafficDirectorRecordRData(d.GetOk); rdataType != "" {
+		key = rdataType
+	}
+
+	log.Printf("[DEBUG] Getting Traffic Director (%s) Record Set (%s) to check rdata class", tdID, rsID)
+	tdrs, err := clientList.GetTrafficDirectorRecordSet(client, tdID, rsID)
+	if err != nil {
+		return fmt.Errorf("Couldn't find Dyn Traffic Director (%s) Record Set (%s): %w", tdID, rsID, err)
+	}
+
+	if err := checkMasterLineRDataClass(tdrs.RDataClass, masterLine); err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+
+	return nil
 }
 
 func resourceDynTrafficDirectorRecordOptions(d *schema.ResourceData) dyn.TrafficDirectorRecordOptionSetter {
+	config := d.GetRawConfig()
+
 	return func(req *dyn.TrafficDirectorRecordCURequest) {
 		label := d.Get("label").(string)
 		if label != "" {
 			req.Label = label
 		}
 
-		weight := d.Get("weight").(int)
-		if weight > 0 {
-			req.Weight = weight
-			req.Eligible = "true"
-		} else if weight == 0 {
-			req.Weight = 1
-			req.Eligible = "false"
+		if resourceDynConfigured(config, cty.GetAttrPath("weight")) {
+			weight := d.Get("weight").(int)
+			if weight > 0 {
+				req.Weight = weight
+			} else if weight == 0 {
+				req.Weight = 1
+				req.Eligible = "false"
+			}
 		}
 
 		endpoints_interface := d.Get("endpoints").([]interface{})
@@ -107,99 +215,153 @@ func resourceDynTrafficDirectorRecordOptions(d *schema.ResourceData) dyn.Traffic
 			req.EndpointUpCount = endpointUpCount
 		}
 
-		if d.Get("eligible") != nil {
-			if d.Get("eligible").(bool) {
-				req.Eligible = "true"
-			} else {
-				req.Eligible = "false"
-			}
+		if resourceDynConfigured(config, cty.GetAttrPath("eligible")) {
+			req.Eligible = strconv.FormatBool(d.Get("eligible").(bool))
 		}
 
-		automation := d.Get("automation").(string)
-		if automation != "" {
-			req.Automation = automation
+		if resourceDynConfigured(config, cty.GetAttrPath("automation")) {
+			req.Automation = d.Get("automation").(string)
 		}
 	}
 }
 
-func resourceDynTrafficDirectorRecordCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceDynTrafficDirectorRecordCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	clientList := meta.(accessControlledClientList)
-	client, err := clientList.Acquire()
+	client, err := clientList.AcquireContext(ctx)
 	if err != nil {
-		return err
+		return resourceDynError(ctx, err)
 	}
 
 	tdID := d.Get("traffic_director_id").(string)
 	rsID := d.Get("record_set_id").(string)
-	masterLine := d.Get("master_line").(string)
+	masterLine, err := resourceDynTrafficDirectorRecordMasterLine(d)
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, err)
+	}
 	optionsSetter := resourceDynTrafficDirectorRecordOptions(d)
 
-	log.Printf("[DEBUG] Dyn Traffic Director (%s) Record create configuration: record_set_id: %s; master_line: %s", tdID, masterLine)
+	// The record set may not have been known when this was planned.
+	err = resourceDynTrafficDirectorRecordCheckRDataClass(clientList, client, d, tdID, rsID, masterLine)
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, err)
+	}
+
+	// Records don't need a label, those without one are told apart by their
+	// master line within the record set.
+	label := d.Get("label").(string)
+	responsePools, err := clientList.GetTrafficDirectorResponsePools(client, tdID)
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, fmt.Errorf("Couldn't get Dyn Traffic Director Response Pools: %w", err))
+	}
+	ids := make([]string, 0)
+	for _, responsePool := range responsePools {
+		for _, recordSet := range responsePool.RecordSets {
+			if recordSet.RecordSetID != rsID {
+				continue
+			}
+			for _, record := range recordSet.Records {
+				if (label != "" && record.Label == label) || (label == "" && record.Label == "" && masterLinesEquivalent(record.MasterLine, masterLine)) {
+					ids = append(ids, record.RecordID)
+				}
+			}
+		}
+	}
+	if label == "" {
+		label = masterLine
+	}
+	id, err := existingTrafficDirectorObject(d.Get("on_existing").(string), "Traffic Director Record", label, ids)
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, err)
+	}
+	if id != "" {
+		log.Printf("[DEBUG] Adopting existing Dyn Traffic Director (%s) Record (%s): %s", tdID, id, label)
+		d.SetId(id)
+		clientList.Release(client)
+		return resourceDynTrafficDirectorRecordUpdate(ctx, d, meta)
+	}
+
+	log.Printf("[DEBUG] Dyn Traffic Director (%s) Record create configuration: record_set_id: %s; master_line: %s", tdID, rsID, masterLine)
 
 	tdrp, err := client.CreateTrafficDirectorRecord(tdID, rsID, masterLine, optionsSetter)
+	clientList.InvalidateTrafficDirector(tdID)
 	if err != nil {
 		clientList.Release(client)
-		return fmt.Errorf("Failed to create Dyn Traffic Director Record: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Failed to create Dyn Traffic Director Record: %w", err))
 	}
 
 	d.SetId(tdrp.RecordID)
 	clientList.Release(client)
-	return resourceDynTrafficDirectorRecordRead(d, meta)
+	return resourceDynTrafficDirectorRecordRead(ctx, d, meta)
 }
 
-func resourceDynTrafficDirectorRecordRead(d *schema.ResourceData, meta interface{}) error {
+func resourceDynTrafficDirectorRecordRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	clientList := meta.(accessControlledClientList)
-	client, err := clientList.Acquire()
+	client, err := clientList.AcquireContext(ctx)
 	if err != nil {
-		return err
+		return resourceDynError(ctx, err)
 	}
 	defer clientList.Release(client)
 
 	tdID := d.Get("traffic_director_id").(string)
 
 	log.Printf("[DEBUG] Getting Traffic Director (%s) Record (%s)", tdID, d.Id())
-	tdr, err := client.GetTrafficDirectorRecord(tdID, d.Id())
+	tdr, err := clientList.GetTrafficDirectorRecord(client, tdID, d.Id())
 	if err != nil {
-		return fmt.Errorf("Couldn't find Dyn Traffic Director (%s) Record (%s): %s", tdID, d.Id(), err)
+		return resourceDynError(ctx, fmt.Errorf("Couldn't find Dyn Traffic Director (%s) Record (%s): %w", tdID, d.Id(), err))
 	}
 
 	err = resourceDynTrafficDirectorRecordToResourceData(tdr, d)
 	if err != nil {
-		return fmt.Errorf("Couldn't convert Dyn Traffic Director Record: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Couldn't convert Dyn Traffic Director Record: %w", err))
 	}
 
 	return nil
 }
 
-func resourceDynTrafficDirectorRecordUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceDynTrafficDirectorRecordUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	clientList := meta.(accessControlledClientList)
-	client, err := clientList.Acquire()
+	client, err := clientList.AcquireContext(ctx)
 	if err != nil {
-		return err
+		return resourceDynError(ctx, err)
 	}
 
 	tdID := d.Get("traffic_director_id").(string)
-	masterLine := d.Get("master_line").(string)
+	masterLine, err := resourceDynTrafficDirectorRecordMasterLine(d)
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, err)
+	}
 	optionsSetter := resourceDynTrafficDirectorRecordOptions(d)
 
+	err = resourceDynTrafficDirectorRecordCheckRDataClass(clientList, client, d, tdID, d.Get("record_set_id").(string), masterLine)
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, err)
+	}
+
 	log.Printf("[DEBUG] Dyn Traffic Director (%s) Record (%s) update configuration: master_line: %s", tdID, d.Id(), masterLine)
 
 	tdr, err := client.UpdateTrafficDirectorRecord(tdID, d.Id(), masterLine, optionsSetter)
+	clientList.InvalidateTrafficDirector(tdID)
 	if err != nil {
 		clientList.Release(client)
-		return fmt.Errorf("Failed to update Dyn Traffic Director Record: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Failed to update Dyn Traffic Director Record: %w", err))
 	}
 
 	d.SetId(tdr.RecordID)
 	clientList.Release(client)
-	return resourceDynTrafficDirectorRecordRead(d, meta)
+	return resourceDynTrafficDirectorRecordRead(ctx, d, meta)
 }
 
-func resourceDynTrafficDirectorRecordDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceDynTrafficDirectorRecordDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	clientList := meta.(accessControlledClientList)
-	client, err := clientList.Acquire()
+	client, err := clientList.AcquireContext(ctx)
 	if err != nil {
-		return err
+		return resourceDynError(ctx, err)
 	}
 	defer clientList.Release(client)
 
@@ -207,19 +369,22 @@ func resourceDynTrafficDirectorRecordDelete(d *schema.ResourceData, meta interfa
 
 	log.Printf("[DEBUG] Deleting Traffic Director (%s) Record (%s)", tdID, d.Id())
 	err = client.DeleteTrafficDirectorRecord(tdID, d.Id())
+	clientList.InvalidateTrafficDirector(tdID)
 	if err != nil {
-		return fmt.Errorf("Couldn't delete Dyn Traffic Director Record: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Couldn't delete Dyn Traffic Director Record: %w", err))
 	}
 
 	d.SetId("")
 	return nil
 }
 
-func resourceDynTrafficDirectorRecordImportState(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceDynTrafficDirectorRecordImportState(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	results := make([]*schema.ResourceData, 1, 1)
 
 	clientList := meta.(accessControlledClientList)
-	client, err := clientList.Acquire()
+	ctx, cancel := context.WithTimeout(ctx, d.Timeout(schema.TimeoutRead))
+	defer cancel()
+	client, err := clientList.AcquireContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -230,52 +395,28 @@ func resourceDynTrafficDirectorRecordImportState(d *schema.ResourceData, meta in
 		return nil, fmt.Errorf("invalid id provided, expected format: {traffic_director}/{response_pool}[/{record_set}]/{record}")
 	}
 
-	log.Printf("[DEBUG] Trying to get Traffic Director using id: %s", values[0])
-	td, err := client.GetTrafficDirector(values[0])
+	td, err := getTrafficDirector(client, values[0])
 	if err != nil {
-		log.Printf("[DEBUG] Error: %s / Trying to get Traffic Director using label: %s", err, values[0])
-		td, err = client.FindTrafficDirector(values[0])
-		if err != nil {
-			return nil, fmt.Errorf("Couldn't find Dyn Traffic Director: %s", err)
-		}
+		return nil, err
 	}
 
-	var tdrp *dyn.TrafficDirectorResponsePool
-	log.Printf("[DEBUG] Trying to get Dyn Traffic Director Response Pool using id/label: %s", values[1])
-	for _, responsePool := range td.ResponsePools {
-		if responsePool.ResponsePoolID == values[1] || responsePool.Label == values[1] {
-			tdrp = responsePool
-			break
-		}
-	}
-	if tdrp == nil {
-		return nil, fmt.Errorf("Couldn't find Dyn Traffic Director (%s) Response Pool: %s", values[0], values[1])
+	tdrp, err := findTrafficDirectorResponsePool(td, values[1])
+	if err != nil {
+		return nil, err
 	}
 
-	var tdrs *dyn.TrafficDirectorRecordSet
+	recordSet := ""
 	if len(values) == 4 {
-		for _, recordSet := range tdrp.RecordSets {
-			if recordSet.RecordSetID == values[2] || recordSet.RDataClass == values[2] {
-				tdrs = recordSet
-				break
-			}
-		}
-	} else if len(tdrp.RecordSets) == 1 {
-		tdrs = tdrp.RecordSets[0]
+		recordSet = values[2]
 	}
-	if tdrs == nil {
-		return nil, fmt.Errorf("Couldn't find Dyn Traffic Director Record Set with ID %s", values)
+	tdrs, err := findTrafficDirectorRecordSet(tdrp, recordSet)
+	if err != nil {
+		return nil, err
 	}
 
-	var tdr *dyn.TrafficDirectorRecord
-	for _, record := range tdrs.Records {
-		if record.RecordID == values[len(values)-1] || record.Label == values[len(values)-1] {
-			tdr = record
-			break
-		}
-	}
-	if tdr == nil {
-		return nil, fmt.Errorf("Couldn't find Dyn Traffic Director Record with ID %s", values)
+	tdr, err := findTrafficDirectorRecord(tdrs, values[len(values)-1])
+	if err != nil {
+		return nil, err
 	}
 
 	d.SetId(tdr.RecordID)
@@ -283,7 +424,7 @@ func resourceDynTrafficDirectorRecordImportState(d *schema.ResourceData, meta in
 	d.Set("record_set_id", tdrs.RecordSetID)
 	err = resourceDynTrafficDirectorRecordToResourceData(tdr, d)
 	if err != nil {
-		return nil, fmt.Errorf("Couldn't convert Dyn Traffic Director Record: %s", err)
+		return nil, fmt.Errorf("Couldn't convert Dyn Traffic Director Record: %w", err)
 	}
 	results[0] = d
 
@@ -1,33 +1,44 @@
 package dyn
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
 
 	"github.com/Shopify/go-dyn/pkg/dyn"
-	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func resourceDynTrafficDirectorRecordSet() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceDynTrafficDirectorRecordSetCreate,
-		Read:   resourceDynTrafficDirectorRecordSetRead,
-		Update: resourceDynTrafficDirectorRecordSetUpdate,
-		Delete: resourceDynTrafficDirectorRecordSetDelete,
+	r := &schema.Resource{
+		CreateContext: resourceDynTrafficDirectorRecordSetCreate,
+		ReadContext:   resourceDynTrafficDirectorRecordSetRead,
+		UpdateContext: resourceDynTrafficDirectorRecordSetUpdate,
+		DeleteContext: resourceDynTrafficDirectorRecordSetDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceDynTrafficDirectorRecordSetImportState,
+			StateContext: resourceDynTrafficDirectorRecordSetImportState,
 		},
 
+		CustomizeDiff: resourceDynTrafficDirectorRecordSetCustomizeDiff,
+
+		SchemaVersion: 1,
+
 		Schema: map[string]*schema.Schema{
+			"on_existing": onExistingSchema(),
+
 			"traffic_director_id": {
 				Type:     schema.TypeString,
 				Required: true,
+				ForceNew: true,
 			},
 
 			"response_pool_id": {
 				Type:     schema.TypeString,
 				Required: true,
+				// Moving a record set to another response pool of the
+				// same service is done in place.
 			},
 
 			"rdata_class": {
@@ -51,6 +62,19 @@ func resourceDynTrafficDirectorRecordSet() *schema.Resource {
 			},
 		},
 	}
+	r.StateUpgraders = trafficDirectorStateUpgraders(r)
+
+	return r
+}
+
+func resourceDynTrafficDirectorRecordSetCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	tree, err := getTrafficDirectorDiffTree(ctx, d, meta, "response_pool_id", "label", "rdata_class")
+	if err != nil || tree == nil {
+		return err
+	}
+	tree.Adopting = trafficDirectorDiffAdopting(d)
+
+	return tree.checkRecordSet(d.Id(), trafficDirectorDiffString(d, "response_pool_id"), trafficDirectorDiffString(d, "label"), trafficDirectorDiffString(d, "rdata_class"))
 }
 
 func resourceDynTrafficDirectorRecordSetOptions(d *schema.ResourceData) dyn.TrafficDirectorRecordSetOptionSetter {
@@ -70,83 +94,124 @@ func resourceDynTrafficDirectorRecordSetOptions(d *schema.ResourceData) dyn.Traf
 	}
 }
 
-func resourceDynTrafficDirectorRecordSetCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceDynTrafficDirectorRecordSetCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	clientList := meta.(accessControlledClientList)
-	client, err := clientList.Acquire()
+	client, err := clientList.AcquireContext(ctx)
 	if err != nil {
-		return err
+		return resourceDynError(ctx, err)
 	}
 
 	tdID := d.Get("traffic_director_id").(string)
 	rdata_class := d.Get("rdata_class").(string)
 	optionsSetter := resourceDynTrafficDirectorRecordSetOptions(d)
 
+	// Record sets don't need a label, but nothing else tells those without
+	// one apart from their siblings of the same rdata class, so they're
+	// never looked up.
+	label := d.Get("label").(string)
+	ids := make([]string, 0)
+	if label != "" {
+		responsePools, err := clientList.GetTrafficDirectorResponsePools(client, tdID)
+		if err != nil {
+			clientList.Release(client)
+			return resourceDynError(ctx, fmt.Errorf("Couldn't get Dyn Traffic Director Response Pools: %w", err))
+		}
+		for _, responsePool := range responsePools {
+			if responsePool.ResponsePoolID != d.Get("response_pool_id").(string) {
+				continue
+			}
+			for _, recordSet := range responsePool.RecordSets {
+				if recordSet.Label == label {
+					ids = append(ids, recordSet.RecordSetID)
+				}
+			}
+		}
+	}
+	id, err := existingTrafficDirectorObject(d.Get("on_existing").(string), "Traffic Director Record Set", label, ids)
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, err)
+	}
+	if id != "" {
+		log.Printf("[DEBUG] Adopting existing Dyn Traffic Director (%s) Record Set (%s) labeled: %s", tdID, id, label)
+		d.SetId(id)
+		clientList.Release(client)
+		return resourceDynTrafficDirectorRecordSetUpdate(ctx, d, meta)
+	}
+
 	log.Printf("[DEBUG] Dyn Traffic Director (%s) Record Set create configuration: rdata_class: %s", tdID, rdata_class)
 
 	tdrp, err := client.CreateTrafficDirectorRecordSet(tdID, rdata_class, optionsSetter)
+	clientList.InvalidateTrafficDirector(tdID)
 	if err != nil {
 		clientList.Release(client)
-		return fmt.Errorf("Failed to create Dyn Traffic Director Record Set: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Failed to create Dyn Traffic Director Record Set: %w", err))
 	}
 
 	d.SetId(tdrp.RecordSetID)
 	clientList.Release(client)
-	return resourceDynTrafficDirectorRecordSetRead(d, meta)
+	return resourceDynTrafficDirectorRecordSetRead(ctx, d, meta)
 }
 
-func resourceDynTrafficDirectorRecordSetRead(d *schema.ResourceData, meta interface{}) error {
+func resourceDynTrafficDirectorRecordSetRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	clientList := meta.(accessControlledClientList)
-	client, err := clientList.Acquire()
+	client, err := clientList.AcquireContext(ctx)
 	if err != nil {
-		return err
+		return resourceDynError(ctx, err)
 	}
 	defer clientList.Release(client)
 
 	tdID := d.Get("traffic_director_id").(string)
 
 	log.Printf("[DEBUG] Getting Traffic Director (%s) Record Set (%s)", tdID, d.Id())
-	tdrs, err := client.GetTrafficDirectorRecordSet(tdID, d.Id())
+	tdrs, err := clientList.GetTrafficDirectorRecordSet(client, tdID, d.Id())
 	if err != nil {
-		return fmt.Errorf("Couldn't find Dyn Traffic Director Record Set: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Couldn't find Dyn Traffic Director Record Set: %w", err))
 	}
 
 	err = resourceDynTrafficDirectorRecordSetToResourceData(tdrs, d)
 	if err != nil {
-		return fmt.Errorf("Couldn't convert Dyn Traffic Director Record Set: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Couldn't convert Dyn Traffic Director Record Set: %w", err))
 	}
 
 	return nil
 }
 
-func resourceDynTrafficDirectorRecordSetUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceDynTrafficDirectorRecordSetUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	clientList := meta.(accessControlledClientList)
-	client, err := clientList.Acquire()
+	client, err := clientList.AcquireContext(ctx)
 	if err != nil {
-		return err
+		return resourceDynError(ctx, err)
 	}
 
 	tdID := d.Get("traffic_director_id").(string)
 	rdataClass := d.Get("rdata_class").(string)
 	optionsSetter := resourceDynTrafficDirectorRecordSetOptions(d)
 
+	if d.HasChange("response_pool_id") {
+		oldResponsePoolID, newResponsePoolID := d.GetChange("response_pool_id")
+		log.Printf("[DEBUG] Moving Dyn Traffic Director (%s) Record Set (%s) from Response Pool (%s) to (%s)", tdID, d.Id(), oldResponsePoolID, newResponsePoolID)
+	}
+
 	log.Printf("[DEBUG] Dyn Traffic Director (%s) Record Set (%s) update configuration: rdata_class: %s", tdID, d.Id(), rdataClass)
 
 	td, err := client.UpdateTrafficDirectorRecordSet(tdID, d.Id(), rdataClass, optionsSetter)
+	clientList.InvalidateTrafficDirector(tdID)
 	if err != nil {
 		clientList.Release(client)
-		return fmt.Errorf("Failed to update Dyn Traffic Director Record Set: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Failed to update Dyn Traffic Director Record Set: %w", err))
 	}
 
 	d.SetId(td.RecordSetID)
 	clientList.Release(client)
-	return resourceDynTrafficDirectorRecordSetRead(d, meta)
+	return resourceDynTrafficDirectorRecordSetRead(ctx, d, meta)
 }
 
-func resourceDynTrafficDirectorRecordSetDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceDynTrafficDirectorRecordSetDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	clientList := meta.(accessControlledClientList)
-	client, err := clientList.Acquire()
+	client, err := clientList.AcquireContext(ctx)
 	if err != nil {
-		return err
+		return resourceDynError(ctx, err)
 	}
 	defer clientList.Release(client)
 
@@ -154,19 +219,22 @@ func resourceDynTrafficDirectorRecordSetDelete(d *schema.ResourceData, meta inte
 
 	log.Printf("[DEBUG] Deleting Traffic Director (%s) Record Set (%s)", tdID, d.Id())
 	err = client.DeleteTrafficDirectorRecordSet(tdID, d.Id())
+	clientList.InvalidateTrafficDirector(tdID)
 	if err != nil {
-		return fmt.Errorf("Couldn't delete Dyn Traffic Director Record Set: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Couldn't delete Dyn Traffic Director Record Set: %w", err))
 	}
 
 	d.SetId("")
 	return nil
 }
 
-func resourceDynTrafficDirectorRecordSetImportState(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceDynTrafficDirectorRecordSetImportState(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	results := make([]*schema.ResourceData, 1, 1)
 
 	clientList := meta.(accessControlledClientList)
-	client, err := clientList.Acquire()
+	ctx, cancel := context.WithTimeout(ctx, d.Timeout(schema.TimeoutRead))
+	defer cancel()
+	client, err := clientList.AcquireContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -177,41 +245,23 @@ func resourceDynTrafficDirectorRecordSetImportState(d *schema.ResourceData, meta
 		return nil, fmt.Errorf("invalid id provided, expected format: {traffic_director}/{response_pool}[/{record_set}]")
 	}
 
-	log.Printf("[DEBUG] Trying to get Traffic Director using id: %s", values[0])
-	td, err := client.GetTrafficDirector(values[0])
+	td, err := getTrafficDirector(client, values[0])
 	if err != nil {
-		log.Printf("[DEBUG] Error: %s / Trying to get Traffic Director using label: %s", err, values[0])
-		td, err = client.FindTrafficDirector(values[0])
-		if err != nil {
-			return nil, fmt.Errorf("Couldn't find Dyn Traffic Director: %s", err)
-		}
+		return nil, err
 	}
 
-	var tdrp *dyn.TrafficDirectorResponsePool
-	log.Printf("[DEBUG] Trying to get Dyn Traffic Director Response Pool using id/label: %s", values[1])
-	for _, responsePool := range td.ResponsePools {
-		if responsePool.ResponsePoolID == values[1] || responsePool.Label == values[1] {
-			tdrp = responsePool
-			break
-		}
-	}
-	if tdrp == nil {
-		return nil, fmt.Errorf("Couldn't find Dyn Traffic Director (%s) Response Pool: %s", values[0], values[1])
+	tdrp, err := findTrafficDirectorResponsePool(td, values[1])
+	if err != nil {
+		return nil, err
 	}
 
-	var tdrs *dyn.TrafficDirectorRecordSet
+	recordSet := ""
 	if len(values) == 3 {
-		for _, recordSet := range tdrp.RecordSets {
-			if recordSet.RecordSetID == values[2] || recordSet.RDataClass == values[2] {
-				tdrs = recordSet
-				break
-			}
-		}
-	} else if len(tdrp.RecordSets) == 1 {
-		tdrs = tdrp.RecordSets[0]
+		recordSet = values[2]
 	}
-	if tdrs == nil {
-		return nil, fmt.Errorf("Couldn't find Dyn Traffic Director Record Set with ID %s", values)
+	tdrs, err := findTrafficDirectorRecordSet(tdrp, recordSet)
+	if err != nil {
+		return nil, err
 	}
 
 	d.SetId(tdrs.RecordSetID)
@@ -219,7 +269,7 @@ func resourceDynTrafficDirectorRecordSetImportState(d *schema.ResourceData, meta
 	d.Set("response_pool_id", tdrp.ResponsePoolID)
 	err = resourceDynTrafficDirectorRecordSetToResourceData(tdrs, d)
 	if err != nil {
-		return nil, fmt.Errorf("Couldn't convert Dyn Traffic Director Record Set: %s", err)
+		return nil, fmt.Errorf("Couldn't convert Dyn Traffic Director Record Set: %w", err)
 	}
 	results[0] = d
 
@@ -233,6 +283,9 @@ func resourceDynTrafficDirectorRecordSetToResourceData(tdrs *dyn.TrafficDirector
 	d.Set("eligible", tdrs.Eligible)
 	d.Set("automation", tdrs.Automation)
 	d.Set("monitor_id", tdrs.MonitorID)
+	if tdrs.ResponsePoolID != "" {
+		d.Set("response_pool_id", tdrs.ResponsePoolID)
+	}
 
 	return nil
 }
@@ -1,28 +1,37 @@
 package dyn
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
 
 	"github.com/Shopify/go-dyn/pkg/dyn"
-	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func resourceDynTrafficDirectorResponsePool() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceDynTrafficDirectorResponsePoolCreate,
-		Read:   resourceDynTrafficDirectorResponsePoolRead,
-		Update: resourceDynTrafficDirectorResponsePoolUpdate,
-		Delete: resourceDynTrafficDirectorResponsePoolDelete,
+	r := &schema.Resource{
+		CreateContext: resourceDynTrafficDirectorResponsePoolCreate,
+		ReadContext:   resourceDynTrafficDirectorResponsePoolRead,
+		UpdateContext: resourceDynTrafficDirectorResponsePoolUpdate,
+		DeleteContext: resourceDynTrafficDirectorResponsePoolDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceDynTrafficDirectorResponsePoolImportState,
+			StateContext: resourceDynTrafficDirectorResponsePoolImportState,
 		},
 
+		CustomizeDiff: resourceDynTrafficDirectorResponsePoolCustomizeDiff,
+
+		SchemaVersion: 1,
+
 		Schema: map[string]*schema.Schema{
+			"on_existing": onExistingSchema(),
+
 			"traffic_director_id": {
 				Type:     schema.TypeString,
 				Required: true,
+				ForceNew: true,
 			},
 
 			"label": {
@@ -31,60 +40,99 @@ func resourceDynTrafficDirectorResponsePool() *schema.Resource {
 			},
 		},
 	}
+	r.StateUpgraders = trafficDirectorStateUpgraders(r)
+
+	return r
 }
 
-func resourceDynTrafficDirectorResponsePoolCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceDynTrafficDirectorResponsePoolCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	tree, err := getTrafficDirectorDiffTree(ctx, d, meta, "label")
+	if err != nil || tree == nil {
+		return err
+	}
+	tree.Adopting = trafficDirectorDiffAdopting(d)
+
+	return tree.checkResponsePool(d.Id(), trafficDirectorDiffString(d, "label"))
+}
+
+func resourceDynTrafficDirectorResponsePoolCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	clientList := meta.(accessControlledClientList)
-	client, err := clientList.Acquire()
+	client, err := clientList.AcquireContext(ctx)
 	if err != nil {
-		return err
+		return resourceDynError(ctx, err)
 	}
 
 	td_id := d.Get("traffic_director_id").(string)
 	label := d.Get("label").(string)
 
+	// The Traffic Director itself only lists the response pools its rulesets
+	// use, so look through all of them.
+	responsePools, err := clientList.GetTrafficDirectorResponsePools(client, td_id)
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, fmt.Errorf("Couldn't get Dyn Traffic Director Response Pools: %w", err))
+	}
+	ids := make([]string, 0)
+	for _, responsePool := range responsePools {
+		if responsePool.Label == label {
+			ids = append(ids, responsePool.ResponsePoolID)
+		}
+	}
+	id, err := existingTrafficDirectorObject(d.Get("on_existing").(string), "Traffic Director Response Pool", label, ids)
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, err)
+	}
+	if id != "" {
+		log.Printf("[DEBUG] Adopting existing Dyn Traffic Director (%s) Response Pool (%s) labeled: %s", td_id, id, label)
+		d.SetId(id)
+		clientList.Release(client)
+		return resourceDynTrafficDirectorResponsePoolUpdate(ctx, d, meta)
+	}
+
 	log.Printf("[DEBUG] Dyn Traffic Director (%s) Response Pool create configuration: label: %s", td_id, label)
 
 	tdrp, err := client.CreateTrafficDirectorResponsePool(td_id, label)
+	clientList.InvalidateTrafficDirector(td_id)
 	if err != nil {
 		clientList.Release(client)
-		return fmt.Errorf("Failed to create Dyn Traffic Director Response Pool: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Failed to create Dyn Traffic Director Response Pool: %w", err))
 	}
 
 	d.SetId(tdrp.ResponsePoolID)
 	clientList.Release(client)
-	return resourceDynTrafficDirectorResponsePoolRead(d, meta)
+	return resourceDynTrafficDirectorResponsePoolRead(ctx, d, meta)
 }
 
-func resourceDynTrafficDirectorResponsePoolRead(d *schema.ResourceData, meta interface{}) error {
+func resourceDynTrafficDirectorResponsePoolRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	clientList := meta.(accessControlledClientList)
-	client, err := clientList.Acquire()
+	client, err := clientList.AcquireContext(ctx)
 	if err != nil {
-		return err
+		return resourceDynError(ctx, err)
 	}
 	defer clientList.Release(client)
 
 	td_id := d.Get("traffic_director_id").(string)
 
 	log.Printf("[DEBUG] Getting Traffic Director (%s) Response Pool (%s)", td_id, d.Id())
-	tdrp, err := client.GetTrafficDirectorResponsePool(td_id, d.Id())
+	tdrp, err := clientList.GetTrafficDirectorResponsePool(client, td_id, d.Id())
 	if err != nil {
-		return fmt.Errorf("Couldn't find Dyn Traffic Director Response Pool: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Couldn't find Dyn Traffic Director Response Pool: %w", err))
 	}
 
 	err = resourceDynTrafficDirectorResponsePoolToResourceData(tdrp, d)
 	if err != nil {
-		return fmt.Errorf("Couldn't convert Dyn Traffic Director Response Pool: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Couldn't convert Dyn Traffic Director Response Pool: %w", err))
 	}
 
 	return nil
 }
 
-func resourceDynTrafficDirectorResponsePoolUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceDynTrafficDirectorResponsePoolUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	clientList := meta.(accessControlledClientList)
-	client, err := clientList.Acquire()
+	client, err := clientList.AcquireContext(ctx)
 	if err != nil {
-		return err
+		return resourceDynError(ctx, err)
 	}
 
 	td_id := d.Get("traffic_director_id").(string)
@@ -93,21 +141,22 @@ func resourceDynTrafficDirectorResponsePoolUpdate(d *schema.ResourceData, meta i
 	log.Printf("[DEBUG] Dyn Traffic Director (%s) Response Pool (%s) update configuration: label: %s", td_id, d.Id(), label)
 
 	td, err := client.UpdateTrafficDirectorResponsePool(td_id, d.Id(), label)
+	clientList.InvalidateTrafficDirector(td_id)
 	if err != nil {
 		clientList.Release(client)
-		return fmt.Errorf("Failed to update Dyn Traffic Director Response Pool: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Failed to update Dyn Traffic Director Response Pool: %w", err))
 	}
 
 	d.SetId(td.ResponsePoolID)
 	clientList.Release(client)
-	return resourceDynTrafficDirectorResponsePoolRead(d, meta)
+	return resourceDynTrafficDirectorResponsePoolRead(ctx, d, meta)
 }
 
-func resourceDynTrafficDirectorResponsePoolDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceDynTrafficDirectorResponsePoolDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	clientList := meta.(accessControlledClientList)
-	client, err := clientList.Acquire()
+	client, err := clientList.AcquireContext(ctx)
 	if err != nil {
-		return err
+		return resourceDynError(ctx, err)
 	}
 	defer clientList.Release(client)
 
@@ -115,19 +164,22 @@ func resourceDynTrafficDirectorResponsePoolDelete(d *schema.ResourceData, meta i
 
 	log.Printf("[DEBUG] Deleting Traffic Director (%s) Response Pool (%s)", td_id, d.Id())
 	err = client.DeleteTrafficDirectorResponsePool(td_id, d.Id())
+	clientList.InvalidateTrafficDirector(td_id)
 	if err != nil {
-		return fmt.Errorf("Couldn't delete Dyn Traffic Director Response Pool: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Couldn't delete Dyn Traffic Director Response Pool: %w", err))
 	}
 
 	d.SetId("")
 	return nil
 }
 
-func resourceDynTrafficDirectorResponsePoolImportState(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceDynTrafficDirectorResponsePoolImportState(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	results := make([]*schema.ResourceData, 1, 1)
 
 	clientList := meta.(accessControlledClientList)
-	client, err := clientList.Acquire()
+	ctx, cancel := context.WithTimeout(ctx, d.Timeout(schema.TimeoutRead))
+	defer cancel()
+	client, err := clientList.AcquireContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -138,33 +190,21 @@ func resourceDynTrafficDirectorResponsePoolImportState(d *schema.ResourceData, m
 		return nil, fmt.Errorf("invalid id provided, expected format: {traffic_director}/{response_pool}")
 	}
 
-	log.Printf("[DEBUG] Trying to get Traffic Director using id: %s", values[0])
-	td, err := client.GetTrafficDirector(values[0])
+	td, err := getTrafficDirector(client, values[0])
 	if err != nil {
-		log.Printf("[DEBUG] Error: %s / Trying to get Traffic Director using label: %s", err, values[0])
-		td, err = client.FindTrafficDirector(values[0])
-		if err != nil {
-			return nil, fmt.Errorf("Couldn't find Dyn Traffic Director: %s", err)
-		}
+		return nil, err
 	}
 
-	var tdrp *dyn.TrafficDirectorResponsePool
-	log.Printf("[DEBUG] Trying to get Dyn Traffic Director Response Pool using id/label: %s", values[1])
-	for _, responsePool := range td.ResponsePools {
-		if responsePool.ResponsePoolID == values[1] || responsePool.Label == values[1] {
-			tdrp = responsePool
-			break
-		}
-	}
-	if tdrp == nil {
-		return nil, fmt.Errorf("Couldn't find Dyn Traffic Director (%s) Response Pool: %s", values[0], values[1])
+	tdrp, err := findTrafficDirectorResponsePool(td, values[1])
+	if err != nil {
+		return nil, err
 	}
 
 	d.SetId(tdrp.ResponsePoolID)
 	d.Set("traffic_director_id", td.ServiceID)
 	err = resourceDynTrafficDirectorResponsePoolToResourceData(tdrp, d)
 	if err != nil {
-		return nil, fmt.Errorf("Couldn't convert Dyn Traffic Director Response Pool: %s", err)
+		return nil, fmt.Errorf("Couldn't convert Dyn Traffic Director Response Pool: %w", err)
 	}
 	results[0] = d
 
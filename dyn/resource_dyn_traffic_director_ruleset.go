@@ -1,28 +1,38 @@
 package dyn
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
 
 	"github.com/Shopify/go-dyn/pkg/dyn"
-	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func resourceDynTrafficDirectorRuleset() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceDynTrafficDirectorRulesetCreate,
-		Read:   resourceDynTrafficDirectorRulesetRead,
-		Update: resourceDynTrafficDirectorRulesetUpdate,
-		Delete: resourceDynTrafficDirectorRulesetDelete,
+	r := &schema.Resource{
+		CreateContext: resourceDynTrafficDirectorRulesetCreate,
+		ReadContext:   resourceDynTrafficDirectorRulesetRead,
+		UpdateContext: resourceDynTrafficDirectorRulesetUpdate,
+		DeleteContext: resourceDynTrafficDirectorRulesetDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceDynTrafficDirectorRulesetImportState,
+			StateContext: resourceDynTrafficDirectorRulesetImportState,
 		},
 
+		CustomizeDiff: resourceDynTrafficDirectorRulesetCustomizeDiff,
+
+		SchemaVersion: 1,
+
 		Schema: map[string]*schema.Schema{
+			"on_existing": onExistingSchema(),
+
 			"traffic_director_id": {
 				Type:     schema.TypeString,
 				Required: true,
+				ForceNew: true,
 			},
 
 			"label": {
@@ -30,9 +40,13 @@ func resourceDynTrafficDirectorRuleset() *schema.Resource {
 				Required: true,
 			},
 
+			// The ordering is left alone when it isn't configured, which
+			// is how it has to be left when a
+			// dyn_traffic_director_ruleset_order owns the order.
 			"ordering": {
 				Type:     schema.TypeInt,
 				Optional: true,
+				Computed: true,
 			},
 
 			"response_pool_ids": {
@@ -43,38 +57,53 @@ func resourceDynTrafficDirectorRuleset() *schema.Resource {
 				Optional: true,
 			},
 
-			"geolocation": {
-				Type: schema.TypeSet,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"region": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"country": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"province": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-					},
-				},
-				Optional: true,
-			},
+			"geolocation": trafficDirectorRulesetGeolocationSchema(),
 		},
 	}
+	r.StateUpgraders = trafficDirectorStateUpgraders(r)
+
+	return r
 }
 
-func resourceDynTrafficDirectorRulesetOptions(d *schema.ResourceData) (dyn.TrafficDirectorRulesetOptionSetter, error) {
-	responsePoolIDsInterface := d.Get("response_pool_ids").([]interface{})
-	responsePoolIDs := make([]string, len(responsePoolIDsInterface))
-	for i, v := range responsePoolIDsInterface {
-		responsePoolIDs[i] = v.(string)
+func resourceDynTrafficDirectorRulesetCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	tree, err := getTrafficDirectorDiffTree(ctx, d, meta, "label", "response_pool_ids")
+	if err != nil || tree == nil {
+		return err
 	}
+	tree.Adopting = trafficDirectorDiffAdopting(d)
 
-	geolocationInterface := d.Get("geolocation").(*schema.Set)
+	return tree.checkRuleset(d.Id(), trafficDirectorDiffString(d, "label"), trafficDirectorDiffIDs(d, "response_pool_ids"))
+}
+
+func trafficDirectorRulesetGeolocationSchema() *schema.Schema {
+	return &schema.Schema{
+		Type: schema.TypeSet,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"region": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validateGeolocationCode("region", geolocationRegions, nil),
+				},
+				"country": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validateGeolocationCode("country", geolocationCountries, geolocationCountryAliases),
+				},
+				"province": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validateGeolocationCode("province", geolocationProvinces, nil),
+				},
+			},
+		},
+		Optional: true,
+	}
+}
+
+// expandTrafficDirectorRulesetGeolocation converts a geolocation set into the
+// criteria expected by TrafficDirectorRulesetCURequest.SetGeolocation.
+func expandTrafficDirectorRulesetGeolocation(geolocationInterface *schema.Set) (map[string][]string, error) {
 	geolocation := make(map[string][]string)
 	for _, mInterface := range geolocationInterface.List() {
 		m := mInterface.(map[string]interface{})
@@ -94,6 +123,50 @@ func resourceDynTrafficDirectorRulesetOptions(d *schema.ResourceData) (dyn.Traff
 		}
 	}
 
+	return geolocation, nil
+}
+
+// flattenTrafficDirectorRulesetGeolocation converts ruleset criteria into a
+// geolocation set.
+func flattenTrafficDirectorRulesetGeolocation(tdrs *dyn.TrafficDirectorRuleset) []map[string]string {
+	geolocation := make([]map[string]string, 0)
+	for _, region := range tdrs.Criteria.Geolocation.Regions {
+		geolocation = append(geolocation, map[string]string{
+			"region":   region,
+			"country":  "",
+			"province": "",
+		})
+	}
+	for _, country := range tdrs.Criteria.Geolocation.Countries {
+		geolocation = append(geolocation, map[string]string{
+			"region":   "",
+			"country":  country,
+			"province": "",
+		})
+	}
+	for _, province := range tdrs.Criteria.Geolocation.Provinces {
+		geolocation = append(geolocation, map[string]string{
+			"region":   "",
+			"country":  "",
+			"province": province,
+		})
+	}
+
+	return geolocation
+}
+
+func resourceDynTrafficDirectorRulesetOptions(d *schema.ResourceData) (dyn.TrafficDirectorRulesetOptionSetter, error) {
+	responsePoolIDsInterface := d.Get("response_pool_ids").([]interface{})
+	responsePoolIDs := make([]string, len(responsePoolIDsInterface))
+	for i, v := range responsePoolIDsInterface {
+		responsePoolIDs[i] = v.(string)
+	}
+
+	geolocation, err := expandTrafficDirectorRulesetGeolocation(d.Get("geolocation").(*schema.Set))
+	if err != nil {
+		return nil, err
+	}
+
 	return func(req *dyn.TrafficDirectorRulesetCURequest) {
 		if len(responsePoolIDs) > 0 {
 			req.SetResponsePools(responsePoolIDs)
@@ -101,17 +174,37 @@ func resourceDynTrafficDirectorRulesetOptions(d *schema.ResourceData) (dyn.Traff
 		if len(geolocation) > 0 {
 			req.SetGeolocation(geolocation)
 		}
-		if d.Get("ordering") != nil {
-			req.Ordering = d.Get("ordering").(int)
-		}
 	}, nil
 }
 
-func resourceDynTrafficDirectorRulesetCreate(d *schema.ResourceData, meta interface{}) error {
+// resourceDynTrafficDirectorRulesetOrdering moves the ruleset to its ordering
+// when one is configured. This isn't part of the options, since an ordering
+// of 0 would be left out of their request.
+func resourceDynTrafficDirectorRulesetOrdering(clientList accessControlledClientList, client *dyn.Client, d *schema.ResourceData, tdID string, tdrs *dyn.TrafficDirectorRuleset) error {
+	if !resourceDynConfigured(d.GetRawConfig(), cty.GetAttrPath("ordering")) {
+		return nil
+	}
+
+	ordering := d.Get("ordering").(int)
+	if tdrs.Ordering == ordering {
+		return nil
+	}
+
+	log.Printf("[DEBUG] Moving Traffic Director (%s) Ruleset (%s) to ordering %d", tdID, tdrs.RulesetID, ordering)
+	_, err := client.UpdateTrafficDirectorRulesetOrdering(tdID, tdrs.RulesetID, ordering, true)
+	clientList.InvalidateTrafficDirector(tdID)
+	if err != nil {
+		return fmt.Errorf("Failed to reorder Dyn Traffic Director Ruleset (%s): %w", tdrs.RulesetID, err)
+	}
+
+	return nil
+}
+
+func resourceDynTrafficDirectorRulesetCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	clientList := meta.(accessControlledClientList)
-	client, err := clientList.Acquire()
+	client, err := clientList.AcquireContext(ctx)
 	if err != nil {
-		return err
+		return resourceDynError(ctx, err)
 	}
 
 	td_id := d.Get("traffic_director_id").(string)
@@ -119,51 +212,83 @@ func resourceDynTrafficDirectorRulesetCreate(d *schema.ResourceData, meta interf
 
 	optionsSetter, err := resourceDynTrafficDirectorRulesetOptions(d)
 	if err != nil {
-		return err
+		clientList.Release(client)
+		return resourceDynError(ctx, err)
+	}
+
+	td, err := client.GetTrafficDirector(td_id)
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, fmt.Errorf("Couldn't find Dyn Traffic Director: %w", err))
+	}
+	ids := make([]string, 0)
+	for _, ruleset := range td.Rulesets {
+		if ruleset.Label == label {
+			ids = append(ids, ruleset.RulesetID)
+		}
+	}
+	id, err := existingTrafficDirectorObject(d.Get("on_existing").(string), "Traffic Director Ruleset", label, ids)
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, err)
+	}
+	if id != "" {
+		log.Printf("[DEBUG] Adopting existing Dyn Traffic Director (%s) Ruleset (%s) labeled: %s", td_id, id, label)
+		d.SetId(id)
+		clientList.Release(client)
+		return resourceDynTrafficDirectorRulesetUpdate(ctx, d, meta)
 	}
 
 	log.Printf("[DEBUG] Dyn Traffic Director (%s) Ruleset create configuration: label: %s", td_id, label)
 
 	tdrs, err := client.CreateTrafficDirectorRuleset(td_id, label, optionsSetter)
+	clientList.InvalidateTrafficDirector(td_id)
 	if err != nil {
 		clientList.Release(client)
-		return fmt.Errorf("Failed to create Dyn Traffic Director Ruleset: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Failed to create Dyn Traffic Director Ruleset: %w", err))
 	}
 
 	d.SetId(tdrs.RulesetID)
+
+	err = resourceDynTrafficDirectorRulesetOrdering(clientList, client, d, td_id, tdrs)
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, err)
+	}
+
 	clientList.Release(client)
-	return resourceDynTrafficDirectorRulesetRead(d, meta)
+	return resourceDynTrafficDirectorRulesetRead(ctx, d, meta)
 }
 
-func resourceDynTrafficDirectorRulesetRead(d *schema.ResourceData, meta interface{}) error {
+func resourceDynTrafficDirectorRulesetRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	clientList := meta.(accessControlledClientList)
-	client, err := clientList.Acquire()
+	client, err := clientList.AcquireContext(ctx)
 	if err != nil {
-		return err
+		return resourceDynError(ctx, err)
 	}
 	defer clientList.Release(client)
 
 	td_id := d.Get("traffic_director_id").(string)
 
 	log.Printf("[DEBUG] Getting Traffic Director (%s) Ruleset (%s)", td_id, d.Id())
-	tdrs, err := client.GetTrafficDirectorRuleset(td_id, d.Id())
+	tdrs, err := clientList.GetTrafficDirectorRuleset(client, td_id, d.Id())
 	if err != nil {
-		return fmt.Errorf("Couldn't find Dyn Traffic Director Ruleset: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Couldn't find Dyn Traffic Director Ruleset: %w", err))
 	}
 
 	err = resourceDynTrafficDirectorRulesetToResourceData(tdrs, d)
 	if err != nil {
-		return fmt.Errorf("Couldn't convert Dyn Traffic Director Ruleset: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Couldn't convert Dyn Traffic Director Ruleset: %w", err))
 	}
 
 	return nil
 }
 
-func resourceDynTrafficDirectorRulesetUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceDynTrafficDirectorRulesetUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	clientList := meta.(accessControlledClientList)
-	client, err := clientList.Acquire()
+	client, err := clientList.AcquireContext(ctx)
 	if err != nil {
-		return err
+		return resourceDynError(ctx, err)
 	}
 
 	td_id := d.Get("traffic_director_id").(string)
@@ -171,27 +296,36 @@ func resourceDynTrafficDirectorRulesetUpdate(d *schema.ResourceData, meta interf
 
 	optionsSetter, err := resourceDynTrafficDirectorRulesetOptions(d)
 	if err != nil {
-		return err
+		clientList.Release(client)
+		return resourceDynError(ctx, err)
 	}
 
 	log.Printf("[DEBUG] Dyn Traffic Director (%s) Ruleset (%s) update configuration: label: %s", td_id, d.Id(), label)
 
 	tdrs, err := client.UpdateTrafficDirectorRuleset(td_id, d.Id(), label, optionsSetter)
+	clientList.InvalidateTrafficDirector(td_id)
 	if err != nil {
 		clientList.Release(client)
-		return fmt.Errorf("Failed to update Dyn Traffic Director Ruleset: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Failed to update Dyn Traffic Director Ruleset: %w", err))
 	}
 
 	d.SetId(tdrs.RulesetID)
+
+	err = resourceDynTrafficDirectorRulesetOrdering(clientList, client, d, td_id, tdrs)
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, err)
+	}
+
 	clientList.Release(client)
-	return resourceDynTrafficDirectorRulesetRead(d, meta)
+	return resourceDynTrafficDirectorRulesetRead(ctx, d, meta)
 }
 
-func resourceDynTrafficDirectorRulesetDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceDynTrafficDirectorRulesetDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	clientList := meta.(accessControlledClientList)
-	client, err := clientList.Acquire()
+	client, err := clientList.AcquireContext(ctx)
 	if err != nil {
-		return err
+		return resourceDynError(ctx, err)
 	}
 	defer clientList.Release(client)
 
@@ -199,19 +333,22 @@ func resourceDynTrafficDirectorRulesetDelete(d *schema.ResourceData, meta interf
 
 	log.Printf("[DEBUG] Deleting Traffic Director (%s) Ruleset (%s)", td_id, d.Id())
 	err = client.DeleteTrafficDirectorRuleset(td_id, d.Id())
+	clientList.InvalidateTrafficDirector(td_id)
 	if err != nil {
-		return fmt.Errorf("Couldn't delete Dyn Traffic Director Ruleset: %s", err)
+		return resourceDynError(ctx, fmt.Errorf("Couldn't delete Dyn Traffic Director Ruleset: %w", err))
 	}
 
 	d.SetId("")
 	return nil
 }
 
-func resourceDynTrafficDirectorRulesetImportState(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceDynTrafficDirectorRulesetImportState(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	results := make([]*schema.ResourceData, 1, 1)
 
 	clientList := meta.(accessControlledClientList)
-	client, err := clientList.Acquire()
+	ctx, cancel := context.WithTimeout(ctx, d.Timeout(schema.TimeoutRead))
+	defer cancel()
+	client, err := clientList.AcquireContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -222,33 +359,21 @@ func resourceDynTrafficDirectorRulesetImportState(d *schema.ResourceData, meta i
 		return nil, fmt.Errorf("invalid id provided, expected format: {traffic_director}/{ruleset}")
 	}
 
-	log.Printf("[DEBUG] Trying to get Traffic Director using id: %s", values[0])
-	td, err := client.GetTrafficDirector(values[0])
+	td, err := getTrafficDirector(client, values[0])
 	if err != nil {
-		log.Printf("[DEBUG] Error: %s / Trying to get Traffic Director using label: %s", err, values[0])
-		td, err = client.FindTrafficDirector(values[0])
-		if err != nil {
-			return nil, fmt.Errorf("Couldn't find Dyn Traffic Director: %s", err)
-		}
+		return nil, err
 	}
 
-	var tdrs *dyn.TrafficDirectorRuleset
-	log.Printf("[DEBUG] Trying to get Dyn Traffic Director Ruleset using id/label: %s", values[1])
-	for _, ruleset := range td.Rulesets {
-		if ruleset.RulesetID == values[1] || ruleset.Label == values[1] {
-			tdrs = ruleset
-			break
-		}
-	}
-	if tdrs == nil {
-		return nil, fmt.Errorf("Couldn't find Dyn Traffic Director (%s) Ruleset: %s", values[0], values[1])
+	tdrs, err := findTrafficDirectorRuleset(td, values[1])
+	if err != nil {
+		return nil, err
 	}
 
 	d.SetId(tdrs.RulesetID)
 	d.Set("traffic_director_id", td.ServiceID)
 	err = resourceDynTrafficDirectorRulesetToResourceData(tdrs, d)
 	if err != nil {
-		return nil, fmt.Errorf("Couldn't convert Dyn Traffic Director Ruleset: %s", err)
+		return nil, fmt.Errorf("Couldn't convert Dyn Traffic Director Ruleset: %w", err)
 	}
 	results[0] = d
 
@@ -261,29 +386,7 @@ func resourceDynTrafficDirectorRulesetToResourceData(tdrs *dyn.TrafficDirectorRu
 	d.Set("criteria", tdrs.Criteria)
 	d.Set("ordering", tdrs.Ordering)
 
-	geolocation := make([]map[string]string, 0)
-	for _, region := range tdrs.Criteria.Geolocation.Regions {
-		geolocation = append(geolocation, map[string]string{
-			"region":   region,
-			"country":  "",
-			"province": "",
-		})
-	}
-	for _, country := range tdrs.Criteria.Geolocation.Countries {
-		geolocation = append(geolocation, map[string]string{
-			"region":   "",
-			"country":  country,
-			"province": "",
-		})
-	}
-	for _, province := range tdrs.Criteria.Geolocation.Provinces {
-		geolocation = append(geolocation, map[string]string{
-			"region":   "",
-			"country":  "",
-			"province": province,
-		})
-	}
-	d.Set("geolocation", geolocation)
+	d.Set("geolocation", flattenTrafficDirectorRulesetGeolocation(tdrs))
 
 	responsePoolIDs := make([]string, len(tdrs.ResponsePools))
 	for idx, responsePool := range tdrs.ResponsePools {
@@ -0,0 +1,243 @@
+package dyn
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/Shopify/go-dyn/pkg/dyn"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceDynTrafficDirectorRulesetOrder() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDynTrafficDirectorRulesetOrderCreate,
+		ReadContext:   resourceDynTrafficDirectorRulesetOrderRead,
+		UpdateContext: resourceDynTrafficDirectorRulesetOrderUpdate,
+		DeleteContext: resourceDynTrafficDirectorRulesetOrderDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceDynTrafficDirectorRulesetOrderImportState,
+		},
+
+		CustomizeDiff: resourceDynTrafficDirectorRulesetOrderCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"traffic_director_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"ruleset_ids": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Required: true,
+				MinItems: 1,
+			},
+		},
+	}
+}
+
+func resourceDynTrafficDirectorRulesetOrderCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	tree, err := getTrafficDirectorDiffTree(ctx, d, meta, "ruleset_ids")
+	if err != nil || tree == nil {
+		return err
+	}
+
+	rulesetIDs := trafficDirectorDiffIDs(d, "ruleset_ids")
+	err = tree.checkIDs("ruleset_ids", "Ruleset", rulesetIDs, func(id string) bool {
+		return tree.ruleset(id) != nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return tree.checkRulesetOrder(rulesetIDs)
+}
+
+// sortedTrafficDirectorRulesets returns the rulesets of a Traffic Director
+// in the order Dyn evaluates them.
+func sortedTrafficDirectorRulesets(td *dyn.TrafficDirector) []*dyn.TrafficDirectorRuleset {
+	rulesets := make([]*dyn.TrafficDirectorRuleset, len(td.Rulesets))
+	copy(rulesets, td.Rulesets)
+	sort.SliceStable(rulesets, func(i, j int) bool {
+		return rulesets[i].Ordering < rulesets[j].Ordering
+	})
+
+	return rulesets
+}
+
+func resourceDynTrafficDirectorRulesetOrderCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	d.SetId(d.Get("traffic_director_id").(string))
+
+	return resourceDynTrafficDirectorRulesetOrderUpdate(ctx, d, meta)
+}
+
+func resourceDynTrafficDirectorRulesetOrderRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	clientList := meta.(accessControlledClientList)
+	client, err := clientList.AcquireContext(ctx)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+	defer clientList.Release(client)
+
+	log.Printf("[DEBUG] Getting Traffic Director (%s) ruleset order", d.Id())
+	td, err := clientList.GetTrafficDirector(client, d.Id())
+	if err != nil {
+		return resourceDynError(ctx, fmt.Errorf("Couldn't find Dyn Traffic Director: %w", err))
+	}
+
+	resourceDynTrafficDirectorRulesetOrderToResourceData(td, d)
+
+	return nil
+}
+
+func resourceDynTrafficDirectorRulesetOrderUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	clientList := meta.(accessControlledClientList)
+	client, err := clientList.AcquireContext(ctx)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+
+	tdID := d.Id()
+	rulesetIDsInterface := d.Get("ruleset_ids").([]interface{})
+	rulesetIDs := make([]string, len(rulesetIDsInterface))
+	for i, v := range rulesetIDsInterface {
+		rulesetIDs[i] = v.(string)
+	}
+
+	log.Printf("[DEBUG] Getting Traffic Director (%s) to reorder rulesets: %v", tdID, rulesetIDs)
+	td, err := client.GetTrafficDirector(tdID)
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, fmt.Errorf("Couldn't find Dyn Traffic Director: %w", err))
+	}
+
+	rulesets := sortedTrafficDirectorRulesets(td)
+	current := make([]string, len(rulesets))
+	orderings := make([]int, len(rulesets))
+	for idx, ruleset := range rulesets {
+		current[idx] = ruleset.RulesetID
+		orderings[idx] = ruleset.Ordering
+	}
+
+	seen := make(map[string]bool)
+	for _, rulesetID := range rulesetIDs {
+		if seen[rulesetID] {
+			clientList.Release(client)
+			return resourceDynError(ctx, fmt.Errorf("Ruleset %s is listed more than once", rulesetID))
+		}
+		seen[rulesetID] = true
+
+		if indexOfString(current, rulesetID) < 0 {
+			clientList.Release(client)
+			return resourceDynError(ctx, fmt.Errorf("Couldn't find Dyn Traffic Director (%s) Ruleset: %s", tdID, rulesetID))
+		}
+	}
+
+	// Dyn renumbers the other rulesets every time one of them is moved, so
+	// place them front to back: each move then leaves the already placed
+	// prefix alone. The positions reuse the orderings Dyn already assigned.
+	// The moves are published together once they're all made, so that the
+	// service never answers with a half reordered set of rulesets.
+	moved := false
+	for position, rulesetID := range rulesetIDs {
+		if current[position] == rulesetID {
+			continue
+		}
+
+		log.Printf("[DEBUG] Moving Traffic Director (%s) Ruleset (%s) to ordering %d", tdID, rulesetID, orderings[position])
+		_, err = client.UpdateTrafficDirectorRulesetOrdering(tdID, rulesetID, orderings[position], false)
+		clientList.InvalidateTrafficDirector(tdID)
+		if err != nil {
+			clientList.Release(client)
+			return resourceDynError(ctx, fmt.Errorf("Failed to reorder Dyn Traffic Director Ruleset (%s): %w", rulesetID, err))
+		}
+
+		current = moveString(current, rulesetID, position)
+		moved = true
+	}
+
+	if moved {
+		log.Printf("[DEBUG] Publishing Traffic Director (%s) ruleset order", tdID)
+		_, err = client.PublishTrafficDirector(tdID)
+		clientList.InvalidateTrafficDirector(tdID)
+		if err != nil {
+			clientList.Release(client)
+			return resourceDynError(ctx, fmt.Errorf("Failed to publish Dyn Traffic Director (%s) ruleset order: %w", tdID, err))
+		}
+	}
+
+	clientList.Release(client)
+	return resourceDynTrafficDirectorRulesetOrderRead(ctx, d, meta)
+}
+
+func resourceDynTrafficDirectorRulesetOrderDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// The rulesets keep their current order; there is nothing to undo.
+	d.SetId("")
+	return nil
+}
+
+func resourceDynTrafficDirectorRulesetOrderImportState(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	results := make([]*schema.ResourceData, 1, 1)
+
+	clientList := meta.(accessControlledClientList)
+	ctx, cancel := context.WithTimeout(ctx, d.Timeout(schema.TimeoutRead))
+	defer cancel()
+	client, err := clientList.AcquireContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer clientList.Release(client)
+
+	td, err := getTrafficDirector(client, d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(td.ServiceID)
+	d.Set("traffic_director_id", td.ServiceID)
+	resourceDynTrafficDirectorRulesetOrderToResourceData(td, d)
+	results[0] = d
+
+	return results, nil
+}
+
+func resourceDynTrafficDirectorRulesetOrderToResourceData(td *dyn.TrafficDirector, d *schema.ResourceData) {
+	rulesets := sortedTrafficDirectorRulesets(td)
+	rulesetIDs := make([]string, len(rulesets))
+	for idx, ruleset := range rulesets {
+		rulesetIDs[idx] = ruleset.RulesetID
+	}
+	d.Set("ruleset_ids", rulesetIDs)
+}
+
+func indexOfString(values []string, value string) int {
+	for idx, v := range values {
+		if v == value {
+			return idx
+		}
+	}
+
+	return -1
+}
+
+// moveString returns values with value moved to the given position.
+func moveString(values []string, value string, position int) []string {
+	moved := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != value {
+			moved = append(moved, v)
+		}
+	}
+
+	moved = append(moved, "")
+	copy(moved[position+1:], moved[position:])
+	moved[position] = value
+
+	return moved
+}
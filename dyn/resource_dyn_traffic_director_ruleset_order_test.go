@@ -0,0 +1,27 @@
+package dyn
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMoveString(t *testing.T) {
+	cases := []struct {
+		values   []string
+		value    string
+		position int
+		expected []string
+	}{
+		{[]string{"a", "b", "c"}, "c", 0, []string{"c", "a", "b"}},
+		{[]string{"a", "b", "c"}, "a", 2, []string{"b", "c", "a"}},
+		{[]string{"a", "b", "c"}, "b", 1, []string{"a", "b", "c"}},
+		{[]string{"a", "b", "c", "d"}, "d", 1, []string{"a", "d", "b", "c"}},
+	}
+
+	for _, tc := range cases {
+		moved := moveString(tc.values, tc.value, tc.position)
+		if !reflect.DeepEqual(moved, tc.expected) {
+			t.Errorf("moving %s to %d in %v: expected %v, got %v", tc.value, tc.position, tc.values, tc.expected, moved)
+		}
+	}
+}
@@ -0,0 +1,589 @@
+package dyn
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Shopify/go-dyn/pkg/dyn"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceDynTrafficDirectorService() *schema.Resource {
+	recordResource := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"label": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"master_line": {
+				Type:     schema.TypeString,
+				Required: true,
+				DiffSuppressFunc: func(k, oldV, newV string, d *schema.ResourceData) bool {
+					return masterLinesEquivalent(oldV, newV)
+				},
+			},
+			// Like on dyn_traffic_director_record, these are kept as
+			// they are in Dyn when they aren't configured.
+			"weight": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateIntBetween(1, 15),
+			},
+			"eligible": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Computed: true,
+			},
+			"automation": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateStringInSlice([]string{"auto", "auto_down", "manual"}),
+			},
+		},
+	}
+
+	recordSetResource := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"label": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"rdata_class": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"ttl": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"monitor_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"record": {
+				Type:     schema.TypeList,
+				Elem:     recordResource,
+				Required: true,
+				MinItems: 1,
+			},
+		},
+	}
+
+	r := &schema.Resource{
+		CreateContext: resourceDynTrafficDirectorServiceCreate,
+		ReadContext:   resourceDynTrafficDirectorServiceRead,
+		UpdateContext: resourceDynTrafficDirectorServiceUpdate,
+		DeleteContext: resourceDynTrafficDirectorServiceDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceDynTrafficDirectorServiceImportState,
+		},
+
+		CustomizeDiff: resourceDynTrafficDirectorServiceCustomizeDiff,
+
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			"ruleset": {
+				Type: schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"label": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"response_pools": {
+							Type: schema.TypeList,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+							Required: true,
+							MinItems: 1,
+						},
+						"geolocation": trafficDirectorRulesetGeolocationSchema(),
+					},
+				},
+				Required: true,
+				MinItems: 1,
+			},
+
+			"response_pool": {
+				Type: schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"label": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"record_set": {
+							Type:     schema.TypeList,
+							Elem:     recordSetResource,
+							Required: true,
+							MinItems: 1,
+						},
+					},
+				},
+				Required: true,
+				MinItems: 1,
+			},
+		},
+	}
+
+	// The service itself is configured exactly like dyn_traffic_director.
+	for k, v := range resourceDynTrafficDirector().Schema {
+		r.Schema[k] = v
+	}
+	r.StateUpgraders = trafficDirectorStateUpgraders(r)
+
+	return r
+}
+
+// expandTrafficDirectorTree builds the tree described by the ruleset and
+// response_pool blocks of a dyn_traffic_director_service.
+func expandTrafficDirectorTree(rulesetsInterface, responsePoolsInterface []interface{}) (*trafficDirectorTree, error) {
+	tree := &trafficDirectorTree{}
+
+	for _, rulesetInterface := range rulesetsInterface {
+		m := rulesetInterface.(map[string]interface{})
+
+		geolocation, err := expandTrafficDirectorRulesetGeolocation(m["geolocation"].(*schema.Set))
+		if err != nil {
+			return nil, fmt.Errorf("ruleset %q: %w", m["label"], err)
+		}
+
+		ruleset := &trafficDirectorTreeRuleset{
+			ID:          m["id"].(string),
+			Label:       m["label"].(string),
+			Geolocation: geolocation,
+		}
+		for _, label := range m["response_pools"].([]interface{}) {
+			ruleset.ResponsePools = append(ruleset.ResponsePools, label.(string))
+		}
+		tree.Rulesets = append(tree.Rulesets, ruleset)
+	}
+
+	for _, responsePoolInterface := range responsePoolsInterface {
+		m := responsePoolInterface.(map[string]interface{})
+		responsePool := &trafficDirectorTreeResponsePool{
+			ID:    m["id"].(string),
+			Label: m["label"].(string),
+		}
+
+		for _, recordSetInterface := range m["record_set"].([]interface{}) {
+			rsm := recordSetInterface.(map[string]interface{})
+			recordSet := &trafficDirectorTreeRecordSet{
+				ID:         rsm["id"].(string),
+				Label:      rsm["label"].(string),
+				RDataClass: rsm["rdata_class"].(string),
+				TTL:        rsm["ttl"].(int),
+				MonitorID:  rsm["monitor_id"].(string),
+			}
+
+			for _, recordInterface := range rsm["record"].([]interface{}) {
+				rm := recordInterface.(map[string]interface{})
+				recordSet.Records = append(recordSet.Records, &trafficDirectorTreeRecord{
+					ID:         rm["id"].(string),
+					Label:      rm["label"].(string),
+					MasterLine: rm["master_line"].(string),
+					Weight:     rm["weight"].(int),
+					Eligible:   rm["eligible"].(bool),
+					Automation: rm["automation"].(string),
+				})
+			}
+			responsePool.RecordSets = append(responsePool.RecordSets, recordSet)
+		}
+		tree.ResponsePools = append(tree.ResponsePools, responsePool)
+	}
+
+	return tree, nil
+}
+
+// alignTo puts the response pools, record sets and records of a live tree in
+// the order they appear in the configuration, so that Dyn's own ordering
+// doesn't show up as a diff. Objects that aren't configured come last.
+func (t *trafficDirectorTree) alignTo(config *trafficDirectorTree) {
+	responsePools := make([]*trafficDirectorTreeResponsePool, 0, len(t.ResponsePools))
+	for _, configResponsePool := range config.ResponsePools {
+		if responsePool := t.responsePool(configResponsePool.Label); responsePool != nil {
+			responsePools = append(responsePools, responsePool)
+		}
+	}
+	for _, responsePool := range t.ResponsePools {
+		if config.responsePool(responsePool.Label) == nil {
+			responsePools = append(responsePools, responsePool)
+		}
+	}
+	t.ResponsePools = responsePools
+
+	for _, responsePool := range t.ResponsePools {
+		configResponsePool := config.responsePool(responsePool.Label)
+		if configResponsePool == nil {
+			continue
+		}
+
+		for _, recordSet := range responsePool.RecordSets {
+			configRecordSet := configResponsePool.recordSet(recordSet.Label)
+			if configRecordSet == nil {
+				continue
+			}
+
+			records := make([]*trafficDirectorTreeRecord, 0, len(recordSet.Records))
+			for _, configRecord := range configRecordSet.Records {
+				if record := recordSet.record(configRecord); record != nil {
+					records = append(records, record)
+				}
+			}
+			for _, record := range recordSet.Records {
+				if configRecordSet.record(record) == nil {
+					records = append(records, record)
+				}
+			}
+			recordSet.Records = records
+		}
+	}
+}
+
+func resourceDynTrafficDirectorServiceCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("ruleset") || !d.NewValueKnown("response_pool") {
+		return nil
+	}
+
+	tree, err := expandTrafficDirectorTree(d.Get("ruleset").([]interface{}), d.Get("response_pool").([]interface{}))
+	if err != nil {
+		return err
+	}
+
+	referenced := make(map[string]bool)
+	rulesetLabels := make(map[string]bool)
+	for idx, ruleset := range tree.Rulesets {
+		if rulesetLabels[ruleset.Label] {
+			return fmt.Errorf("ruleset.%d.label: %q is used by more than one ruleset", idx, ruleset.Label)
+		}
+		rulesetLabels[ruleset.Label] = true
+
+		for pidx, label := range ruleset.ResponsePools {
+			if tree.responsePool(label) == nil {
+				return fmt.Errorf("ruleset.%d.response_pools.%d: no response_pool is labeled %q", idx, pidx, label)
+			}
+			referenced[label] = true
+		}
+	}
+
+	responsePoolLabels := make(map[string]bool)
+	for idx, responsePool := range tree.ResponsePools {
+		if responsePoolLabels[responsePool.Label] {
+			return fmt.Errorf("response_pool.%d.label: %q is used by more than one response pool", idx, responsePool.Label)
+		}
+		responsePoolLabels[responsePool.Label] = true
+
+		if !referenced[responsePool.Label] {
+			return fmt.Errorf("response_pool.%d: %q isn't used by any ruleset", idx, responsePool.Label)
+		}
+
+		recordSetLabels := make(map[string]bool)
+		for rsidx, recordSet := range responsePool.RecordSets {
+			if recordSetLabels[recordSet.Label] {
+				return fmt.Errorf("response_pool.%d.record_set.%d.label: %q is used by more than one record set", idx, rsidx, recordSet.Label)
+			}
+			recordSetLabels[recordSet.Label] = true
+
+			for ridx, record := range recordSet.Records {
+				if err := checkMasterLineRDataClass(recordSet.RDataClass, record.MasterLine); err != nil {
+					return fmt.Errorf("response_pool.%d.record_set.%d.record.%d.master_line: %w", idx, rsidx, ridx, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func resourceDynTrafficDirectorServiceTree(d *schema.ResourceData) (*trafficDirectorTree, error) {
+	return expandTrafficDirectorTree(d.Get("ruleset").([]interface{}), d.Get("response_pool").([]interface{}))
+}
+
+// resourceDynTrafficDirectorServiceKeepLive gives the records of the tree
+// whose weight, eligible or automation isn't configured the value of the live
+// record they update, or Dyn's default for new records. live may be nil when
+// the service is being created.
+func resourceDynTrafficDirectorServiceKeepLive(d *schema.ResourceData, tree, live *trafficDirectorTree) {
+	config := d.GetRawConfig()
+	for idx, responsePool := range tree.ResponsePools {
+		var liveResponsePool *trafficDirectorTreeResponsePool
+		if live != nil {
+			liveResponsePool = live.responsePool(responsePool.Label)
+		}
+
+		for rsidx, recordSet := range responsePool.RecordSets {
+			var liveRecordSet *trafficDirectorTreeRecordSet
+			if liveResponsePool != nil {
+				liveRecordSet = liveResponsePool.recordSet(recordSet.Label)
+			}
+
+			for ridx, record := range recordSet.Records {
+				liveRecord := &trafficDirectorTreeRecord{Weight: 1, Eligible: true, Automation: "auto"}
+				if liveRecordSet != nil {
+					if r := liveRecordSet.record(record); r != nil {
+						liveRecord = r
+					}
+				}
+
+				path := cty.GetAttrPath("response_pool").IndexInt(idx).GetAttr("record_set").IndexInt(rsidx).GetAttr("record").IndexInt(ridx)
+				if !resourceDynConfigured(config, path.GetAttr("weight")) {
+					record.Weight = liveRecord.Weight
+				}
+				if !resourceDynConfigured(config, path.GetAttr("eligible")) {
+					record.Eligible = liveRecord.Eligible
+				}
+				if !resourceDynConfigured(config, path.GetAttr("automation")) {
+					record.Automation = liveRecord.Automation
+				}
+			}
+		}
+	}
+}
+
+func trafficDirectorRulesets(tree *trafficDirectorTree) dyn.TrafficDirectorOptionSetter {
+	return func(req *dyn.TrafficDirectorCURequest) {
+		req.Rulesets = tree.rulesetRequests()
+	}
+}
+
+func resourceDynTrafficDirectorServiceCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	clientList := meta.(accessControlledClientList)
+	client, err := clientList.AcquireContext(ctx)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+
+	label := d.Get("label").(string)
+	tree, err := resourceDynTrafficDirectorServiceTree(d)
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, err)
+	}
+
+	id, err := existingTrafficDirector(client, d.Get("on_existing").(string), label)
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, err)
+	}
+	if id != "" {
+		log.Printf("[DEBUG] Adopting existing Dyn Traffic Director service (%s) labeled: %s", id, label)
+		d.SetId(id)
+		clientList.Release(client)
+		return resourceDynTrafficDirectorServiceUpdate(ctx, d, meta)
+	}
+	resourceDynTrafficDirectorServiceKeepLive(d, tree, nil)
+
+	log.Printf("[DEBUG] Dyn Traffic Director service create configuration: label: %s, rulesets: %d, response pools: %d", label, len(tree.Rulesets), len(tree.ResponsePools))
+
+	td, err := client.CreateTrafficDirector(label, resourceDynTrafficDirectorOptions(d), trafficDirectorRulesets(tree))
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, fmt.Errorf("Failed to create Dyn Traffic Director: %w", err))
+	}
+
+	d.SetId(td.ServiceID)
+	clientList.Release(client)
+	return resourceDynTrafficDirectorServiceRead(ctx, d, meta)
+}
+
+func resourceDynTrafficDirectorServiceRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	clientList := meta.(accessControlledClientList)
+	client, err := clientList.AcquireContext(ctx)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+	defer clientList.Release(client)
+
+	log.Printf("[DEBUG] Getting Traffic Director service using id: %s", d.Id())
+	td, err := clientList.GetTrafficDirector(client, d.Id())
+	if err != nil {
+		return resourceDynError(ctx, fmt.Errorf("Couldn't find Dyn Traffic Director: %w", err))
+	}
+
+	return resourceDynError(ctx, resourceDynTrafficDirectorServiceToResourceData(td, d))
+}
+
+// resourceDynTrafficDirectorServiceUpdate sends the whole tree in one service
+// update whenever anything in it changed, rather than going through the
+// endpoints of the changed children. The live IDs are adopted first, so the
+// objects Dyn already has are updated in place.
+func resourceDynTrafficDirectorServiceUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	clientList := meta.(accessControlledClientList)
+	client, err := clientList.AcquireContext(ctx)
+	if err != nil {
+		return resourceDynError(ctx, err)
+	}
+
+	label := d.Get("label").(string)
+	tree, err := resourceDynTrafficDirectorServiceTree(d)
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, err)
+	}
+
+	log.Printf("[DEBUG] Getting Traffic Director service (%s) to compute changes", d.Id())
+	td, err := client.GetTrafficDirector(d.Id())
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, fmt.Errorf("Couldn't find Dyn Traffic Director: %w", err))
+	}
+
+	live := newTrafficDirectorTree(td)
+	tree.adoptIDs(live)
+	resourceDynTrafficDirectorServiceKeepLive(d, tree, live)
+
+	options := []dyn.TrafficDirectorOptionSetter{resourceDynTrafficDirectorOptions(d)}
+	changes := trafficDirectorTreeChanges(tree, live)
+	if len(changes) > 0 {
+		log.Printf("[DEBUG] Dyn Traffic Director service (%s) changes: %s", d.Id(), strings.Join(changes, "; "))
+		options = append(options, trafficDirectorRulesets(tree))
+	}
+
+	if len(changes) > 0 || d.HasChange("label") || d.HasChange("ttl") || d.HasChange("active") {
+		log.Printf("[DEBUG] Dyn Traffic Director service update configuration for id %s: label: %s", d.Id(), label)
+		_, err = client.UpdateTrafficDirector(d.Id(), label, options...)
+		clientList.InvalidateTrafficDirector(d.Id())
+		if err != nil {
+			clientList.Release(client)
+			return resourceDynError(ctx, fmt.Errorf("Failed to update Dyn Traffic Director: %w", err))
+		}
+	}
+
+	err = resourceDynTrafficDirectorUpdateNodes(clientList, client, d)
+	if err != nil {
+		clientList.Release(client)
+		return resourceDynError(ctx, err)
+	}
+
+	clientList.Release(client)
+	return resourceDynTrafficDirectorServiceRead(ctx, d, meta)
+}
+
+func resourceDynTrafficDirectorServiceDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return resourceDynTrafficDirectorDelete(ctx, d, meta)
+}
+
+func resourceDynTrafficDirectorServiceImportState(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	results := make([]*schema.ResourceData, 1, 1)
+
+	clientList := meta.(accessControlledClientList)
+	ctx, cancel := context.WithTimeout(ctx, d.Timeout(schema.TimeoutRead))
+	defer cancel()
+	client, err := clientList.AcquireContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer clientList.Release(client)
+
+	td, err := getTrafficDirector(client, d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(td.ServiceID)
+	d.Set("node", flattenTrafficDirectorNodes(td.Nodes, nil))
+	err = resourceDynTrafficDirectorServiceToResourceData(td, d)
+	if err != nil {
+		return nil, err
+	}
+	results[0] = d
+
+	return results, nil
+}
+
+func resourceDynTrafficDirectorServiceToResourceData(td *dyn.TrafficDirector, d *schema.ResourceData) error {
+	err := resourceDynTrafficDirectorToResourceData(td, d)
+	if err != nil {
+		return fmt.Errorf("Couldn't convert Dyn Traffic Director: %w", err)
+	}
+
+	live := newTrafficDirectorTree(td)
+	if config, err := resourceDynTrafficDirectorServiceTree(d); err == nil {
+		live.alignTo(config)
+	}
+
+	rulesets := make([]interface{}, len(live.Rulesets))
+	for idx, ruleset := range live.Rulesets {
+		geolocation := make([]interface{}, 0)
+		for _, k := range []string{"region", "country", "province"} {
+			for _, v := range ruleset.Geolocation[k] {
+				m := map[string]interface{}{"region": "", "country": "", "province": ""}
+				m[k] = v
+				geolocation = append(geolocation, m)
+			}
+		}
+
+		rulesets[idx] = map[string]interface{}{
+			"id":             ruleset.ID,
+			"label":          ruleset.Label,
+			"response_pools": ruleset.ResponsePools,
+			"geolocation":    geolocation,
+		}
+	}
+
+	responsePools := make([]interface{}, len(live.ResponsePools))
+	for idx, responsePool := range live.ResponsePools {
+		recordSets := make([]interface{}, len(responsePool.RecordSets))
+		for rsidx, recordSet := range responsePool.RecordSets {
+			records := make([]interface{}, len(recordSet.Records))
+			for ridx, record := range recordSet.Records {
+				records[ridx] = map[string]interface{}{
+					"id":          record.ID,
+					"label":       record.Label,
+					"master_line": record.MasterLine,
+					"weight":      record.Weight,
+					"eligible":    record.Eligible,
+					"automation":  record.Automation,
+				}
+			}
+
+			recordSets[rsidx] = map[string]interface{}{
+				"id":          recordSet.ID,
+				"label":       recordSet.Label,
+				"rdata_class": recordSet.RDataClass,
+				"ttl":         recordSet.TTL,
+				"monitor_id":  recordSet.MonitorID,
+				"record":      records,
+			}
+		}
+
+		responsePools[idx] = map[string]interface{}{
+			"id":         responsePool.ID,
+			"label":      responsePool.Label,
+			"record_set": recordSets,
+		}
+	}
+
+	if err := d.Set("ruleset", rulesets); err != nil {
+		return fmt.Errorf("Couldn't set ruleset: %w", err)
+	}
+	if err := d.Set("response_pool", responsePools); err != nil {
+		return fmt.Errorf("Couldn't set response_pool: %w", err)
+	}
+
+	return nil
+}
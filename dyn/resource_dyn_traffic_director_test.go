@@ -0,0 +1,75 @@
+package dyn
+
+import (
+	"testing"
+
+	"github.com/Shopify/go-dyn/pkg/dyn"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func testTrafficDirectorNodeSet(nodes ...dyn.TrafficDirectorNode) *schema.Set {
+	f := schema.HashResource(trafficDirectorNodeSchema().Elem.(*schema.Resource))
+	return schema.NewSet(f, flattenTrafficDirectorNodes(nodes, nil))
+}
+
+func TestTrafficDirectorNodeChanges(t *testing.T) {
+	declared := dyn.TrafficDirectorNode{Zone: "example.com", FQDN: "www.example.com"}
+	added := dyn.TrafficDirectorNode{Zone: "example.com", FQDN: "api.example.com"}
+	external := dyn.TrafficDirectorNode{Zone: "example.com", FQDN: "shop.example.com"}
+
+	cases := []struct {
+		name           string
+		old, new       *schema.Set
+		live           []dyn.TrafficDirectorNode
+		attach, detach []dyn.TrafficDirectorNode
+	}{
+		{
+			name:   "keeps nodes attached with dyn_traffic_director_node",
+			old:    testTrafficDirectorNodeSet(declared),
+			new:    testTrafficDirectorNodeSet(declared, added),
+			live:   []dyn.TrafficDirectorNode{declared, external},
+			attach: []dyn.TrafficDirectorNode{added},
+			detach: []dyn.TrafficDirectorNode{},
+		},
+		{
+			name:   "detaches declared nodes when every block is removed",
+			old:    testTrafficDirectorNodeSet(declared, added),
+			new:    testTrafficDirectorNodeSet(),
+			live:   []dyn.TrafficDirectorNode{declared, added, external},
+			attach: []dyn.TrafficDirectorNode{},
+			detach: []dyn.TrafficDirectorNode{added, declared},
+		},
+		{
+			name:   "skips nodes already in the wanted state",
+			old:    testTrafficDirectorNodeSet(declared),
+			new:    testTrafficDirectorNodeSet(added),
+			live:   []dyn.TrafficDirectorNode{added},
+			attach: []dyn.TrafficDirectorNode{},
+			detach: []dyn.TrafficDirectorNode{},
+		},
+	}
+
+	for _, c := range cases {
+		attach, detach := trafficDirectorNodeChanges(c.old, c.new, c.live)
+		if !testTrafficDirectorNodeSet(attach...).Equal(testTrafficDirectorNodeSet(c.attach...)) || len(attach) != len(c.attach) {
+			t.Errorf("%s: expected to attach %v, got %v", c.name, c.attach, attach)
+		}
+		if !testTrafficDirectorNodeSet(detach...).Equal(testTrafficDirectorNodeSet(c.detach...)) || len(detach) != len(c.detach) {
+			t.Errorf("%s: expected to detach %v, got %v", c.name, c.detach, detach)
+		}
+	}
+}
+
+func TestFlattenTrafficDirectorNodes(t *testing.T) {
+	declared := dyn.TrafficDirectorNode{Zone: "example.com", FQDN: "www.example.com"}
+	external := dyn.TrafficDirectorNode{Zone: "example.com", FQDN: "shop.example.com"}
+	live := []dyn.TrafficDirectorNode{declared, external}
+
+	if nodes := flattenTrafficDirectorNodes(live, testTrafficDirectorNodeSet(declared)); len(nodes) != 1 || nodes[0].(map[string]interface{})["fqdn"] != declared.FQDN {
+		t.Errorf("expected only the declared node to be read, got %v", nodes)
+	}
+
+	if nodes := flattenTrafficDirectorNodes(live, nil); len(nodes) != 2 {
+		t.Errorf("expected every node to be read on import, got %v", nodes)
+	}
+}
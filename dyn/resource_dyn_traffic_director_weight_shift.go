@@ -0,0 +1,349 @@
+package dyn
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Shopify/go-dyn/pkg/dyn"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// trafficDirectorMaxWeight is the highest weight Dyn accepts for a record.
+const trafficDirectorMaxWeight = 15
+
+func resourceDynTrafficDirectorWeightShift() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDynTrafficDirectorWeightShiftCreate,
+		ReadContext:   resourceDynTrafficDirectorWeightShiftRead,
+		UpdateContext: resourceDynTrafficDirectorWeightShiftUpdate,
+		DeleteContext: resourceDynTrafficDirectorWeightShiftDelete,
+
+		CustomizeDiff: resourceDynTrafficDirectorWeightShiftCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"traffic_director_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"source_record_ids": {
+				Type:     schema.TypeSet,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Required: true,
+				ForceNew: true,
+			},
+
+			"target_record_ids": {
+				Type:     schema.TypeSet,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Required: true,
+				ForceNew: true,
+			},
+
+			"step_size": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      10,
+				ValidateFunc: validateIntBetween(1, 100),
+			},
+
+			"interval": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "1h",
+				ValidateFunc: validateDuration,
+			},
+
+			"health_gate_record_set_ids": {
+				Type:     schema.TypeSet,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Optional: true,
+			},
+
+			"healthy_statuses": {
+				Type:     schema.TypeSet,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Optional: true,
+				Computed: true,
+			},
+
+			"current_step": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"total_steps": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			// The share of the traffic reached so far, which the next
+			// step starts from.
+			"target_percent": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"last_step_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"blocked_reason": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceDynTrafficDirectorWeightShiftCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	tree, err := getTrafficDirectorDiffTree(ctx, d, meta, "source_record_ids", "target_record_ids", "health_gate_record_set_ids")
+	if err != nil || tree == nil {
+		return err
+	}
+
+	for _, key := range []string{"source_record_ids", "target_record_ids"} {
+		err := tree.checkIDs(key, "Record", trafficDirectorDiffIDs(d, key), func(id string) bool {
+			return tree.record(id) != nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return tree.checkIDs("health_gate_record_set_ids", "Record Set", trafficDirectorDiffIDs(d, "health_gate_record_set_ids"), func(id string) bool {
+		return tree.recordSet(id) != nil
+	})
+}
+
+// trafficDirectorWeightShiftSteps returns how many more steps of the given
+// size it takes to move the rest of the traffic, from the share, in percent,
+// already sent to the target records.
+func trafficDirectorWeightShiftSteps(percent, stepSize int) int {
+	if percent >= 100 {
+		return 0
+	}
+
+	return (100 - percent + stepSize - 1) / stepSize
+}
+
+// trafficDirectorWeightShiftPercent returns the share of the traffic, in
+// percent, sent to the target records one step after the given share. It
+// steps from the share reached so far, so that changing step_size midway
+// carries on from there.
+func trafficDirectorWeightShiftPercent(percent, stepSize int) int {
+	percent += stepSize
+	if percent > 100 {
+		return 100
+	}
+
+	return percent
+}
+
+// trafficDirectorWeightShiftWeights returns the weight of each source and of
+// each target record that sends the given share of the traffic, in percent,
+// to the targets as a whole. The records of a group all get the same weight,
+// so the sizes of the groups are part of the ratio. Shares that weights of 1
+// to 15 can't express are only approached. Dyn doesn't accept a
+// weight of 0, so a group that shouldn't get any traffic is made ineligible
+// instead, and keeps a weight of 1.
+func trafficDirectorWeightShiftWeights(percent, sources, targets int) (int, int) {
+	if percent <= 0 {
+		return trafficDirectorMaxWeight, 1
+	}
+	if percent >= 100 {
+		return 1, trafficDirectorMaxWeight
+	}
+
+	// The share of the targets is targets*targetWeight over the total weight,
+	// the weights that come closest to percent win. Heavier weights are tried
+	// first so that ties are settled on the finest grained ones.
+	sourceWeight, targetWeight, bestError := 0, 0, -1
+	for sw := trafficDirectorMaxWeight; sw >= 1; sw-- {
+		for tw := trafficDirectorMaxWeight; tw >= 1; tw-- {
+			err := targets*tw*100 - percent*(sources*sw+targets*tw)
+			if err < 0 {
+				err = -err
+			}
+			// Errors are scaled by the total weight, compare them as
+			// fractions of it.
+			if bestError < 0 || err*(sources*sourceWeight+targets*targetWeight) < bestError*(sources*sw+targets*tw) {
+				sourceWeight, targetWeight, bestError = sw, tw, err
+			}
+		}
+	}
+
+	return sourceWeight, targetWeight
+}
+
+func resourceDynTrafficDirectorWeightShiftCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tdID := d.Get("traffic_director_id").(string)
+
+	for _, recordID := range d.Get("source_record_ids").(*schema.Set).List() {
+		if d.Get("target_record_ids").(*schema.Set).Contains(recordID) {
+			return resourceDynError(ctx, fmt.Errorf("Record %s cannot be both a source and a target", recordID))
+		}
+	}
+
+	if len(d.Get("healthy_statuses").(*schema.Set).List()) == 0 {
+		d.Set("healthy_statuses", []string{"ok"})
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", tdID, id.UniqueId()))
+	d.Set("current_step", 0)
+	d.Set("target_percent", 0)
+	d.Set("last_step_at", "")
+
+	return resourceDynError(ctx, resourceDynTrafficDirectorWeightShiftAdvance(ctx, d, meta))
+}
+
+func resourceDynTrafficDirectorWeightShiftRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return resourceDynError(ctx, resourceDynTrafficDirectorWeightShiftAdvance(ctx, d, meta))
+}
+
+func resourceDynTrafficDirectorWeightShiftUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return resourceDynError(ctx, resourceDynTrafficDirectorWeightShiftAdvance(ctx, d, meta))
+}
+
+func resourceDynTrafficDirectorWeightShiftDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// The records keep the weights of the last step; removing the shift
+	// only stops it from advancing.
+	d.SetId("")
+	return nil
+}
+
+// resourceDynTrafficDirectorWeightShiftAdvance moves to the next step when
+// the interval has elapsed and the health gates are open, then makes sure the
+// records carry the weights of the current step. Re-applying the current step
+// is what lets an interrupted run resume.
+func resourceDynTrafficDirectorWeightShiftAdvance(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	clientList := meta.(accessControlledClientList)
+	client, err := clientList.AcquireContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer clientList.Release(client)
+
+	tdID := d.Get("traffic_director_id").(string)
+	stepSize := d.Get("step_size").(int)
+	step := d.Get("current_step").(int)
+	percent := d.Get("target_percent").(int)
+
+	interval, err := time.ParseDuration(d.Get("interval").(string))
+	if err != nil {
+		return fmt.Errorf("Invalid interval: %w", err)
+	}
+
+	due := true
+	if lastStepAt := d.Get("last_step_at").(string); lastStepAt != "" {
+		last, err := time.Parse(time.RFC3339, lastStepAt)
+		if err == nil && time.Since(last) < interval {
+			due = false
+		}
+	}
+
+	blockedReason := ""
+	if due && percent < 100 {
+		blockedReason, err = resourceDynTrafficDirectorWeightShiftGates(client, d)
+		if err != nil {
+			return err
+		}
+
+		if blockedReason == "" {
+			step++
+			percent = trafficDirectorWeightShiftPercent(percent, stepSize)
+			log.Printf("[DEBUG] Advancing Traffic Director (%s) weight shift (%s) to step %d of %d: %d%%", tdID, d.Id(), step, step+trafficDirectorWeightShiftSteps(percent, stepSize), percent)
+			d.Set("current_step", step)
+			d.Set("last_step_at", time.Now().UTC().Format(time.RFC3339))
+		} else {
+			log.Printf("[WARN] Traffic Director (%s) weight shift (%s) held at step %d: %s", tdID, d.Id(), step, blockedReason)
+		}
+	}
+
+	sources := d.Get("source_record_ids").(*schema.Set).List()
+	targets := d.Get("target_record_ids").(*schema.Set).List()
+	sourceWeight, targetWeight := trafficDirectorWeightShiftWeights(percent, len(sources), len(targets))
+	sourceEligible, targetEligible := percent < 100, percent > 0
+
+	for _, recordID := range sources {
+		err = resourceDynTrafficDirectorWeightShiftApply(clientList, client, tdID, recordID.(string), sourceWeight, sourceEligible)
+		if err != nil {
+			return err
+		}
+	}
+	for _, recordID := range targets {
+		err = resourceDynTrafficDirectorWeightShiftApply(clientList, client, tdID, recordID.(string), targetWeight, targetEligible)
+		if err != nil {
+			return err
+		}
+	}
+
+	d.Set("total_steps", step+trafficDirectorWeightShiftSteps(percent, stepSize))
+	d.Set("target_percent", percent)
+	d.Set("blocked_reason", blockedReason)
+
+	return nil
+}
+
+// resourceDynTrafficDirectorWeightShiftGates returns why the shift can't
+// advance, or an empty string when all the gating record sets are healthy.
+func resourceDynTrafficDirectorWeightShiftGates(client *dyn.Client, d *schema.ResourceData) (string, error) {
+	tdID := d.Get("traffic_director_id").(string)
+	healthy := d.Get("healthy_statuses").(*schema.Set)
+	if healthy.Len() == 0 {
+		healthy = schema.NewSet(schema.HashString, []interface{}{"ok"})
+	}
+
+	unhealthy := make([]string, 0)
+	for _, rsIDInterface := range d.Get("health_gate_record_set_ids").(*schema.Set).List() {
+		rsID := rsIDInterface.(string)
+
+		log.Printf("[DEBUG] Getting Traffic Director (%s) Record Set (%s) status", tdID, rsID)
+		tdrs, err := client.GetTrafficDirectorRecordSet(tdID, rsID)
+		if err != nil {
+			return "", fmt.Errorf("Couldn't find Dyn Traffic Director (%s) Record Set (%s): %w", tdID, rsID, err)
+		}
+
+		if !healthy.Contains(tdrs.Status) {
+			unhealthy = append(unhealthy, fmt.Sprintf("%s is %q", rsID, tdrs.Status))
+		}
+	}
+
+	if len(unhealthy) > 0 {
+		return fmt.Sprintf("unhealthy record sets: %s", strings.Join(unhealthy, ", ")), nil
+	}
+
+	return "", nil
+}
+
+func resourceDynTrafficDirectorWeightShiftApply(clientList accessControlledClientList, client *dyn.Client, tdID, recordID string, weight int, eligible bool) error {
+	tdr, err := client.GetTrafficDirectorRecord(tdID, recordID)
+	if err != nil {
+		return fmt.Errorf("Couldn't find Dyn Traffic Director (%s) Record (%s): %w", tdID, recordID, err)
+	}
+
+	if tdr.Weight == weight && tdr.Eligible == eligible {
+		return nil
+	}
+
+	log.Printf("[DEBUG] Setting Traffic Director (%s) Record (%s) weight: %d; eligible: %t", tdID, recordID, weight, eligible)
+	_, err = client.UpdateTrafficDirectorRecord(tdID, recordID, tdr.MasterLine, func(req *dyn.TrafficDirectorRecordCURequest) {
+		req.Weight = weight
+		req.Eligible = strconv.FormatBool(eligible)
+		req.Automation = tdr.Automation
+	})
+	clientList.InvalidateTrafficDirector(tdID)
+	if err != nil {
+		return fmt.Errorf("Failed to update Dyn Traffic Director (%s) Record (%s) weight: %w", tdID, recordID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package dyn
+
+import (
+	"testing"
+)
+
+func TestTrafficDirectorWeightShiftSchedule(t *testing.T) {
+	if steps := trafficDirectorWeightShiftSteps(0, 30); steps != 4 {
+		t.Errorf("expected 4 steps of 30%%, got %d", steps)
+	}
+	if steps := trafficDirectorWeightShiftSteps(30, 20); steps != 4 {
+		t.Errorf("expected 4 steps of 20%% from 30%%, got %d", steps)
+	}
+
+	// Going from steps of 10% to steps of 25% at 30% carries on from 30%.
+	percent := 0
+	for _, stepSize := range []int{10, 10, 10, 25, 25, 25} {
+		percent = trafficDirectorWeightShiftPercent(percent, stepSize)
+	}
+	if percent != 100 {
+		t.Errorf("expected to reach 100%%, got %d%%", percent)
+	}
+	if percent := trafficDirectorWeightShiftPercent(30, 25); percent != 55 {
+		t.Errorf("expected a step of 25%% from 30%% to reach 55%%, got %d%%", percent)
+	}
+
+	cases := []struct {
+		from, stepSize   int
+		sources, targets int
+		percent          int
+		sourceWeight     int
+		targetWeight     int
+	}{
+		{0, 0, 1, 1, 0, 15, 1},
+		{0, 10, 1, 1, 10, 9, 1},
+		{0, 50, 1, 1, 50, 15, 15},
+		{90, 30, 1, 1, 100, 1, 15},
+		{0, 50, 3, 1, 50, 5, 15},
+		{0, 25, 1, 2, 25, 12, 2},
+		{0, 10, 2, 3, 10, 14, 1},
+	}
+
+	for _, tc := range cases {
+		percent := trafficDirectorWeightShiftPercent(tc.from, tc.stepSize)
+		if percent != tc.percent {
+			t.Errorf("step of %d%% from %d%%: expected %d%%, got %d%%", tc.stepSize, tc.from, tc.percent, percent)
+		}
+
+		sourceWeight, targetWeight := trafficDirectorWeightShiftWeights(percent, tc.sources, tc.targets)
+		if sourceWeight != tc.sourceWeight || targetWeight != tc.targetWeight {
+			t.Errorf("%d%% with %d sources and %d targets: expected weights %d and %d, got %d and %d", percent, tc.sources, tc.targets, tc.sourceWeight, tc.targetWeight, sourceWeight, targetWeight)
+		}
+	}
+}
+
+func TestTrafficDirectorWeightShiftWeightsShare(t *testing.T) {
+	for _, groups := range [][2]int{{1, 1}, {3, 1}, {1, 3}, {4, 2}} {
+		sources, targets := groups[0], groups[1]
+		// Weights only go up to 15, so these groups can't get much closer
+		// to the ends than 20% and 80%.
+		for percent := 20; percent <= 80; percent += 10 {
+			sourceWeight, targetWeight := trafficDirectorWeightShiftWeights(percent, sources, targets)
+			share := float64(targets*targetWeight) * 100 / float64(sources*sourceWeight+targets*targetWeight)
+			if share < float64(percent)-2 || share > float64(percent)+2 {
+				t.Errorf("%d sources and %d targets at %d%%: targets get %.1f%% with weights %d and %d", sources, targets, percent, share, sourceWeight, targetWeight)
+			}
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package dyn
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceDynTimeouts adds a timeouts block to a resource, whose durations
+// bound the calls, retries and job polling of each operation: the SDK gives
+// the CRUD functions a context with that deadline. The errors of operations
+// that run out of time say which operation and object it was, see
+// resourceDynError; kind names the object, as in "Traffic Director Ruleset".
+func resourceDynTimeouts(r *schema.Resource, kind string) *schema.Resource {
+	r.Timeouts = &schema.ResourceTimeout{
+		Create: schema.DefaultTimeout(10 * time.Minute),
+		Read:   schema.DefaultTimeout(5 * time.Minute),
+		Update: schema.DefaultTimeout(10 * time.Minute),
+		Delete: schema.DefaultTimeout(10 * time.Minute),
+	}
+
+	return resourceDynOperations(r, kind)
+}
+
+// resourceDynTimeoutObject describes an object by its ID or, before it has
+// one, by its label.
+func resourceDynTimeoutObject(d *schema.ResourceData) string {
+	if d.Id() != "" {
+		return fmt.Sprintf("(%s)", d.Id())
+	}
+
+	if label, ok := d.GetOk("label"); ok {
+		return fmt.Sprintf("%q", label)
+	}
+
+	return "(new)"
+}
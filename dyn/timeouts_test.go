@@ -0,0 +1,53 @@
+package dyn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestResourceDynTimeouts(t *testing.T) {
+	r := resourceDynTimeouts(&schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"label": {Type: schema.TypeString, Optional: true},
+		},
+		CreateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return resourceDynError(ctx, fmt.Errorf("Couldn't create: %w", context.DeadlineExceeded))
+		},
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return resourceDynError(ctx, errors.New("Couldn't read: NOT_FOUND"))
+		},
+		UpdateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return resourceDynError(ctx, fmt.Errorf("Couldn't update: %s", context.DeadlineExceeded))
+		},
+		DeleteContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return nil
+		},
+	}, "Traffic Director Ruleset")
+
+	d := r.TestResourceData()
+	d.Set("label", "default")
+
+	diags := r.CreateContext(context.Background(), d, nil)
+	if len(diags) != 1 || !strings.Contains(diags[0].Summary, `trying to create Dyn Traffic Director Ruleset "default"`) || diags[0].Detail != "Couldn't create: context deadline exceeded" {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+
+	d.SetId("rl1")
+	if diags := r.ReadContext(context.Background(), d, nil); len(diags) != 1 || diags[0].Summary != "Couldn't read: NOT_FOUND" {
+		t.Fatalf("other errors should be left alone, got: %#v", diags)
+	}
+
+	if diags := r.UpdateContext(context.Background(), d, nil); len(diags) != 1 || strings.HasPrefix(diags[0].Summary, "Timed out") {
+		t.Fatalf("only errors wrapping a deadline should be reported as timeouts, got: %#v", diags)
+	}
+
+	if diags := r.DeleteContext(context.Background(), d, nil); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+}
@@ -0,0 +1,195 @@
+package dyn
+
+import (
+	"log"
+	"sync"
+
+	"github.com/Shopify/go-dyn/pkg/dyn"
+)
+
+// trafficDirectorCache keeps the Traffic Director trees, and the listings of
+// their response pools, fetched during an operation, so that the Reads and
+// plans of the many child resources of a service don't each make their own
+// call. Whatever writes to a service must invalidate it.
+type trafficDirectorCache struct {
+	mutex   sync.Mutex
+	entries map[string]*trafficDirectorCacheEntry
+}
+
+type trafficDirectorCacheEntry struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+// trafficDirectorCacheResponsePoolsKey is the key of the listing of the
+// response pools of a service, next to its tree.
+func trafficDirectorCacheResponsePoolsKey(serviceID string) string {
+	return "DSFResponsePool/" + serviceID
+}
+
+func newTrafficDirectorCache() *trafficDirectorCache {
+	return &trafficDirectorCache{
+		entries: make(map[string]*trafficDirectorCacheEntry),
+	}
+}
+
+// get returns the cached tree of a service, calling fetch when there's none.
+// Concurrent callers for the same service share a single fetch. Errors aren't
+// cached.
+func (c *trafficDirectorCache) get(serviceID string, fetch func() (*dyn.TrafficDirector, error)) (*dyn.TrafficDirector, error) {
+	v, err := c.load(serviceID, func() (interface{}, error) {
+		return fetch()
+	})
+	td, _ := v.(*dyn.TrafficDirector)
+
+	return td, err
+}
+
+// getResponsePools returns the cached listing of the response pools of a
+// service, calling fetch when there's none, like get.
+func (c *trafficDirectorCache) getResponsePools(serviceID string, fetch func() ([]*dyn.TrafficDirectorResponsePool, error)) ([]*dyn.TrafficDirectorResponsePool, error) {
+	v, err := c.load(trafficDirectorCacheResponsePoolsKey(serviceID), func() (interface{}, error) {
+		return fetch()
+	})
+	responsePools, _ := v.([]*dyn.TrafficDirectorResponsePool)
+
+	return responsePools, err
+}
+
+func (c *trafficDirectorCache) load(key string, fetch func() (interface{}, error)) (interface{}, error) {
+	if c == nil {
+		return fetch()
+	}
+
+	c.mutex.Lock()
+	entry, ok := c.entries[key]
+	if !ok {
+		entry = &trafficDirectorCacheEntry{done: make(chan struct{})}
+		c.entries[key] = entry
+	}
+	c.mutex.Unlock()
+
+	if ok {
+		<-entry.done
+		return entry.value, entry.err
+	}
+
+	log.Printf("[DEBUG] Caching Traffic Director %s", key)
+	entry.value, entry.err = fetch()
+	if entry.err != nil {
+		c.invalidateEntry(key, entry)
+	}
+	close(entry.done)
+
+	return entry.value, entry.err
+}
+
+// invalidate drops the cached tree of a service, and its response pools.
+func (c *trafficDirectorCache) invalidate(serviceID string) {
+	if c == nil {
+		return
+	}
+
+	log.Printf("[DEBUG] Invalidating cached Traffic Director (%s)", serviceID)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.entries, serviceID)
+	delete(c.entries, trafficDirectorCacheResponsePoolsKey(serviceID))
+}
+
+func (c *trafficDirectorCache) invalidateEntry(key string, entry *trafficDirectorCacheEntry) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.entries[key] == entry {
+		delete(c.entries, key)
+	}
+}
+
+// GetTrafficDirector returns the tree of a service, fetched with client at
+// most once per operation unless it's been written to since.
+func (acc accessControlledClientList) GetTrafficDirector(client *dyn.Client, serviceID string) (*dyn.TrafficDirector, error) {
+	return acc.TrafficDirectors.get(serviceID, func() (*dyn.TrafficDirector, error) {
+		return client.GetTrafficDirector(serviceID)
+	})
+}
+
+// GetTrafficDirectorResponsePools returns every response pool of a service,
+// including those no ruleset uses, fetched with client at most once per
+// operation unless the service's been written to since.
+func (acc accessControlledClientList) GetTrafficDirectorResponsePools(client *dyn.Client, serviceID string) ([]*dyn.TrafficDirectorResponsePool, error) {
+	return acc.TrafficDirectors.getResponsePools(serviceID, func() ([]*dyn.TrafficDirectorResponsePool, error) {
+		return client.GetTrafficDirectorResponsePools(serviceID)
+	})
+}
+
+// InvalidateTrafficDirector must be called after writing to a service, or to
+// any of its children.
+func (acc accessControlledClientList) InvalidateTrafficDirector(serviceID string) {
+	acc.TrafficDirectors.invalidate(serviceID)
+}
+
+// The following return a child of a service from its cached tree. Response
+// pools that aren't used by any ruleset aren't part of the tree, so what
+// can't be found there is fetched on its own.
+
+func (acc accessControlledClientList) GetTrafficDirectorRuleset(client *dyn.Client, serviceID string, rulesetID string) (*dyn.TrafficDirectorRuleset, error) {
+	if td, err := acc.GetTrafficDirector(client, serviceID); err == nil {
+		for _, ruleset := range td.Rulesets {
+			if ruleset.RulesetID == rulesetID {
+				return ruleset, nil
+			}
+		}
+	}
+
+	return client.GetTrafficDirectorRuleset(serviceID, rulesetID)
+}
+
+func (acc accessControlledClientList) GetTrafficDirectorResponsePool(client *dyn.Client, serviceID string, responsePoolID string) (*dyn.TrafficDirectorResponsePool, error) {
+	if td, err := acc.GetTrafficDirector(client, serviceID); err == nil {
+		for _, responsePool := range td.ResponsePools {
+			if responsePool.ResponsePoolID == responsePoolID {
+				return responsePool, nil
+			}
+		}
+	}
+
+	return client.GetTrafficDirectorResponsePool(serviceID, responsePoolID)
+}
+
+func (acc accessControlledClientList) GetTrafficDirectorRecordSet(client *dyn.Client, serviceID string, recordSetID string) (*dyn.TrafficDirectorRecordSet, error) {
+	if td, err := acc.GetTrafficDirector(client, serviceID); err == nil {
+		for _, responsePool := range td.ResponsePools {
+			for _, recordSet := range responsePool.RecordSets {
+				if recordSet.RecordSetID == recordSetID {
+					// The tree is shared, fill in the response pool on a copy.
+					tdrs := *recordSet
+					if tdrs.ResponsePoolID == "" {
+						tdrs.ResponsePoolID = responsePool.ResponsePoolID
+					}
+					return &tdrs, nil
+				}
+			}
+		}
+	}
+
+	return client.GetTrafficDirectorRecordSet(serviceID, recordSetID)
+}
+
+func (acc accessControlledClientList) GetTrafficDirectorRecord(client *dyn.Client, serviceID string, recordID string) (*dyn.TrafficDirectorRecord, error) {
+	if td, err := acc.GetTrafficDirector(client, serviceID); err == nil {
+		for _, responsePool := range td.ResponsePools {
+			for _, recordSet := range responsePool.RecordSets {
+				for _, record := range recordSet.Records {
+					if record.RecordID == recordID {
+						return record, nil
+					}
+				}
+			}
+		}
+	}
+
+	return client.GetTrafficDirectorRecord(serviceID, recordID)
+}
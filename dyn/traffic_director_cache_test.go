@@ -0,0 +1,52 @@
+package dyn
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Shopify/go-dyn/pkg/dyn"
+)
+
+func TestTrafficDirectorCache(t *testing.T) {
+	cache := newTrafficDirectorCache()
+
+	fetches := 0
+	fetch := func() (*dyn.TrafficDirector, error) {
+		fetches++
+		return &dyn.TrafficDirector{ServiceID: "td"}, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		if td, err := cache.get("td", fetch); err != nil || td.ServiceID != "td" {
+			t.Fatalf("unexpected result: %v, %v", td, err)
+		}
+	}
+	if fetches != 1 {
+		t.Errorf("expected a single fetch, got %d", fetches)
+	}
+
+	cache.invalidate("td")
+	cache.get("td", fetch)
+	if fetches != 2 {
+		t.Errorf("expected a fetch after invalidating, got %d", fetches)
+	}
+
+	failing := func() (*dyn.TrafficDirector, error) {
+		fetches++
+		return nil, errors.New("unavailable")
+	}
+	if _, err := cache.get("other", failing); err == nil {
+		t.Errorf("expected the fetch error")
+	}
+	cache.get("other", fetch)
+	if fetches != 4 {
+		t.Errorf("errors shouldn't be cached, got %d fetches", fetches)
+	}
+
+	var none *trafficDirectorCache
+	none.get("td", fetch)
+	none.invalidate("td")
+	if fetches != 5 {
+		t.Errorf("a nil cache should always fetch, got %d fetches", fetches)
+	}
+}
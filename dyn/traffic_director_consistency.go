@@ -0,0 +1,309 @@
+package dyn
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Shopify/go-dyn/pkg/dyn"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// unknownVariableValue is what the SDK puts in place of the elements of a list
+// that aren't known yet.
+const unknownVariableValue = "74D93920-ED26-11E3-AC10-0800200C9A66"
+
+// trafficDirectorDiffTree is what the child resources of a Traffic Director
+// are checked against at plan time: its rulesets and all of its response
+// pools, including those no ruleset uses yet.
+type trafficDirectorDiffTree struct {
+	ServiceID     string
+	Rulesets      []*dyn.TrafficDirectorRuleset
+	ResponsePools []*dyn.TrafficDirectorResponsePool
+
+	// Adopting is set when the resource is about to adopt an object with
+	// the same label, see on_existing, so labels aren't checked.
+	Adopting bool
+}
+
+// getTrafficDirectorDiffTree fetches the Traffic Director a child resource
+// belongs to. It returns nil when its ID isn't known yet, when there's no
+// client to fetch it with, or when the resource exists and neither
+// traffic_director_id nor any of the attributes given by keys, those checked
+// against the tree, changes, so that plans without changes don't call Dyn.
+func getTrafficDirectorDiffTree(ctx context.Context, d *schema.ResourceDiff, meta interface{}, keys ...string) (*trafficDirectorDiffTree, error) {
+	if meta == nil || !d.NewValueKnown("traffic_director_id") {
+		return nil, nil
+	}
+
+	if d.Id() != "" && !d.HasChanges(append([]string{"traffic_director_id"}, keys...)...) {
+		return nil, nil
+	}
+
+	tdID := d.Get("traffic_director_id").(string)
+
+	clientList := meta.(accessControlledClientList)
+	client, err := clientList.AcquireContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer clientList.Release(client)
+
+	log.Printf("[DEBUG] Getting Traffic Director (%s) to check references", tdID)
+	td, err := clientList.GetTrafficDirector(client, tdID)
+	if err != nil {
+		return nil, fmt.Errorf("traffic_director_id: Couldn't find Dyn Traffic Director (%s): %w", tdID, err)
+	}
+
+	responsePools, err := clientList.GetTrafficDirectorResponsePools(client, tdID)
+	if err != nil {
+		return nil, fmt.Errorf("traffic_director_id: Couldn't get Dyn Traffic Director (%s) Response Pools: %w", tdID, err)
+	}
+
+	return &trafficDirectorDiffTree{
+		ServiceID:     td.ServiceID,
+		Rulesets:      td.Rulesets,
+		ResponsePools: responsePools,
+	}, nil
+}
+
+// trafficDirectorDiffAdopting reports whether a resource with on_existing is
+// about to be created by adopting an existing object.
+func trafficDirectorDiffAdopting(d *schema.ResourceDiff) bool {
+	return d.Id() == "" && d.Get("on_existing").(string) == onExistingAdopt
+}
+
+// trafficDirectorDiffString returns the value of a string attribute, or an
+// empty string when it isn't known yet.
+func trafficDirectorDiffString(d *schema.ResourceDiff, key string) string {
+	if !d.NewValueKnown(key) {
+		return ""
+	}
+
+	return d.Get(key).(string)
+}
+
+// trafficDirectorDiffIDs returns the known IDs of a list attribute, with
+// empty strings in place of the unknown ones, or nil when the whole list is
+// unknown.
+func trafficDirectorDiffIDs(d *schema.ResourceDiff, key string) []string {
+	if !d.NewValueKnown(key) {
+		return nil
+	}
+
+	var raw []interface{}
+	switch v := d.Get(key).(type) {
+	case []interface{}:
+		raw = v
+	case *schema.Set:
+		raw = v.List()
+	}
+
+	ids := make([]string, len(raw))
+	for idx, id := range raw {
+		if s, ok := id.(string); ok && s != unknownVariableValue {
+			ids[idx] = s
+		}
+	}
+
+	return ids
+}
+
+func (t *trafficDirectorDiffTree) ruleset(id string) *dyn.TrafficDirectorRuleset {
+	for _, ruleset := range t.Rulesets {
+		if ruleset.RulesetID == id {
+			return ruleset
+		}
+	}
+
+	return nil
+}
+
+func (t *trafficDirectorDiffTree) responsePool(id string) *dyn.TrafficDirectorResponsePool {
+	for _, responsePool := range t.ResponsePools {
+		if responsePool.ResponsePoolID == id {
+			return responsePool
+		}
+	}
+
+	return nil
+}
+
+func (t *trafficDirectorDiffTree) recordSet(id string) *dyn.TrafficDirectorRecordSet {
+	for _, responsePool := range t.ResponsePools {
+		for _, recordSet := range responsePool.RecordSets {
+			if recordSet.RecordSetID == id {
+				return recordSet
+			}
+		}
+	}
+
+	return nil
+}
+
+func (t *trafficDirectorDiffTree) record(id string) *dyn.TrafficDirectorRecord {
+	for _, responsePool := range t.ResponsePools {
+		for _, recordSet := range responsePool.RecordSets {
+			for _, record := range recordSet.Records {
+				if record.RecordID == id {
+					return record
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkIDs checks that every known ID of a list attribute belongs to the
+// Traffic Director.
+func (t *trafficDirectorDiffTree) checkIDs(key, kind string, ids []string, exists func(string) bool) error {
+	for _, id := range ids {
+		if id != "" && !exists(id) {
+			return fmt.Errorf("%s: Dyn Traffic Director %s (%s) doesn't belong to Traffic Director (%s)", key, kind, id, t.ServiceID)
+		}
+	}
+
+	return nil
+}
+
+// checkLabel checks that no sibling other than the object itself uses the
+// label.
+func (t *trafficDirectorDiffTree) checkLabel(kind, id, label, parent string, siblingIDs, siblingLabels []string) error {
+	if t.Adopting || label == "" {
+		return nil
+	}
+
+	for idx, siblingID := range siblingIDs {
+		if siblingID != id && siblingLabels[idx] == label {
+			return fmt.Errorf("label: %q is already used by Dyn Traffic Director %s (%s) in %s", label, kind, siblingID, parent)
+		}
+	}
+
+	return nil
+}
+
+func (t *trafficDirectorDiffTree) checkRuleset(id, label string, responsePoolIDs []string) error {
+	ids := make([]string, len(t.Rulesets))
+	labels := make([]string, len(t.Rulesets))
+	for idx, ruleset := range t.Rulesets {
+		ids[idx] = ruleset.RulesetID
+		labels[idx] = ruleset.Label
+	}
+	if err := t.checkLabel("Ruleset", id, label, fmt.Sprintf("Traffic Director (%s)", t.ServiceID), ids, labels); err != nil {
+		return err
+	}
+
+	return t.checkIDs("response_pool_ids", "Response Pool", responsePoolIDs, func(id string) bool {
+		return t.responsePool(id) != nil
+	})
+}
+
+// checkRulesetOrder checks that a ruleset order lists every ruleset of the
+// Traffic Director, since those left out would end up wherever Dyn renumbered
+// them. Nothing is checked while some of the IDs aren't known.
+func (t *trafficDirectorDiffTree) checkRulesetOrder(ids []string) error {
+	if ids == nil {
+		return nil
+	}
+
+	listed := make(map[string]bool)
+	for _, id := range ids {
+		if id == "" {
+			return nil
+		}
+		listed[id] = true
+	}
+
+	missing := make([]string, 0)
+	for _, ruleset := range t.Rulesets {
+		if !listed[ruleset.RulesetID] {
+			missing = append(missing, fmt.Sprintf("%s (%s)", ruleset.RulesetID, ruleset.Label))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("ruleset_ids: must list every Ruleset of Traffic Director (%s), missing: %s", t.ServiceID, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+func (t *trafficDirectorDiffTree) checkResponsePool(id, label string) error {
+	ids := make([]string, len(t.ResponsePools))
+	labels := make([]string, len(t.ResponsePools))
+	for idx, responsePool := range t.ResponsePools {
+		ids[idx] = responsePool.ResponsePoolID
+		labels[idx] = responsePool.Label
+	}
+
+	return t.checkLabel("Response Pool", id, label, fmt.Sprintf("Traffic Director (%s)", t.ServiceID), ids, labels)
+}
+
+// checkRecordSet checks a record set against its response pool, and its
+// rdata class against the records it already has. Empty arguments are
+// unknown and aren't checked.
+func (t *trafficDirectorDiffTree) checkRecordSet(id, responsePoolID, label, rdataClass string) error {
+	if responsePoolID != "" {
+		responsePool := t.responsePool(responsePoolID)
+		if responsePool == nil {
+			return fmt.Errorf("response_pool_id: Dyn Traffic Director Response Pool (%s) doesn't belong to Traffic Director (%s)", responsePoolID, t.ServiceID)
+		}
+
+		ids := make([]string, len(responsePool.RecordSets))
+		labels := make([]string, len(responsePool.RecordSets))
+		for idx, recordSet := range responsePool.RecordSets {
+			ids[idx] = recordSet.RecordSetID
+			labels[idx] = recordSet.Label
+		}
+		if err := t.checkLabel("Record Set", id, label, fmt.Sprintf("Response Pool (%s)", responsePoolID), ids, labels); err != nil {
+			return err
+		}
+	}
+
+	if recordSet := t.recordSet(id); recordSet != nil && rdataClass != "" {
+		for _, record := range recordSet.Records {
+			if err := checkMasterLineRDataClass(rdataClass, record.MasterLine); err != nil {
+				return fmt.Errorf("rdata_class: doesn't fit Dyn Traffic Director Record (%s): %w", record.RecordID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkRecord checks a record against its record set. Empty arguments are
+// unknown and aren't checked. Errors about the master line are reported
+// against masterLineKey, which is the rdata block it comes from if any.
+func (t *trafficDirectorDiffTree) checkRecord(id, recordSetID, label, masterLine, masterLineKey string) error {
+	if recordSetID == "" {
+		return nil
+	}
+
+	recordSet := t.recordSet(recordSetID)
+	if recordSet == nil {
+		return fmt.Errorf("record_set_id: Dyn Traffic Director Record Set (%s) doesn't belong to Traffic Director (%s)", recordSetID, t.ServiceID)
+	}
+
+	ids := make([]string, len(recordSet.Records))
+	labels := make([]string, len(recordSet.Records))
+	for idx, record := range recordSet.Records {
+		ids[idx] = record.RecordID
+		labels[idx] = record.Label
+	}
+	if err := t.checkLabel("Record", id, label, fmt.Sprintf("Record Set (%s)", recordSetID), ids, labels); err != nil {
+		return err
+	}
+
+	if rdataClass, ok := trafficDirectorRecordRDataClasses[masterLineKey]; ok && !strings.EqualFold(rdataClass, recordSet.RDataClass) {
+		return fmt.Errorf("%s: cannot be used in Dyn Traffic Director Record Set (%s) with rdata_class %s", masterLineKey, recordSetID, recordSet.RDataClass)
+	}
+
+	if masterLine != "" {
+		if err := checkMasterLineRDataClass(recordSet.RDataClass, masterLine); err != nil {
+			return fmt.Errorf("%s: %w", masterLineKey, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package dyn
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Shopify/go-dyn/pkg/dyn"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func testTrafficDirectorDiffTree() *trafficDirectorDiffTree {
+	records := []*dyn.TrafficDirectorRecord{
+		{RecordID: "r1", Label: "web-1", MasterLine: "192.0.2.1"},
+		{RecordID: "r2", Label: "web-2", MasterLine: "192.0.2.2"},
+	}
+	recordSets := []*dyn.TrafficDirectorRecordSet{
+		{RecordSetID: "rs1", Label: "web", RDataClass: "A", Records: records},
+		{RecordSetID: "rs2", Label: "web6", RDataClass: "AAAA"},
+	}
+
+	return &trafficDirectorDiffTree{
+		ServiceID: "td",
+		Rulesets: []*dyn.TrafficDirectorRuleset{
+			{RulesetID: "rl1", Label: "default"},
+		},
+		ResponsePools: []*dyn.TrafficDirectorResponsePool{
+			{ResponsePoolID: "rp1", Label: "primary", RecordSets: recordSets},
+			{ResponsePoolID: "rp2", Label: "unused"},
+		},
+	}
+}
+
+func TestTrafficDirectorDiffTreeChecks(t *testing.T) {
+	tree := testTrafficDirectorDiffTree()
+
+	cases := []struct {
+		name  string
+		check func() error
+		key   string
+	}{
+		{"ruleset", func() error { return tree.checkRuleset("", "other", []string{"rp1", "", "rp2"}) }, ""},
+		{"ruleset itself", func() error { return tree.checkRuleset("rl1", "default", nil) }, ""},
+		{"ruleset label", func() error { return tree.checkRuleset("", "default", nil) }, "label"},
+		{"ruleset foreign pool", func() error { return tree.checkRuleset("", "other", []string{"rp1", "rp9"}) }, "response_pool_ids"},
+		{"ruleset order", func() error { return tree.checkRulesetOrder([]string{"rl1"}) }, ""},
+		{"ruleset order unknown", func() error { return tree.checkRulesetOrder([]string{""}) }, ""},
+		{"ruleset order missing", func() error { return tree.checkRulesetOrder([]string{"rl9"}) }, "ruleset_ids"},
+		{"response pool label", func() error { return tree.checkResponsePool("", "unused") }, "label"},
+		{"record set foreign pool", func() error { return tree.checkRecordSet("", "rp9", "web", "A") }, "response_pool_id"},
+		{"record set label", func() error { return tree.checkRecordSet("", "rp1", "web6", "AAAA") }, "label"},
+		{"record set other pool", func() error { return tree.checkRecordSet("", "rp2", "web6", "AAAA") }, ""},
+		{"record set rdata class", func() error { return tree.checkRecordSet("rs1", "rp1", "web", "AAAA") }, "rdata_class"},
+		{"record", func() error { return tree.checkRecord("", "rs1", "web-3", "192.0.2.3", "master_line") }, ""},
+		{"record foreign record set", func() error { return tree.checkRecord("", "rs9", "web-3", "192.0.2.3", "master_line") }, "record_set_id"},
+		{"record label", func() error { return tree.checkRecord("", "rs1", "web-2", "192.0.2.3", "master_line") }, "label"},
+		{"record master line", func() error { return tree.checkRecord("", "rs1", "", "2001:db8::1", "master_line") }, "master_line"},
+		{"record rdata block", func() error { return tree.checkRecord("", "rs1", "", "2001:db8::1", "aaaa") }, "aaaa"},
+	}
+
+	for _, tc := range cases {
+		err := tc.check()
+		if tc.key == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %s", tc.name, err)
+			}
+			continue
+		}
+
+		if err == nil || !strings.HasPrefix(err.Error(), tc.key+": ") {
+			t.Errorf("%s: expected an error about %s, got: %v", tc.name, tc.key, err)
+		}
+	}
+
+	tree.Adopting = true
+	if err := tree.checkRuleset("", "default", nil); err != nil {
+		t.Errorf("labels shouldn't be checked when adopting, got: %s", err)
+	}
+}
+
+func TestGetTrafficDirectorDiffTreeOnlyOnChange(t *testing.T) {
+	var mutex sync.Mutex
+	requests := make([]string, 0)
+	clientList := testDynClientList(t, func(method, resource string, body map[string]interface{}) (interface{}, int) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		requests = append(requests, method+" "+resource)
+
+		switch resource {
+		case "DSF/td":
+			return map[string]interface{}{"service_id": "td", "label": "td"}, http.StatusOK
+		case "DSFResponsePool/td":
+			return []interface{}{map[string]interface{}{"dsf_response_pool_id": "rp1", "label": "primary"}}, http.StatusOK
+		}
+		return nil, http.StatusNotFound
+	})
+	clientList.TrafficDirectors = newTrafficDirectorCache()
+
+	r := resourceDynTrafficDirectorResponsePool()
+	state := &terraform.InstanceState{
+		ID: "rp1",
+		Attributes: map[string]string{
+			"id":                  "rp1",
+			"on_existing":         onExistingError,
+			"traffic_director_id": "td",
+			"label":               "primary",
+		},
+	}
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{"traffic_director_id": "td", "label": "primary"})
+	if _, err := r.Diff(context.Background(), state, config, clientList); err != nil {
+		t.Fatalf("unexpected diff error: %s", err)
+	}
+	if len(requests) != 0 {
+		t.Errorf("expected a plan without changes not to call Dyn, got %v", requests)
+	}
+
+	config = terraform.NewResourceConfigRaw(map[string]interface{}{"traffic_director_id": "td", "label": "backup"})
+	for i := 0; i < 2; i++ {
+		if _, err := r.Diff(context.Background(), state, config, clientList); err != nil {
+			t.Fatalf("unexpected diff error: %s", err)
+		}
+	}
+	if len(requests) != 2 {
+		t.Errorf("expected the service and its response pools to be fetched once, got %v", requests)
+	}
+}
@@ -0,0 +1,57 @@
+package dyn
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Shopify/go-dyn/pkg/dyn"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// What to do when an object about to be created already exists with the same
+// label, typically because an earlier create timed out after Dyn processed it.
+const (
+	onExistingAdopt = "adopt"
+	onExistingError = "error"
+)
+
+func onExistingSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Default:      onExistingError,
+		ValidateFunc: validateStringInSlice([]string{onExistingAdopt, onExistingError}),
+	}
+}
+
+// existingTrafficDirectorObject decides what to do with the IDs of the
+// objects found with the label of one about to be created. It returns the ID
+// to adopt, or an empty string when the object should be created.
+func existingTrafficDirectorObject(onExisting, kind, label string, ids []string) (string, error) {
+	switch {
+	case len(ids) == 0:
+		return "", nil
+	case len(ids) > 1:
+		return "", fmt.Errorf("Found %d Dyn %ss labeled %q (%s), remove the duplicates or import one of them", len(ids), kind, label, strings.Join(ids, ", "))
+	case onExisting == onExistingAdopt:
+		return ids[0], nil
+	}
+
+	return "", fmt.Errorf("Dyn %s %q already exists (%s), import it or set on_existing = %q", kind, label, ids[0], onExistingAdopt)
+}
+
+// existingTrafficDirector looks up the Traffic Directors labeled label and
+// returns the ID of the one to adopt, if any.
+func existingTrafficDirector(client *dyn.Client, onExisting, label string) (string, error) {
+	existing, err := client.FindTrafficDirectors(label)
+	if err != nil {
+		return "", fmt.Errorf("Failed to look up Dyn Traffic Directors labeled %q: %w", label, err)
+	}
+
+	ids := make([]string, len(existing))
+	for idx, td := range existing {
+		ids[idx] = td.ServiceID
+	}
+
+	return existingTrafficDirectorObject(onExisting, "Traffic Director", label, ids)
+}
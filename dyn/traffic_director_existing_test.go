@@ -0,0 +1,84 @@
+package dyn
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestExistingTrafficDirectorObject(t *testing.T) {
+	cases := []struct {
+		onExisting string
+		ids        []string
+		id         string
+		fails      bool
+	}{
+		{onExistingError, nil, "", false},
+		{onExistingAdopt, nil, "", false},
+		{onExistingError, []string{"a"}, "", true},
+		{onExistingAdopt, []string{"a"}, "a", false},
+		{onExistingError, []string{"a", "b"}, "", true},
+		{onExistingAdopt, []string{"a", "b"}, "", true},
+	}
+
+	for _, tc := range cases {
+		id, err := existingTrafficDirectorObject(tc.onExisting, "Traffic Director Ruleset", "label", tc.ids)
+		if (err != nil) != tc.fails {
+			t.Errorf("%s %v: expected failure to be %t, got: %v", tc.onExisting, tc.ids, tc.fails, err)
+		}
+		if id != tc.id {
+			t.Errorf("%s %v: expected %q, got %q", tc.onExisting, tc.ids, tc.id, id)
+		}
+	}
+}
+
+func TestTrafficDirectorObjectOnExistingLookup(t *testing.T) {
+	var requests []string
+	clientList := testDynClientList(t, func(method, resource string, body map[string]interface{}) (interface{}, int) {
+		requests = append(requests, method+" "+resource)
+		switch method + " " + resource {
+		case "GET DSF/td":
+			// No ruleset uses the response pool, so the service doesn't list it.
+			return map[string]interface{}{"service_id": "td", "label": "td"}, http.StatusOK
+		case "GET DSFResponsePool/td":
+			pool := testTrafficDirectorClonePool("p1", "unused", "s1", "r1")
+			pool["rs_chains"].([]interface{})[0].(map[string]interface{})["record_sets"].([]interface{})[0].(map[string]interface{})["label"] = ""
+			return []interface{}{pool}, http.StatusOK
+		case "PUT DSFResponsePool/td/p1", "GET DSFResponsePool/td/p1":
+			return testTrafficDirectorClonePool("p1", "unused", "s1", "r1"), http.StatusOK
+		case "POST DSFRecordSet/td", "PUT DSFRecordSet/td/s2", "GET DSFRecordSet/td/s2":
+			return map[string]interface{}{"dsf_record_set_id": "s2", "rdata_class": "A", "dsf_response_pool_id": "p1"}, http.StatusOK
+		}
+		return nil, http.StatusNotFound
+	})
+
+	pool := resourceDynTrafficDirectorResponsePool()
+	d := schema.TestResourceDataRaw(t, pool.Schema, map[string]interface{}{
+		"traffic_director_id": "td",
+		"label":               "unused",
+		"on_existing":         onExistingAdopt,
+	})
+	if diags := pool.CreateContext(context.Background(), d, clientList); diags.HasError() {
+		t.Fatalf("unexpected response pool create error: %v", diags)
+	}
+	if d.Id() != "p1" {
+		t.Errorf("expected the response pool no ruleset uses to be adopted, got %q", d.Id())
+	}
+
+	requests = nil
+	recordSet := resourceDynTrafficDirectorRecordSet()
+	d = schema.TestResourceDataRaw(t, recordSet.Schema, map[string]interface{}{
+		"traffic_director_id": "td",
+		"response_pool_id":    "p1",
+		"rdata_class":         "A",
+		"on_existing":         onExistingAdopt,
+	})
+	if diags := recordSet.CreateContext(context.Background(), d, clientList); diags.HasError() {
+		t.Fatalf("unexpected record set create error: %v", diags)
+	}
+	if d.Id() != "s2" || len(requests) == 0 || requests[0] != "POST DSFRecordSet/td" {
+		t.Errorf("expected an unlabeled record set to be created rather than adopted, got %q after %v", d.Id(), requests)
+	}
+}
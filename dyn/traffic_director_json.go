@@ -0,0 +1,325 @@
+package dyn
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// trafficDirectorJSONComputedKeys are the fields of a DSF document that are
+// assigned by Dyn rather than configured.
+var trafficDirectorJSONComputedKeys = map[string]bool{
+	"service_id":           true,
+	"dsf_ruleset_id":       true,
+	"dsf_response_pool_id": true,
+	"dsf_record_set_id":    true,
+	"dsf_record_id":        true,
+	"status":               true,
+	"last_monitored":       true,
+	"pending_change":       true,
+	"publish":              true,
+}
+
+// parseTrafficDirectorJSON decodes a DSF document, keeping numbers as they
+// were written.
+func parseTrafficDirectorJSON(document string) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(strings.NewReader(document))
+	decoder.UseNumber()
+
+	var doc map[string]interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		return nil, fmt.Errorf("expected a JSON object")
+	}
+
+	return doc, nil
+}
+
+// stripTrafficDirectorJSON returns a copy of a DSF document without the
+// fields computed by Dyn. Response pools embedded in rulesets also lose their
+// back references to the rulesets using them.
+func stripTrafficDirectorJSON(v interface{}, parent string) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		stripped := make(map[string]interface{}, len(value))
+		for k, child := range value {
+			if trafficDirectorJSONComputedKeys[k] {
+				continue
+			}
+			if k == "rulesets" && parent == "response_pools" {
+				continue
+			}
+			stripped[k] = stripTrafficDirectorJSON(child, k)
+		}
+		return stripped
+	case []interface{}:
+		stripped := make([]interface{}, len(value))
+		for idx, child := range value {
+			stripped[idx] = stripTrafficDirectorJSON(child, parent)
+		}
+		return stripped
+	}
+
+	return v
+}
+
+// normalizeTrafficDirectorJSON returns the canonical form of a stripped DSF
+// document: scalars are strings, rulesets are in evaluation order without
+// their ordering field, and lists whose order doesn't matter are sorted.
+func normalizeTrafficDirectorJSON(v interface{}, parent string) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(value))
+		for k, child := range value {
+			normalized[k] = normalizeTrafficDirectorJSON(child, k)
+		}
+		if parent == "rulesets" {
+			delete(normalized, "ordering")
+		}
+		return normalized
+	case []interface{}:
+		normalized := make([]interface{}, len(value))
+		for idx, child := range value {
+			normalized[idx] = normalizeTrafficDirectorJSON(child, parent)
+		}
+
+		switch parent {
+		case "rulesets":
+			orderings := make([]int, len(value))
+			for idx, child := range value {
+				orderings[idx] = trafficDirectorJSONOrdering(child, idx)
+			}
+			indexes := make([]int, len(value))
+			for idx := range indexes {
+				indexes[idx] = idx
+			}
+			sort.SliceStable(indexes, func(i, j int) bool {
+				return orderings[indexes[i]] < orderings[indexes[j]]
+			})
+			sorted := make([]interface{}, len(normalized))
+			for idx, from := range indexes {
+				sorted[idx] = normalized[from]
+			}
+			normalized = sorted
+		case "nodes":
+			sortTrafficDirectorJSON(normalized, "zone", "fqdn")
+		case "records":
+			sortTrafficDirectorJSON(normalized, "label", "master_line")
+		case "region", "country", "province":
+			sortTrafficDirectorJSON(normalized)
+		}
+		return normalized
+	case json.Number:
+		return value.String()
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		if parent == "active" {
+			if value {
+				return "Y"
+			}
+			return "N"
+		}
+		return strconv.FormatBool(value)
+	case string:
+		if parent == "active" {
+			switch strings.ToLower(value) {
+			case "true", "y":
+				return "Y"
+			case "false", "n":
+				return "N"
+			}
+		}
+		return value
+	}
+
+	return v
+}
+
+// trafficDirectorJSONOrdering returns the ordering of a ruleset, or def when
+// it doesn't have one.
+func trafficDirectorJSONOrdering(v interface{}, def int) int {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return def
+	}
+
+	ordering, err := strconv.Atoi(fmt.Sprint(m["ordering"]))
+	if err != nil {
+		return def
+	}
+
+	return ordering
+}
+
+// sortTrafficDirectorJSON sorts normalized values by the given keys, or by
+// their own value when no key is given.
+func sortTrafficDirectorJSON(values []interface{}, keys ...string) {
+	sortKey := func(v interface{}) string {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Sprint(v)
+		}
+
+		parts := make([]string, len(keys))
+		for idx, k := range keys {
+			if m[k] != nil {
+				parts[idx] = fmt.Sprint(m[k])
+			}
+		}
+		return strings.Join(parts, "\x00")
+	}
+
+	sort.SliceStable(values, func(i, j int) bool {
+		return sortKey(values[i]) < sortKey(values[j])
+	})
+}
+
+// projectTrafficDirectorJSON keeps only the parts of a normalized live
+// document that the configured one describes, so that fields left to Dyn's
+// defaults don't show up as changes. Missing or extra list entries are kept
+// so that they do.
+func projectTrafficDirectorJSON(live, config interface{}) interface{} {
+	switch configValue := config.(type) {
+	case map[string]interface{}:
+		liveValue, ok := live.(map[string]interface{})
+		if !ok {
+			return live
+		}
+
+		projected := make(map[string]interface{}, len(configValue))
+		for k, child := range configValue {
+			if liveChild, ok := liveValue[k]; ok {
+				projected[k] = projectTrafficDirectorJSON(liveChild, child)
+			}
+		}
+		return projected
+	case []interface{}:
+		liveValue, ok := live.([]interface{})
+		if !ok {
+			return live
+		}
+
+		projected := make([]interface{}, len(liveValue))
+		for idx, liveChild := range liveValue {
+			if idx < len(configValue) {
+				projected[idx] = projectTrafficDirectorJSON(liveChild, configValue[idx])
+			} else {
+				projected[idx] = liveChild
+			}
+		}
+		return projected
+	}
+
+	return live
+}
+
+// formatTrafficDirectorJSON renders a normalized document with one field per
+// line, so that plans show which fields change.
+func formatTrafficDirectorJSON(v interface{}) (string, error) {
+	var buf bytes.Buffer
+
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// canonicalTrafficDirectorJSON parses, strips, normalizes and formats a DSF
+// document.
+func canonicalTrafficDirectorJSON(document string) (string, error) {
+	doc, err := parseTrafficDirectorJSON(document)
+	if err != nil {
+		return "", err
+	}
+
+	return formatTrafficDirectorJSON(normalizeTrafficDirectorJSON(stripTrafficDirectorJSON(doc, ""), ""))
+}
+
+// adoptTrafficDirectorJSONIDs copies the IDs of the live rulesets, response
+// pools, record sets and records onto the matching objects of a stripped
+// document, matching them by label (and master line for unlabeled records),
+// so that an update modifies them in place instead of replacing them.
+func adoptTrafficDirectorJSONIDs(doc, live map[string]interface{}) {
+	liveRulesets := make(map[string]map[string]interface{})
+	liveResponsePools := make(map[string]map[string]interface{})
+	for _, ruleset := range trafficDirectorJSONObjects(live["rulesets"]) {
+		liveRulesets[fmt.Sprint(ruleset["label"])] = ruleset
+		for _, responsePool := range trafficDirectorJSONObjects(ruleset["response_pools"]) {
+			liveResponsePools[fmt.Sprint(responsePool["label"])] = responsePool
+		}
+	}
+
+	for _, ruleset := range trafficDirectorJSONObjects(doc["rulesets"]) {
+		if liveRuleset, ok := liveRulesets[fmt.Sprint(ruleset["label"])]; ok {
+			ruleset["dsf_ruleset_id"] = liveRuleset["dsf_ruleset_id"]
+		}
+
+		for _, responsePool := range trafficDirectorJSONObjects(ruleset["response_pools"]) {
+			liveResponsePool, ok := liveResponsePools[fmt.Sprint(responsePool["label"])]
+			if !ok {
+				continue
+			}
+			responsePool["dsf_response_pool_id"] = liveResponsePool["dsf_response_pool_id"]
+
+			liveRecordSets := make(map[string]map[string]interface{})
+			for _, chain := range trafficDirectorJSONObjects(liveResponsePool["rs_chains"]) {
+				for _, recordSet := range trafficDirectorJSONObjects(chain["record_sets"]) {
+					liveRecordSets[fmt.Sprint(recordSet["label"])] = recordSet
+				}
+			}
+
+			for _, chain := range trafficDirectorJSONObjects(responsePool["rs_chains"]) {
+				for _, recordSet := range trafficDirectorJSONObjects(chain["record_sets"]) {
+					liveRecordSet, ok := liveRecordSets[fmt.Sprint(recordSet["label"])]
+					if !ok {
+						continue
+					}
+					recordSet["dsf_record_set_id"] = liveRecordSet["dsf_record_set_id"]
+
+					for _, record := range trafficDirectorJSONObjects(recordSet["records"]) {
+						for _, liveRecord := range trafficDirectorJSONObjects(liveRecordSet["records"]) {
+							if trafficDirectorJSONSameRecord(record, liveRecord) {
+								record["dsf_record_id"] = liveRecord["dsf_record_id"]
+								break
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func trafficDirectorJSONSameRecord(a, b map[string]interface{}) bool {
+	labelA, _ := a["label"].(string)
+	labelB, _ := b["label"].(string)
+	if labelA != "" || labelB != "" {
+		return labelA == labelB
+	}
+
+	masterLineA, _ := a["master_line"].(string)
+	masterLineB, _ := b["master_line"].(string)
+	return masterLinesEquivalent(masterLineA, masterLineB)
+}
+
+func trafficDirectorJSONObjects(v interface{}) []map[string]interface{} {
+	values, _ := v.([]interface{})
+	objects := make([]map[string]interface{}, 0, len(values))
+	for _, value := range values {
+		if object, ok := value.(map[string]interface{}); ok {
+			objects = append(objects, object)
+		}
+	}
+
+	return objects
+}
@@ -0,0 +1,136 @@
+package dyn
+
+import (
+	"testing"
+)
+
+const testTrafficDirectorJSONLive = `{
+  "service_id": "svc",
+  "label": "web",
+  "active": "Y",
+  "ttl": "30",
+  "notifiers": [],
+  "pending_change": "",
+  "nodes": [{"zone": "example.com", "fqdn": "www.example.com"}, {"zone": "example.com", "fqdn": "api.example.com"}],
+  "rulesets": [
+    {
+      "dsf_ruleset_id": "rs2",
+      "label": "default",
+      "ordering": "1",
+      "criteria_type": "always",
+      "response_pools": [{"dsf_response_pool_id": "rp1", "label": "pool", "rulesets": [], "rs_chains": []}]
+    },
+    {
+      "dsf_ruleset_id": "rs1",
+      "label": "eu",
+      "ordering": "0",
+      "criteria_type": "geoip",
+      "criteria": {"geoip": {"country": ["FR", "DE"]}},
+      "response_pools": [
+        {
+          "dsf_response_pool_id": "rp1",
+          "label": "pool",
+          "status": "ok",
+          "rs_chains": [
+            {
+              "record_sets": [
+                {
+                  "dsf_record_set_id": "set1",
+                  "label": "a",
+                  "rdata_class": "A",
+                  "records": [
+                    {"dsf_record_id": "r2", "master_line": "192.0.2.2", "weight": 1},
+                    {"dsf_record_id": "r1", "master_line": "192.0.2.1", "weight": 1}
+                  ]
+                }
+              ]
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}`
+
+const testTrafficDirectorJSONConfig = `{
+  "label": "web",
+  "active": true,
+  "ttl": 30,
+  "nodes": [{"zone": "example.com", "fqdn": "api.example.com"}, {"zone": "example.com", "fqdn": "www.example.com"}],
+  "rulesets": [
+    {
+      "label": "eu",
+      "criteria_type": "geoip",
+      "criteria": {"geoip": {"country": ["DE", "FR"]}},
+      "response_pools": [
+        {
+          "label": "pool",
+          "rs_chains": [
+            {
+              "record_sets": [
+                {
+                  "label": "a",
+                  "rdata_class": "A",
+                  "records": [
+                    {"master_line": "192.0.2.1", "weight": 1},
+                    {"master_line": "192.0.2.2", "weight": 1}
+                  ]
+                }
+              ]
+            }
+          ]
+        }
+      ]
+    },
+    {
+      "label": "default",
+      "criteria_type": "always",
+      "response_pools": [{"label": "pool"}]
+    }
+  ]
+}`
+
+func TestTrafficDirectorJSONSemanticallyEqual(t *testing.T) {
+	live, err := parseTrafficDirectorJSON(testTrafficDirectorJSONLive)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, err := canonicalTrafficDirectorJSON(testTrafficDirectorJSONConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, _ := parseTrafficDirectorJSON(expected)
+
+	normalized := normalizeTrafficDirectorJSON(stripTrafficDirectorJSON(live, ""), "")
+	actual, err := formatTrafficDirectorJSON(projectTrafficDirectorJSON(normalized, config))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if actual != expected {
+		t.Errorf("expected live document to match the configuration\nexpected:\n%s\nactual:\n%s", expected, actual)
+	}
+}
+
+func TestAdoptTrafficDirectorJSONIDs(t *testing.T) {
+	live, _ := parseTrafficDirectorJSON(testTrafficDirectorJSONLive)
+	doc, _ := parseTrafficDirectorJSON(testTrafficDirectorJSONConfig)
+	adoptTrafficDirectorJSONIDs(doc, live)
+
+	rulesets := trafficDirectorJSONObjects(doc["rulesets"])
+	if rulesets[0]["dsf_ruleset_id"] != "rs1" || rulesets[1]["dsf_ruleset_id"] != "rs2" {
+		t.Errorf("unexpected ruleset IDs: %v, %v", rulesets[0]["dsf_ruleset_id"], rulesets[1]["dsf_ruleset_id"])
+	}
+
+	pool := trafficDirectorJSONObjects(rulesets[0]["response_pools"])[0]
+	if pool["dsf_response_pool_id"] != "rp1" {
+		t.Errorf("unexpected response pool ID: %v", pool["dsf_response_pool_id"])
+	}
+
+	recordSet := trafficDirectorJSONObjects(trafficDirectorJSONObjects(pool["rs_chains"])[0]["record_sets"])[0]
+	records := trafficDirectorJSONObjects(recordSet["records"])
+	if recordSet["dsf_record_set_id"] != "set1" || records[0]["dsf_record_id"] != "r1" || records[1]["dsf_record_id"] != "r2" {
+		t.Errorf("unexpected record set or record IDs: %v", recordSet)
+	}
+}
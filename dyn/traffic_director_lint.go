@@ -0,0 +1,180 @@
+package dyn
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Shopify/go-dyn/pkg/dyn"
+)
+
+// Severities of Traffic Director lint findings.
+const (
+	trafficDirectorLintError   = "error"
+	trafficDirectorLintWarning = "warning"
+)
+
+// trafficDirectorFinding is a problem found in a Traffic Director tree.
+type trafficDirectorFinding struct {
+	Severity   string
+	Code       string
+	ObjectType string
+	ObjectID   string
+	Message    string
+}
+
+func (f trafficDirectorFinding) String() string {
+	return fmt.Sprintf("%s: %s (%s %s): %s", f.Severity, f.Code, f.ObjectType, f.ObjectID, f.Message)
+}
+
+// trafficDirectorObjectName describes an object by label and ID.
+func trafficDirectorObjectName(label, id string) string {
+	if label == "" {
+		return id
+	}
+
+	return fmt.Sprintf("%q (%s)", label, id)
+}
+
+// lintTrafficDirector analyses a Traffic Director tree and returns what looks
+// broken in it, rulesets first in evaluation order. The response pools of the
+// service should be all of them, as listed by GetTrafficDirectorResponsePools,
+// so that those no ruleset points at are found.
+func lintTrafficDirector(td *dyn.TrafficDirector) []trafficDirectorFinding {
+	findings := make([]trafficDirectorFinding, 0)
+	add := func(severity, code, objectType, objectID, format string, args ...interface{}) {
+		findings = append(findings, trafficDirectorFinding{
+			Severity:   severity,
+			Code:       code,
+			ObjectType: objectType,
+			ObjectID:   objectID,
+			Message:    fmt.Sprintf(format, args...),
+		})
+	}
+
+	// A ruleset is shadowed when everything it matches is already matched
+	// by an earlier ruleset. A country is matched by an earlier ruleset with
+	// that country or with the region of that country.
+	var catchAll *dyn.TrafficDirectorRuleset
+	seen := map[string]map[string]*dyn.TrafficDirectorRuleset{
+		"region":   {},
+		"country":  {},
+		"province": {},
+	}
+	reachable := make(map[string]bool)
+	referenced := make(map[string]bool)
+
+	for _, ruleset := range sortedTrafficDirectorRulesets(td) {
+		name := trafficDirectorObjectName(ruleset.Label, ruleset.RulesetID)
+		for _, responsePool := range ruleset.ResponsePools {
+			referenced[responsePool.ResponsePoolID] = true
+		}
+
+		if len(ruleset.ResponsePools) == 0 {
+			add(trafficDirectorLintError, "ruleset_without_response_pools", "ruleset", ruleset.RulesetID,
+				"ruleset %s doesn't have any response pool", name)
+		}
+
+		if catchAll != nil {
+			add(trafficDirectorLintWarning, "ruleset_shadowed", "ruleset", ruleset.RulesetID,
+				"ruleset %s is never evaluated: ruleset %s before it matches every client", name, trafficDirectorObjectName(catchAll.Label, catchAll.RulesetID))
+			continue
+		}
+
+		codes := map[string][]string{
+			"region":   ruleset.Criteria.Geolocation.Regions,
+			"country":  ruleset.Criteria.Geolocation.Countries,
+			"province": ruleset.Criteria.Geolocation.Provinces,
+		}
+		total, shadowed := 0, 0
+		for _, kind := range []string{"region", "country", "province"} {
+			for _, code := range codes[kind] {
+				total++
+				earlier, ok := seen[kind][code]
+				if !ok && kind == "country" {
+					earlier, ok = seen["region"][geolocationCountryRegion(code)]
+				}
+				if ok {
+					shadowed++
+					add(trafficDirectorLintWarning, "geolocation_shadowed", "ruleset", ruleset.RulesetID,
+						"%s %s of ruleset %s is already matched by ruleset %s", kind, code, name, trafficDirectorObjectName(earlier.Label, earlier.RulesetID))
+				}
+			}
+		}
+		for kind, kindCodes := range codes {
+			for _, code := range kindCodes {
+				if _, ok := seen[kind][code]; !ok {
+					seen[kind][code] = ruleset
+				}
+			}
+		}
+
+		if total == 0 {
+			catchAll = ruleset
+		} else if shadowed == total {
+			add(trafficDirectorLintWarning, "ruleset_shadowed", "ruleset", ruleset.RulesetID,
+				"ruleset %s is never evaluated: all of its geolocation criteria are matched by earlier rulesets", name)
+			continue
+		}
+
+		for _, responsePool := range ruleset.ResponsePools {
+			reachable[responsePool.ResponsePoolID] = true
+		}
+	}
+
+	for _, responsePool := range td.ResponsePools {
+		name := trafficDirectorObjectName(responsePool.Label, responsePool.ResponsePoolID)
+
+		if !referenced[responsePool.ResponsePoolID] {
+			add(trafficDirectorLintWarning, "response_pool_unreachable", "response_pool", responsePool.ResponsePoolID,
+				"response pool %s isn't used by any ruleset", name)
+		} else if !reachable[responsePool.ResponsePoolID] {
+			add(trafficDirectorLintWarning, "response_pool_unreachable", "response_pool", responsePool.ResponsePoolID,
+				"response pool %s is only used by rulesets that are never evaluated", name)
+		}
+
+		if len(responsePool.RecordSets) == 0 {
+			add(trafficDirectorLintError, "response_pool_without_record_sets", "response_pool", responsePool.ResponsePoolID,
+				"response pool %s doesn't have any record set", name)
+		}
+
+		for _, recordSet := range responsePool.RecordSets {
+			rsName := trafficDirectorObjectName(recordSet.Label, recordSet.RecordSetID)
+
+			if len(recordSet.Records) == 0 {
+				add(trafficDirectorLintError, "record_set_without_records", "record_set", recordSet.RecordSetID,
+					"record set %s of response pool %s doesn't have any record", rsName, name)
+			}
+
+			if recordSet.MonitorID == "" {
+				add(trafficDirectorLintWarning, "record_set_without_monitor", "record_set", recordSet.RecordSetID,
+					"record set %s of response pool %s isn't monitored, its records are served even when down", rsName, name)
+			}
+
+			for _, record := range recordSet.Records {
+				if err := checkMasterLineRDataClass(recordSet.RDataClass, record.MasterLine); err != nil {
+					add(trafficDirectorLintError, "record_master_line_mismatch", "record", record.RecordID,
+						"record %s of record set %s: %s", trafficDirectorObjectName(record.Label, record.RecordID), rsName, err)
+				}
+			}
+		}
+	}
+
+	return findings
+}
+
+// trafficDirectorFindingsError returns an error listing the findings with the
+// error severity, or nil when there aren't any.
+func trafficDirectorFindingsError(tdID string, findings []trafficDirectorFinding) error {
+	errors := make([]string, 0)
+	for _, finding := range findings {
+		if finding.Severity == trafficDirectorLintError {
+			errors = append(errors, "  - "+finding.String())
+		}
+	}
+
+	if len(errors) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("Dyn Traffic Director (%s) has %d lint error(s):\n%s", tdID, len(errors), strings.Join(errors, "\n"))
+}
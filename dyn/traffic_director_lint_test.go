@@ -0,0 +1,101 @@
+package dyn
+
+import (
+	"testing"
+
+	"github.com/Shopify/go-dyn/pkg/dyn"
+)
+
+func TestLintTrafficDirector(t *testing.T) {
+	td := testTrafficDirectorResolutionFixture()
+
+	// A pool without record sets and a record that doesn't fit its set.
+	empty := &dyn.TrafficDirectorResponsePool{ResponsePoolID: "rp-empty", Label: "empty", Eligible: true}
+	td.ResponsePools = append(td.ResponsePools, empty)
+	td.Rulesets[1].ResponsePools = append(td.Rulesets[1].ResponsePools, empty)
+	td.ResponsePools[1].RecordSets[1].RDataClass = "A"
+	td.ResponsePools[1].RecordSets[1].MonitorID = "monitor"
+	td.ResponsePools[1].RecordSets[1].Records[0].MasterLine = "2001:db8::1"
+
+	// A ruleset after the catch-all and one with an overlapping country.
+	late := &dyn.TrafficDirectorRuleset{RulesetID: "late", Ordering: 3}
+	late.Criteria.Geolocation.Countries = []string{"DE"}
+	france := &dyn.TrafficDirectorRuleset{RulesetID: "france", Ordering: 1, ResponsePools: []*dyn.TrafficDirectorResponsePool{td.ResponsePools[0]}}
+	france.Criteria.Geolocation.Countries = []string{"FR"}
+	td.Rulesets[1].Ordering = 0
+	td.Rulesets[2].Ordering = 1
+	france.Ordering = 2
+	td.Rulesets[0].Ordering = 3
+	late.Ordering = 4
+	td.Rulesets = append(td.Rulesets, late, france)
+
+	counts := make(map[string]int)
+	for _, finding := range lintTrafficDirector(td) {
+		counts[finding.Code]++
+	}
+
+	expected := map[string]int{
+		"ruleset_without_response_pools":    1,
+		"ruleset_shadowed":                  2,
+		"geolocation_shadowed":              1,
+		"response_pool_without_record_sets": 1,
+		"record_set_without_monitor":        3,
+		"record_master_line_mismatch":       1,
+	}
+	for code, count := range expected {
+		if counts[code] != count {
+			t.Errorf("expected %d %s findings, got %d", count, code, counts[code])
+		}
+	}
+	if counts["response_pool_unreachable"] != 0 {
+		t.Errorf("expected every pool to be reachable, got %d unreachable", counts["response_pool_unreachable"])
+	}
+
+	if err := trafficDirectorFindingsError("td", lintTrafficDirector(td)); err == nil {
+		t.Errorf("expected lint errors to fail")
+	}
+}
+
+func TestLintTrafficDirectorCountryInRegion(t *testing.T) {
+	td := testTrafficDirectorResolutionFixture()
+
+	// Germany is in the EU Central region of the European ruleset, Spain
+	// isn't.
+	germany := &dyn.TrafficDirectorRuleset{RulesetID: "germany", Ordering: 2, ResponsePools: []*dyn.TrafficDirectorResponsePool{td.ResponsePools[1]}}
+	germany.Criteria.Geolocation.Countries = []string{"DE"}
+	spain := &dyn.TrafficDirectorRuleset{RulesetID: "spain", Ordering: 2, ResponsePools: []*dyn.TrafficDirectorResponsePool{td.ResponsePools[1]}}
+	spain.Criteria.Geolocation.Countries = []string{"ES"}
+	td.Rulesets[0].Ordering = 3
+	td.Rulesets = append(td.Rulesets, germany, spain)
+
+	shadowed := make(map[string]bool)
+	for _, finding := range lintTrafficDirector(td) {
+		if finding.Code == "ruleset_shadowed" {
+			shadowed[finding.ObjectID] = true
+		}
+	}
+
+	if !shadowed["germany"] {
+		t.Errorf("expected a country ruleset within an earlier region to be shadowed")
+	}
+	if shadowed["spain"] {
+		t.Errorf("expected a country ruleset outside of earlier regions not to be shadowed")
+	}
+}
+
+func TestLintTrafficDirectorUnreferencedResponsePool(t *testing.T) {
+	td := testTrafficDirectorResolutionFixture()
+	unused := &dyn.TrafficDirectorResponsePool{ResponsePoolID: "rp-unused", Label: "unused", Eligible: true}
+	td.ResponsePools = append(td.ResponsePools, unused)
+
+	unreachable := make([]string, 0)
+	for _, finding := range lintTrafficDirector(td) {
+		if finding.Code == "response_pool_unreachable" {
+			unreachable = append(unreachable, finding.ObjectID)
+		}
+	}
+
+	if len(unreachable) != 1 || unreachable[0] != "rp-unused" {
+		t.Errorf("expected only the pool without rulesets to be unreachable, got %v", unreachable)
+	}
+}
@@ -0,0 +1,207 @@
+package dyn
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/Shopify/go-dyn/pkg/dyn"
+)
+
+// getTrafficDirector gets a Traffic Director by ID or, failing that, by label.
+func getTrafficDirector(client *dyn.Client, idOrLabel string) (*dyn.TrafficDirector, error) {
+	log.Printf("[DEBUG] Trying to get Traffic Director using id: %s", idOrLabel)
+	td, err := client.GetTrafficDirector(idOrLabel)
+	if err != nil {
+		log.Printf("[DEBUG] Error: %s / Trying to get Traffic Director using label: %s", err, idOrLabel)
+		td, err = client.FindTrafficDirector(idOrLabel)
+		if err != nil {
+			return nil, fmt.Errorf("Couldn't find Dyn Traffic Director: %w", err)
+		}
+	}
+
+	return td, nil
+}
+
+// trafficDirectorMatch picks the object whose ID is idOrLabel or, when there
+// is none, the only one labeled idOrLabel. It returns -1 when nothing matches
+// and an error when the label is ambiguous.
+func trafficDirectorMatch(kind, idOrLabel string, count int, id func(int) string, labels func(int) []string) (int, error) {
+	matched := -1
+	for idx := 0; idx < count; idx++ {
+		if id(idx) == idOrLabel {
+			return idx, nil
+		}
+
+		for _, label := range labels(idx) {
+			if label != "" && label == idOrLabel {
+				if matched >= 0 && matched != idx {
+					return -1, fmt.Errorf("More than one Dyn Traffic Director %s matches %q: %s and %s", kind, idOrLabel, id(matched), id(idx))
+				}
+				matched = idx
+			}
+		}
+	}
+
+	return matched, nil
+}
+
+// findTrafficDirectorRuleset finds a ruleset of a Traffic Director by ID or
+// label.
+func findTrafficDirectorRuleset(td *dyn.TrafficDirector, idOrLabel string) (*dyn.TrafficDirectorRuleset, error) {
+	log.Printf("[DEBUG] Trying to get Dyn Traffic Director Ruleset using id/label: %s", idOrLabel)
+	idx, err := trafficDirectorMatch("Ruleset", idOrLabel, len(td.Rulesets),
+		func(i int) string { return td.Rulesets[i].RulesetID },
+		func(i int) []string { return []string{td.Rulesets[i].Label} })
+	if err != nil {
+		return nil, err
+	}
+	if idx < 0 {
+		return nil, fmt.Errorf("Couldn't find Dyn Traffic Director (%s) Ruleset: %s", td.ServiceID, idOrLabel)
+	}
+
+	return td.Rulesets[idx], nil
+}
+
+// findTrafficDirectorResponsePool finds a response pool of a Traffic Director
+// by ID or label.
+func findTrafficDirectorResponsePool(td *dyn.TrafficDirector, idOrLabel string) (*dyn.TrafficDirectorResponsePool, error) {
+	log.Printf("[DEBUG] Trying to get Dyn Traffic Director Response Pool using id/label: %s", idOrLabel)
+	idx, err := trafficDirectorMatch("Response Pool", idOrLabel, len(td.ResponsePools),
+		func(i int) string { return td.ResponsePools[i].ResponsePoolID },
+		func(i int) []string { return []string{td.ResponsePools[i].Label} })
+	if err != nil {
+		return nil, err
+	}
+	if idx < 0 {
+		return nil, fmt.Errorf("Couldn't find Dyn Traffic Director (%s) Response Pool: %s", td.ServiceID, idOrLabel)
+	}
+
+	return td.ResponsePools[idx], nil
+}
+
+// findTrafficDirectorRecordSet finds a record set of a response pool by ID,
+// label or rdata class. When idOrLabel is empty, the pool must only have one
+// record set.
+func findTrafficDirectorRecordSet(tdrp *dyn.TrafficDirectorResponsePool, idOrLabel string) (*dyn.TrafficDirectorRecordSet, error) {
+	if idOrLabel == "" {
+		if len(tdrp.RecordSets) == 1 {
+			return tdrp.RecordSets[0], nil
+		}
+		return nil, fmt.Errorf("Dyn Traffic Director Response Pool (%s) has %d record sets, one must be picked", tdrp.ResponsePoolID, len(tdrp.RecordSets))
+	}
+
+	log.Printf("[DEBUG] Trying to get Dyn Traffic Director Record Set using id/label: %s", idOrLabel)
+	idx, err := trafficDirectorMatch("Record Set", idOrLabel, len(tdrp.RecordSets),
+		func(i int) string { return tdrp.RecordSets[i].RecordSetID },
+		func(i int) []string { return []string{tdrp.RecordSets[i].Label, tdrp.RecordSets[i].RDataClass} })
+	if err != nil {
+		return nil, err
+	}
+	if idx < 0 {
+		return nil, fmt.Errorf("Couldn't find Dyn Traffic Director (%s) Record Set: %s", tdrp.ResponsePoolID, idOrLabel)
+	}
+
+	return tdrp.RecordSets[idx], nil
+}
+
+// findTrafficDirectorRecord finds a record of a record set by ID or label.
+func findTrafficDirectorRecord(tdrs *dyn.TrafficDirectorRecordSet, idOrLabel string) (*dyn.TrafficDirectorRecord, error) {
+	log.Printf("[DEBUG] Trying to get Dyn Traffic Director Record using id/label: %s", idOrLabel)
+	idx, err := trafficDirectorMatch("Record", idOrLabel, len(tdrs.Records),
+		func(i int) string { return tdrs.Records[i].RecordID },
+		func(i int) []string { return []string{tdrs.Records[i].Label} })
+	if err != nil {
+		return nil, err
+	}
+	if idx < 0 {
+		return nil, fmt.Errorf("Couldn't find Dyn Traffic Director (%s) Record: %s", tdrs.RecordSetID, idOrLabel)
+	}
+
+	return tdrs.Records[idx], nil
+}
+
+// findTrafficDirectorRecordSetInService finds a record set by ID, label or
+// rdata class, within the given response pool or, when responsePool is
+// empty, anywhere in the service.
+func findTrafficDirectorRecordSetInService(td *dyn.TrafficDirector, responsePool string, idOrLabel string) (*dyn.TrafficDirectorResponsePool, *dyn.TrafficDirectorRecordSet, error) {
+	if responsePool != "" {
+		tdrp, err := findTrafficDirectorResponsePool(td, responsePool)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		tdrs, err := findTrafficDirectorRecordSet(tdrp, idOrLabel)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return tdrp, tdrs, nil
+	}
+
+	pools := make([]*dyn.TrafficDirectorResponsePool, 0)
+	recordSets := make([]*dyn.TrafficDirectorRecordSet, 0)
+	for _, tdrp := range td.ResponsePools {
+		for _, tdrs := range tdrp.RecordSets {
+			pools = append(pools, tdrp)
+			recordSets = append(recordSets, tdrs)
+		}
+	}
+
+	idx, err := trafficDirectorMatch("Record Set", idOrLabel, len(recordSets),
+		func(i int) string { return recordSets[i].RecordSetID },
+		func(i int) []string { return []string{recordSets[i].Label, recordSets[i].RDataClass} })
+	if err != nil {
+		return nil, nil, err
+	}
+	if idx < 0 {
+		return nil, nil, fmt.Errorf("Couldn't find Dyn Traffic Director (%s) Record Set: %s", td.ServiceID, idOrLabel)
+	}
+
+	return pools[idx], recordSets[idx], nil
+}
+
+// findTrafficDirectorRecordInService finds a record by ID or label, within
+// the given record set or, when recordSet is empty, anywhere in the response
+// pool or service.
+func findTrafficDirectorRecordInService(td *dyn.TrafficDirector, responsePool string, recordSet string, idOrLabel string) (*dyn.TrafficDirectorRecordSet, *dyn.TrafficDirectorRecord, error) {
+	recordSets := make([]*dyn.TrafficDirectorRecordSet, 0)
+	switch {
+	case recordSet != "":
+		_, tdrs, err := findTrafficDirectorRecordSetInService(td, responsePool, recordSet)
+		if err != nil {
+			return nil, nil, err
+		}
+		recordSets = append(recordSets, tdrs)
+	case responsePool != "":
+		tdrp, err := findTrafficDirectorResponsePool(td, responsePool)
+		if err != nil {
+			return nil, nil, err
+		}
+		recordSets = append(recordSets, tdrp.RecordSets...)
+	default:
+		for _, tdrp := range td.ResponsePools {
+			recordSets = append(recordSets, tdrp.RecordSets...)
+		}
+	}
+
+	owners := make([]*dyn.TrafficDirectorRecordSet, 0)
+	records := make([]*dyn.TrafficDirectorRecord, 0)
+	for _, tdrs := range recordSets {
+		for _, tdr := range tdrs.Records {
+			owners = append(owners, tdrs)
+			records = append(records, tdr)
+		}
+	}
+
+	idx, err := trafficDirectorMatch("Record", idOrLabel, len(records),
+		func(i int) string { return records[i].RecordID },
+		func(i int) []string { return []string{records[i].Label} })
+	if err != nil {
+		return nil, nil, err
+	}
+	if idx < 0 {
+		return nil, nil, fmt.Errorf("Couldn't find Dyn Traffic Director (%s) Record: %s", td.ServiceID, idOrLabel)
+	}
+
+	return owners[idx], records[idx], nil
+}
@@ -0,0 +1,57 @@
+package dyn
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// trafficDirectorV1Attributes are the attributes the Traffic Director
+// resources gained in schema version 1, with the value given to resources
+// created before they existed. Each resource only has some of them.
+var trafficDirectorV1Attributes = map[string]interface{}{
+	"on_existing":  onExistingError,
+	"force_detach": false,
+}
+
+// trafficDirectorStateUpgraders returns the state upgraders of the Traffic
+// Director resources that gained on_existing, and for monitors force_detach,
+// in schema version 1.
+func trafficDirectorStateUpgraders(r *schema.Resource) []schema.StateUpgrader {
+	v0 := make(map[string]*schema.Schema, len(r.Schema))
+	defaults := make(map[string]interface{})
+	for k, v := range r.Schema {
+		if value, ok := trafficDirectorV1Attributes[k]; ok {
+			defaults[k] = value
+			continue
+		}
+		v0[k] = v
+	}
+
+	return []schema.StateUpgrader{
+		{
+			Version: 0,
+			Type:    (&schema.Resource{Schema: v0}).CoreConfigSchema().ImpliedType(),
+			Upgrade: trafficDirectorStateUpgradeV0(defaults),
+		},
+	}
+}
+
+// trafficDirectorStateUpgradeV0 records the defaults of the attributes added
+// in version 1 for resources created before they existed, so that they don't
+// show a spurious update.
+func trafficDirectorStateUpgradeV0(defaults map[string]interface{}) schema.StateUpgradeFunc {
+	return func(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+		if rawState == nil {
+			rawState = make(map[string]interface{})
+		}
+
+		for k, value := range defaults {
+			if v, ok := rawState[k]; !ok || v == nil || v == "" {
+				rawState[k] = value
+			}
+		}
+
+		return rawState, nil
+	}
+}
@@ -0,0 +1,63 @@
+package dyn
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTrafficDirectorStateUpgradeV0(t *testing.T) {
+	cases := []struct {
+		state    map[string]interface{}
+		expected string
+	}{
+		{map[string]interface{}{"label": "service"}, onExistingError},
+		{map[string]interface{}{"label": "service", "on_existing": ""}, onExistingError},
+		{map[string]interface{}{"label": "service", "on_existing": onExistingAdopt}, onExistingAdopt},
+	}
+
+	upgrade := trafficDirectorStateUpgradeV0(map[string]interface{}{"on_existing": onExistingError})
+	for _, tc := range cases {
+		state, err := upgrade(context.Background(), tc.state, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if state["on_existing"] != tc.expected {
+			t.Errorf("expected on_existing to be %q, got %q", tc.expected, state["on_existing"])
+		}
+		if state["label"] != "service" {
+			t.Errorf("expected label to be kept, got %q", state["label"])
+		}
+	}
+
+	r := resourceDynTrafficDirectorRecordSet()
+	if _, ok := r.StateUpgraders[0].Type.AttributeTypes()["on_existing"]; ok {
+		t.Error("expected the version 0 type not to have on_existing")
+	}
+	if _, ok := r.StateUpgraders[0].Type.AttributeTypes()["response_pool_id"]; !ok {
+		t.Error("expected the version 0 type to have response_pool_id")
+	}
+	if _, ok := r.StateUpgraders[0].Type.AttributeTypes()["force_detach"]; ok {
+		t.Error("expected the version 0 type of record sets not to have force_detach")
+	}
+
+	monitor := resourceDynTrafficDirectorMonitor()
+	if _, ok := monitor.StateUpgraders[0].Type.AttributeTypes()["force_detach"]; ok {
+		t.Error("expected the version 0 type of monitors not to have force_detach")
+	}
+	state, err := monitor.StateUpgraders[0].Upgrade(context.Background(), map[string]interface{}{"label": "monitor"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state["force_detach"] != false || state["on_existing"] != onExistingError {
+		t.Errorf("expected the monitor to get the defaults of force_detach and on_existing, got %v", state)
+	}
+
+	rs, err := r.StateUpgraders[0].Upgrade(context.Background(), map[string]interface{}{"label": "web"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := rs["force_detach"]; ok {
+		t.Errorf("expected record sets not to get force_detach, got %v", rs)
+	}
+}
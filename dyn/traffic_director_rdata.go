@@ -0,0 +1,341 @@
+package dyn
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// trafficDirectorRecordRDataClasses maps each typed rdata block of a
+// Traffic Director record to the record set rdata_class it belongs to.
+var trafficDirectorRecordRDataClasses = map[string]string{
+	"a":     "A",
+	"aaaa":  "AAAA",
+	"cname": "CNAME",
+	"mx":    "MX",
+	"txt":   "TXT",
+	"srv":   "SRV",
+	"caa":   "CAA",
+}
+
+func trafficDirectorRecordRDataTypes() []string {
+	rdataTypes := make([]string, 0, len(trafficDirectorRecordRDataClasses))
+	for rdataType := range trafficDirectorRecordRDataClasses {
+		rdataTypes = append(rdataTypes, rdataType)
+	}
+	sort.Strings(rdataTypes)
+
+	return rdataTypes
+}
+
+// trafficDirectorRecordRDataSchema returns the typed rdata blocks, each of
+// them conflicting with all the others.
+func trafficDirectorRecordRDataSchema() map[string]*schema.Schema {
+	fields := map[string]map[string]*schema.Schema{
+		"a": {
+			"address": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateIPv4Address,
+			},
+		},
+		"aaaa": {
+			"address": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateIPv6Address,
+			},
+		},
+		"cname": {
+			"cname": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateHostname,
+			},
+		},
+		"mx": {
+			"preference": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validateIntBetween(0, 65535),
+			},
+			"exchange": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateHostname,
+			},
+		},
+		"txt": {
+			"txtdata": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+		},
+		"srv": {
+			"priority": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validateIntBetween(0, 65535),
+			},
+			"weight": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validateIntBetween(0, 65535),
+			},
+			"port": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validateIntBetween(0, 65535),
+			},
+			"target": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateHostname,
+			},
+		},
+		"caa": {
+			"flags": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validateIntBetween(0, 255),
+			},
+			"tag": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateStringInSlice([]string{"issue", "issuewild", "iodef"}),
+			},
+			"value": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+		},
+	}
+
+	rdataTypes := trafficDirectorRecordRDataTypes()
+	blocks := make(map[string]*schema.Schema, len(fields))
+	for rdataType, blockSchema := range fields {
+		conflicts := make([]string, 0, len(rdataTypes)-1)
+		for _, other := range rdataTypes {
+			if other != rdataType {
+				conflicts = append(conflicts, other)
+			}
+		}
+
+		blocks[rdataType] = &schema.Schema{
+			Type:     schema.TypeList,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: blockSchema,
+			},
+			Optional:      true,
+			ConflictsWith: conflicts,
+		}
+	}
+
+	return blocks
+}
+
+// trafficDirectorRecordRData returns the typed rdata block set through get,
+// if any, along with its values.
+func trafficDirectorRecordRData(get func(string) (interface{}, bool)) (string, map[string]interface{}) {
+	for _, rdataType := range trafficDirectorRecordRDataTypes() {
+		v, ok := get(rdataType)
+		if !ok {
+			continue
+		}
+
+		blocks := v.([]interface{})
+		if len(blocks) == 0 || blocks[0] == nil {
+			continue
+		}
+
+		return rdataType, blocks[0].(map[string]interface{})
+	}
+
+	return "", nil
+}
+
+// trafficDirectorRecordMasterLine renders a typed rdata block as a canonical
+// master line.
+func trafficDirectorRecordMasterLine(rdataType string, m map[string]interface{}) (string, error) {
+	switch rdataType {
+	case "a", "aaaa":
+		ip := net.ParseIP(m["address"].(string))
+		if ip == nil {
+			return "", fmt.Errorf("invalid address: %s", m["address"])
+		}
+		return ip.String(), nil
+	case "cname":
+		return canonicalHostname(m["cname"].(string)), nil
+	case "mx":
+		return fmt.Sprintf("%d %s", m["preference"].(int), canonicalHostname(m["exchange"].(string))), nil
+	case "txt":
+		return quoteMasterLineString(m["txtdata"].(string)), nil
+	case "srv":
+		return fmt.Sprintf("%d %d %d %s", m["priority"].(int), m["weight"].(int), m["port"].(int), canonicalHostname(m["target"].(string))), nil
+	case "caa":
+		return fmt.Sprintf("%d %s %s", m["flags"].(int), m["tag"].(string), quoteMasterLineString(m["value"].(string))), nil
+	}
+
+	return "", fmt.Errorf("unknown rdata type: %s", rdataType)
+}
+
+// checkMasterLineRDataClass verifies that a master line is well formed for
+// the given record set rdata_class. Classes it doesn't know about are
+// accepted as is.
+func checkMasterLineRDataClass(rdataClass string, masterLine string) error {
+	tokens := masterLineTokens(masterLine)
+	values := make([]string, len(tokens))
+	for idx, token := range tokens {
+		values[idx] = token.Value
+	}
+
+	isInt := func(s string) bool {
+		_, err := strconv.ParseUint(s, 10, 16)
+		return err == nil
+	}
+	isHostname := func(s string) bool {
+		_, errs := validateHostname(s, "")
+		return len(errs) == 0
+	}
+
+	var valid bool
+	var expected string
+	switch strings.ToUpper(rdataClass) {
+	case "A":
+		expected = "an IPv4 address"
+		valid = len(values) == 1 && net.ParseIP(values[0]) != nil && net.ParseIP(values[0]).To4() != nil
+	case "AAAA":
+		expected = "an IPv6 address"
+		valid = len(values) == 1 && net.ParseIP(values[0]) != nil && net.ParseIP(values[0]).To4() == nil
+	case "CNAME":
+		expected = "a hostname"
+		valid = len(values) == 1 && isHostname(values[0])
+	case "MX":
+		expected = "{preference} {exchange}"
+		valid = len(values) == 2 && isInt(values[0]) && isHostname(values[1])
+	case "TXT":
+		expected = "some text"
+		valid = len(values) > 0
+	case "SRV":
+		expected = "{priority} {weight} {port} {target}"
+		valid = len(values) == 4 && isInt(values[0]) && isInt(values[1]) && isInt(values[2]) && isHostname(values[3])
+	case "CAA":
+		expected = "{flags} {tag} {value}"
+		valid = len(values) == 3 && isInt(values[0])
+	default:
+		return nil
+	}
+
+	if !valid {
+		return fmt.Errorf("master line %q is not valid for rdata_class %s, expected %s", masterLine, rdataClass, expected)
+	}
+
+	return nil
+}
+
+// masterLinesEquivalent reports whether two master lines describe the same
+// rdata, ignoring whitespace, hostname case and trailing dots, IP address
+// notation and quoting. The tokens are compared one by one: `"a b"` is a
+// single character string, `"a" "b"` is two.
+func masterLinesEquivalent(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	tokensA := masterLineTokens(a)
+	tokensB := masterLineTokens(b)
+
+	if len(tokensA) != len(tokensB) {
+		return false
+	}
+
+	for idx := range tokensA {
+		if tokensA[idx].canonical() != tokensB[idx].canonical() {
+			return false
+		}
+	}
+
+	return true
+}
+
+type masterLineToken struct {
+	Value  string
+	Quoted bool
+}
+
+func (t masterLineToken) canonical() string {
+	if t.Quoted {
+		return t.Value
+	}
+
+	if n, err := strconv.ParseUint(t.Value, 10, 64); err == nil {
+		return strconv.FormatUint(n, 10)
+	}
+
+	if ip := net.ParseIP(t.Value); ip != nil {
+		return ip.String()
+	}
+
+	return strings.TrimSuffix(strings.ToLower(t.Value), ".")
+}
+
+// masterLineTokens splits a master line on whitespace, keeping quoted
+// strings together.
+func masterLineTokens(line string) []masterLineToken {
+	tokens := make([]masterLineToken, 0)
+
+	var current strings.Builder
+	inToken, inQuotes, quoted, escaped := false, false, false, false
+
+	flush := func() {
+		if inToken {
+			tokens = append(tokens, masterLineToken{Value: current.String(), Quoted: quoted})
+		}
+		current.Reset()
+		inToken, quoted = false, false
+	}
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && inQuotes:
+			escaped = true
+		case r == '"':
+			inQuotes = !inQuotes
+			inToken, quoted = true, true
+		case !inQuotes && unicode.IsSpace(r):
+			flush()
+		default:
+			current.WriteRune(r)
+			inToken = true
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+func canonicalHostname(hostname string) string {
+	hostname = strings.ToLower(hostname)
+	if !strings.HasSuffix(hostname, ".") {
+		hostname += "."
+	}
+
+	return hostname
+}
+
+func quoteMasterLineString(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, `"`, `\"`, -1)
+
+	return `"` + s + `"`
+}
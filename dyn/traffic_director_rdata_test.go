@@ -0,0 +1,84 @@
+package dyn
+
+import (
+	"testing"
+)
+
+func TestTrafficDirectorRecordMasterLine(t *testing.T) {
+	cases := []struct {
+		rdataType string
+		rdata     map[string]interface{}
+		expected  string
+	}{
+		{"a", map[string]interface{}{"address": "192.0.2.1"}, "192.0.2.1"},
+		{"aaaa", map[string]interface{}{"address": "2001:0db8:0000:0000:0000:0000:0000:0001"}, "2001:db8::1"},
+		{"cname", map[string]interface{}{"cname": "Target.Example.com"}, "target.example.com."},
+		{"mx", map[string]interface{}{"preference": 10, "exchange": "mail.example.com."}, "10 mail.example.com."},
+		{"txt", map[string]interface{}{"txtdata": `v=spf1 "quoted" ~all`}, `"v=spf1 \"quoted\" ~all"`},
+		{"srv", map[string]interface{}{"priority": 10, "weight": 5, "port": 443, "target": "sip.example.com"}, "10 5 443 sip.example.com."},
+		{"caa", map[string]interface{}{"flags": 0, "tag": "issue", "value": "letsencrypt.org"}, `0 issue "letsencrypt.org"`},
+	}
+
+	for _, tc := range cases {
+		masterLine, err := trafficDirectorRecordMasterLine(tc.rdataType, tc.rdata)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tc.rdataType, err)
+		}
+		if masterLine != tc.expected {
+			t.Errorf("%s: expected master line %q, got %q", tc.rdataType, tc.expected, masterLine)
+		}
+		if err := checkMasterLineRDataClass(trafficDirectorRecordRDataClasses[tc.rdataType], masterLine); err != nil {
+			t.Errorf("%s: rendered master line doesn't check out: %s", tc.rdataType, err)
+		}
+	}
+}
+
+func TestMasterLinesEquivalent(t *testing.T) {
+	cases := []struct {
+		a, b       string
+		equivalent bool
+	}{
+		{"192.0.2.1", "192.0.2.1", true},
+		{"2001:db8::1", "2001:0DB8:0:0:0:0:0:1", true},
+		{"10 mail.example.com", "10  Mail.Example.com.", true},
+		{"010 mail.example.com.", "10 mail.example.com.", true},
+		{`"v=spf1" "~all"`, "v=spf1 ~all", true},
+		{`"v=spf1 ~all"`, "v=spf1 ~all", false},
+		{`"a" "b"`, `"a b"`, false},
+		{`0 issue "letsencrypt.org"`, "0 issue letsencrypt.org", true},
+		{"192.0.2.1", "192.0.2.2", false},
+		{"10 mail.example.com.", "20 mail.example.com.", false},
+		{`"Hello"`, `"hello"`, false},
+	}
+
+	for _, tc := range cases {
+		if masterLinesEquivalent(tc.a, tc.b) != tc.equivalent {
+			t.Errorf("expected equivalence of %q and %q to be %t", tc.a, tc.b, tc.equivalent)
+		}
+	}
+}
+
+func TestCheckMasterLineRDataClass(t *testing.T) {
+	cases := []struct {
+		rdataClass string
+		masterLine string
+		valid      bool
+	}{
+		{"A", "192.0.2.1", true},
+		{"A", "2001:db8::1", false},
+		{"AAAA", "192.0.2.1", false},
+		{"CNAME", "www.example.com.", true},
+		{"MX", "mail.example.com.", false},
+		{"MX", "10 mail.example.com.", true},
+		{"SRV", "10 5 443", false},
+		{"TXT", "", false},
+		{"NAPTR", "anything goes", true},
+	}
+
+	for _, tc := range cases {
+		err := checkMasterLineRDataClass(tc.rdataClass, tc.masterLine)
+		if (err == nil) != tc.valid {
+			t.Errorf("%s %q: expected valid to be %t, got error: %v", tc.rdataClass, tc.masterLine, tc.valid, err)
+		}
+	}
+}
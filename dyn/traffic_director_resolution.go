@@ -0,0 +1,144 @@
+package dyn
+
+import (
+	"strings"
+
+	"github.com/Shopify/go-dyn/pkg/dyn"
+)
+
+// trafficDirectorClient is where a simulated query comes from.
+type trafficDirectorClient struct {
+	Country  string
+	Region   string
+	Province string
+}
+
+// trafficDirectorAnswer is a record that may be served, along with the
+// probability that it is.
+type trafficDirectorAnswer struct {
+	Record      *dyn.TrafficDirectorRecord
+	Probability float64
+}
+
+// trafficDirectorResolution is the outcome of a simulated query. Ruleset,
+// ResponsePool and RecordSet are nil when nothing would be served.
+type trafficDirectorResolution struct {
+	Ruleset      *dyn.TrafficDirectorRuleset
+	ResponsePool *dyn.TrafficDirectorResponsePool
+	RecordSet    *dyn.TrafficDirectorRecordSet
+	Answers      []trafficDirectorAnswer
+}
+
+// trafficDirectorRulesetMatches reports whether a ruleset applies to the
+// client. Rulesets without geolocation criteria apply to everyone. Clients
+// only known by their country are in the region of that country.
+func trafficDirectorRulesetMatches(ruleset *dyn.TrafficDirectorRuleset, client trafficDirectorClient) bool {
+	geolocation := ruleset.Criteria.Geolocation
+	if ruleset.CriteriaType != "geoip" && len(geolocation.Regions)+len(geolocation.Countries)+len(geolocation.Provinces) == 0 {
+		return true
+	}
+
+	contains := func(codes []string, code string, aliases map[string]string) bool {
+		code = strings.ToUpper(code)
+		if alias, ok := aliases[code]; ok {
+			code = alias
+		}
+		if code == "" {
+			return false
+		}
+
+		for _, c := range codes {
+			c = strings.ToUpper(c)
+			if alias, ok := aliases[c]; ok {
+				c = alias
+			}
+			if c == code {
+				return true
+			}
+		}
+		return false
+	}
+
+	region := client.Region
+	if region == "" {
+		region = geolocationCountryRegion(client.Country)
+	}
+
+	return contains(geolocation.Regions, region, nil) ||
+		contains(geolocation.Countries, client.Country, geolocationCountryAliases) ||
+		contains(geolocation.Provinces, client.Province, nil)
+}
+
+// eligibleTrafficDirectorRecords returns the records of a record set that can
+// be served.
+func eligibleTrafficDirectorRecords(recordSet *dyn.TrafficDirectorRecordSet) []*dyn.TrafficDirectorRecord {
+	records := make([]*dyn.TrafficDirectorRecord, 0, len(recordSet.Records))
+	if !recordSet.Eligible {
+		return records
+	}
+
+	for _, record := range recordSet.Records {
+		if record.Eligible {
+			records = append(records, record)
+		}
+	}
+
+	return records
+}
+
+// simulateTrafficDirectorResolution works out which records a Traffic
+// Director would answer a client with, from its tree alone. Rulesets are
+// evaluated in order; within the first one that matches, response pools are
+// tried in order and, within a pool, its record sets are tried in chain order.
+// The first record set with eligible records answers, each record being
+// picked in proportion to its weight. When all the pools of a matching
+// ruleset are ineligible, the next matching ruleset is tried.
+func simulateTrafficDirectorResolution(td *dyn.TrafficDirector, client trafficDirectorClient) *trafficDirectorResolution {
+	for _, ruleset := range sortedTrafficDirectorRulesets(td) {
+		if !trafficDirectorRulesetMatches(ruleset, client) {
+			continue
+		}
+
+		for _, responsePool := range ruleset.ResponsePools {
+			if !responsePool.Eligible {
+				continue
+			}
+
+			for _, recordSet := range responsePool.RecordSets {
+				records := eligibleTrafficDirectorRecords(recordSet)
+				if len(records) == 0 {
+					continue
+				}
+
+				weights := make([]int, len(records))
+				total := 0
+				for idx, record := range records {
+					weights[idx] = record.Weight
+					if weights[idx] < 1 {
+						weights[idx] = 1
+					}
+					total += weights[idx]
+				}
+
+				resolution := &trafficDirectorResolution{
+					Ruleset:      ruleset,
+					ResponsePool: responsePool,
+					RecordSet:    recordSet,
+					Answers:      make([]trafficDirectorAnswer, len(records)),
+				}
+				for idx, record := range records {
+					resolution.Answers[idx] = trafficDirectorAnswer{
+						Record:      record,
+						Probability: float64(weights[idx]) / float64(total),
+					}
+				}
+
+				return resolution
+			}
+		}
+	}
+
+	return &trafficDirectorResolution{
+		Answers: make([]trafficDirectorAnswer, 0),
+	}
+}
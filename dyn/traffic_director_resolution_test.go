@@ -0,0 +1,125 @@
+package dyn
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Shopify/go-dyn/pkg/dyn"
+)
+
+// testTrafficDirectorResolutionFixture is a service with a European ruleset
+// failing over from a drained pool to a weighted one, and a catch-all.
+func testTrafficDirectorResolutionFixture() *dyn.TrafficDirector {
+	drained := &dyn.TrafficDirectorResponsePool{
+		ResponsePoolID: "rp-drained",
+		Label:          "drained",
+		Eligible:       false,
+		RecordSets: []*dyn.TrafficDirectorRecordSet{
+			{RecordSetID: "rs-drained", Eligible: true, Records: []*dyn.TrafficDirectorRecord{
+				{RecordID: "r-drained", MasterLine: "192.0.2.9", Weight: 1, Eligible: true},
+			}},
+		},
+	}
+	eu := &dyn.TrafficDirectorResponsePool{
+		ResponsePoolID: "rp-eu",
+		Label:          "eu",
+		Eligible:       true,
+		RecordSets: []*dyn.TrafficDirectorRecordSet{
+			{RecordSetID: "rs-eu-down", Eligible: true, Records: []*dyn.TrafficDirectorRecord{
+				{RecordID: "r-ineligible", MasterLine: "192.0.2.8", Weight: 1, Eligible: false},
+			}},
+			{RecordSetID: "rs-eu", Eligible: true, Records: []*dyn.TrafficDirectorRecord{
+				{RecordID: "r-eu-1", MasterLine: "192.0.2.1", Weight: 3, Eligible: true},
+				{RecordID: "r-eu-2", MasterLine: "192.0.2.2", Weight: 1, Eligible: true},
+				{RecordID: "r-eu-3", MasterLine: "192.0.2.3", Weight: 5, Eligible: false},
+			}},
+		},
+	}
+	global := &dyn.TrafficDirectorResponsePool{
+		ResponsePoolID: "rp-global",
+		Label:          "global",
+		Eligible:       true,
+		RecordSets: []*dyn.TrafficDirectorRecordSet{
+			{RecordSetID: "rs-global", Eligible: true, Records: []*dyn.TrafficDirectorRecord{
+				{RecordID: "r-global", MasterLine: "198.51.100.1", Weight: 1, Eligible: true},
+			}},
+		},
+	}
+
+	europe := &dyn.TrafficDirectorRuleset{
+		RulesetID:     "europe",
+		CriteriaType:  "geoip",
+		Ordering:      1,
+		ResponsePools: []*dyn.TrafficDirectorResponsePool{drained, eu},
+	}
+	europe.Criteria.Geolocation.Countries = []string{"FR", "GB"}
+	europe.Criteria.Geolocation.Regions = []string{"16"}
+
+	quebec := &dyn.TrafficDirectorRuleset{
+		RulesetID:     "quebec",
+		CriteriaType:  "geoip",
+		Ordering:      0,
+		ResponsePools: []*dyn.TrafficDirectorResponsePool{drained},
+	}
+	quebec.Criteria.Geolocation.Provinces = []string{"QC"}
+
+	always := &dyn.TrafficDirectorRuleset{
+		RulesetID:     "always",
+		CriteriaType:  "always",
+		Ordering:      2,
+		ResponsePools: []*dyn.TrafficDirectorResponsePool{global},
+	}
+
+	return &dyn.TrafficDirector{
+		ServiceID:     "td",
+		Rulesets:      []*dyn.TrafficDirectorRuleset{always, europe, quebec},
+		ResponsePools: []*dyn.TrafficDirectorResponsePool{drained, eu, global},
+	}
+}
+
+func TestSimulateTrafficDirectorResolution(t *testing.T) {
+	cases := []struct {
+		name          string
+		client        trafficDirectorClient
+		ruleset       string
+		recordSet     string
+		probabilities map[string]float64
+	}{
+		{"country", trafficDirectorClient{Country: "FR"}, "europe", "rs-eu", map[string]float64{"r-eu-1": 0.75, "r-eu-2": 0.25}},
+		{"country alias", trafficDirectorClient{Country: "uk"}, "europe", "rs-eu", map[string]float64{"r-eu-1": 0.75, "r-eu-2": 0.25}},
+		{"region", trafficDirectorClient{Country: "DE", Region: "16"}, "europe", "rs-eu", map[string]float64{"r-eu-1": 0.75, "r-eu-2": 0.25}},
+		{"region of country", trafficDirectorClient{Country: "de"}, "europe", "rs-eu", map[string]float64{"r-eu-1": 0.75, "r-eu-2": 0.25}},
+		{"country outside region", trafficDirectorClient{Country: "ES"}, "always", "rs-global", map[string]float64{"r-global": 1}},
+		{"ineligible ruleset falls through", trafficDirectorClient{Country: "CA", Province: "QC"}, "always", "rs-global", map[string]float64{"r-global": 1}},
+		{"no match", trafficDirectorClient{Country: "JP"}, "always", "rs-global", map[string]float64{"r-global": 1}},
+	}
+
+	for _, tc := range cases {
+		resolution := simulateTrafficDirectorResolution(testTrafficDirectorResolutionFixture(), tc.client)
+		if resolution.RecordSet == nil {
+			t.Errorf("%s: expected an answer", tc.name)
+			continue
+		}
+		if resolution.Ruleset.RulesetID != tc.ruleset || resolution.RecordSet.RecordSetID != tc.recordSet {
+			t.Errorf("%s: expected ruleset %s and record set %s, got %s and %s", tc.name, tc.ruleset, tc.recordSet, resolution.Ruleset.RulesetID, resolution.RecordSet.RecordSetID)
+		}
+		if len(resolution.Answers) != len(tc.probabilities) {
+			t.Errorf("%s: expected %d answers, got %d", tc.name, len(tc.probabilities), len(resolution.Answers))
+		}
+		for _, answer := range resolution.Answers {
+			if math.Abs(answer.Probability-tc.probabilities[answer.Record.RecordID]) > 1e-9 {
+				t.Errorf("%s: expected %s probability to be %f, got %f", tc.name, answer.Record.RecordID, tc.probabilities[answer.Record.RecordID], answer.Probability)
+			}
+		}
+	}
+}
+
+func TestSimulateTrafficDirectorResolutionNoAnswer(t *testing.T) {
+	td := testTrafficDirectorResolutionFixture()
+	td.Rulesets = td.Rulesets[1:]
+
+	resolution := simulateTrafficDirectorResolution(td, trafficDirectorClient{Country: "JP"})
+	if resolution.RecordSet != nil || len(resolution.Answers) != 0 {
+		t.Errorf("expected no answer, got %+v", resolution)
+	}
+}
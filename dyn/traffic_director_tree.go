@@ -0,0 +1,388 @@
+package dyn
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/Shopify/go-dyn/pkg/dyn"
+)
+
+// trafficDirectorTree is the whole content of a Traffic Director service:
+// its rulesets, in order, and the response pools they point at.
+type trafficDirectorTree struct {
+	Rulesets      []*trafficDirectorTreeRuleset
+	ResponsePools []*trafficDirectorTreeResponsePool
+}
+
+type trafficDirectorTreeRuleset struct {
+	ID            string
+	Label         string
+	ResponsePools []string
+	Geolocation   map[string][]string
+}
+
+type trafficDirectorTreeResponsePool struct {
+	ID         string
+	Label      string
+	RecordSets []*trafficDirectorTreeRecordSet
+}
+
+type trafficDirectorTreeRecordSet struct {
+	ID         string
+	Label      string
+	RDataClass string
+	TTL        int
+	MonitorID  string
+	Records    []*trafficDirectorTreeRecord
+}
+
+type trafficDirectorTreeRecord struct {
+	ID         string
+	Label      string
+	MasterLine string
+	Weight     int
+	Eligible   bool
+	Automation string
+}
+
+// newTrafficDirectorTree builds the tree of an existing Traffic Director.
+// Rulesets are in the order Dyn evaluates them, response pools are sorted by
+// label since Dyn doesn't keep them in any particular order.
+func newTrafficDirectorTree(td *dyn.TrafficDirector) *trafficDirectorTree {
+	tree := &trafficDirectorTree{}
+
+	for _, tdrs := range sortedTrafficDirectorRulesets(td) {
+		ruleset := &trafficDirectorTreeRuleset{
+			ID:            tdrs.RulesetID,
+			Label:         tdrs.Label,
+			ResponsePools: make([]string, len(tdrs.ResponsePools)),
+			Geolocation:   make(map[string][]string),
+		}
+		for idx, tdrp := range tdrs.ResponsePools {
+			ruleset.ResponsePools[idx] = tdrp.Label
+		}
+		for k, v := range map[string][]string{
+			"region":   tdrs.Criteria.Geolocation.Regions,
+			"country":  tdrs.Criteria.Geolocation.Countries,
+			"province": tdrs.Criteria.Geolocation.Provinces,
+		} {
+			if len(v) > 0 {
+				ruleset.Geolocation[k] = v
+			}
+		}
+		tree.Rulesets = append(tree.Rulesets, ruleset)
+	}
+
+	for _, tdrp := range td.ResponsePools {
+		responsePool := &trafficDirectorTreeResponsePool{
+			ID:    tdrp.ResponsePoolID,
+			Label: tdrp.Label,
+		}
+		for _, tdrs := range tdrp.RecordSets {
+			ttl, _ := strconv.Atoi(tdrs.TTL)
+			recordSet := &trafficDirectorTreeRecordSet{
+				ID:         tdrs.RecordSetID,
+				Label:      tdrs.Label,
+				RDataClass: tdrs.RDataClass,
+				TTL:        ttl,
+				MonitorID:  tdrs.MonitorID,
+			}
+			for _, tdr := range tdrs.Records {
+				recordSet.Records = append(recordSet.Records, &trafficDirectorTreeRecord{
+					ID:         tdr.RecordID,
+					Label:      tdr.Label,
+					MasterLine: tdr.MasterLine,
+					Weight:     tdr.Weight,
+					Eligible:   tdr.Eligible,
+					Automation: tdr.Automation,
+				})
+			}
+			responsePool.RecordSets = append(responsePool.RecordSets, recordSet)
+		}
+		tree.ResponsePools = append(tree.ResponsePools, responsePool)
+	}
+	sort.SliceStable(tree.ResponsePools, func(i, j int) bool {
+		return tree.ResponsePools[i].Label < tree.ResponsePools[j].Label
+	})
+
+	return tree
+}
+
+func (t *trafficDirectorTree) responsePool(label string) *trafficDirectorTreeResponsePool {
+	for _, responsePool := range t.ResponsePools {
+		if responsePool.Label == label {
+			return responsePool
+		}
+	}
+
+	return nil
+}
+
+func (t *trafficDirectorTree) ruleset(label string) *trafficDirectorTreeRuleset {
+	for _, ruleset := range t.Rulesets {
+		if ruleset.Label == label {
+			return ruleset
+		}
+	}
+
+	return nil
+}
+
+func (rp *trafficDirectorTreeResponsePool) recordSet(label string) *trafficDirectorTreeRecordSet {
+	for _, recordSet := range rp.RecordSets {
+		if recordSet.Label == label {
+			return recordSet
+		}
+	}
+
+	return nil
+}
+
+// record finds a record by label or, for unlabeled records, by master line.
+func (rs *trafficDirectorTreeRecordSet) record(r *trafficDirectorTreeRecord) *trafficDirectorTreeRecord {
+	for _, record := range rs.Records {
+		if r.Label != "" && record.Label == r.Label {
+			return record
+		}
+		if r.Label == "" && record.Label == "" && masterLinesEquivalent(record.MasterLine, r.MasterLine) {
+			return record
+		}
+	}
+
+	return nil
+}
+
+// adoptIDs copies the IDs of the live objects onto the matching objects of
+// the tree, matching them by label, so that updating the service with it
+// modifies them in place instead of replacing them.
+func (t *trafficDirectorTree) adoptIDs(live *trafficDirectorTree) {
+	for _, ruleset := range t.Rulesets {
+		ruleset.ID = ""
+		if liveRuleset := live.ruleset(ruleset.Label); liveRuleset != nil {
+			ruleset.ID = liveRuleset.ID
+		}
+	}
+
+	for _, responsePool := range t.ResponsePools {
+		responsePool.ID = ""
+		liveResponsePool := live.responsePool(responsePool.Label)
+		if liveResponsePool != nil {
+			responsePool.ID = liveResponsePool.ID
+		}
+
+		for _, recordSet := range responsePool.RecordSets {
+			recordSet.ID = ""
+			var liveRecordSet *trafficDirectorTreeRecordSet
+			if liveResponsePool != nil {
+				liveRecordSet = liveResponsePool.recordSet(recordSet.Label)
+			}
+			if liveRecordSet != nil {
+				recordSet.ID = liveRecordSet.ID
+			}
+
+			for _, record := range recordSet.Records {
+				record.ID = ""
+				if liveRecordSet == nil {
+					continue
+				}
+				if liveRecord := liveRecordSet.record(record); liveRecord != nil {
+					record.ID = liveRecord.ID
+				}
+			}
+		}
+	}
+}
+
+// trafficDirectorTreeChanges lists what differs between the desired and the
+// live tree. An empty list means the service doesn't need to be updated.
+func trafficDirectorTreeChanges(desired, live *trafficDirectorTree) []string {
+	changes := make([]string, 0)
+
+	for idx, ruleset := range desired.Rulesets {
+		liveRuleset := live.ruleset(ruleset.Label)
+		switch {
+		case liveRuleset == nil:
+			changes = append(changes, fmt.Sprintf("create ruleset %q", ruleset.Label))
+			continue
+		case indexOfTreeRuleset(live.Rulesets, liveRuleset) != idx:
+			changes = append(changes, fmt.Sprintf("move ruleset %q to position %d", ruleset.Label, idx))
+		}
+		if !reflect.DeepEqual(ruleset.ResponsePools, liveRuleset.ResponsePools) {
+			changes = append(changes, fmt.Sprintf("update ruleset %q response pools", ruleset.Label))
+		}
+		if !geolocationsEqual(ruleset.Geolocation, liveRuleset.Geolocation) {
+			changes = append(changes, fmt.Sprintf("update ruleset %q geolocation", ruleset.Label))
+		}
+	}
+	for _, liveRuleset := range live.Rulesets {
+		if desired.ruleset(liveRuleset.Label) == nil {
+			changes = append(changes, fmt.Sprintf("delete ruleset %q", liveRuleset.Label))
+		}
+	}
+
+	for _, responsePool := range desired.ResponsePools {
+		liveResponsePool := live.responsePool(responsePool.Label)
+		if liveResponsePool == nil {
+			changes = append(changes, fmt.Sprintf("create response pool %q", responsePool.Label))
+			continue
+		}
+		changes = append(changes, trafficDirectorResponsePoolChanges(responsePool, liveResponsePool)...)
+	}
+	for _, liveResponsePool := range live.ResponsePools {
+		if desired.responsePool(liveResponsePool.Label) == nil {
+			changes = append(changes, fmt.Sprintf("delete response pool %q", liveResponsePool.Label))
+		}
+	}
+
+	return changes
+}
+
+func trafficDirectorResponsePoolChanges(desired, live *trafficDirectorTreeResponsePool) []string {
+	changes := make([]string, 0)
+
+	if len(desired.RecordSets) != len(live.RecordSets) {
+		changes = append(changes, fmt.Sprintf("update response pool %q record sets", desired.Label))
+	}
+
+	for idx, recordSet := range desired.RecordSets {
+		name := fmt.Sprintf("record set %q of response pool %q", recordSet.Label, desired.Label)
+		liveRecordSet := live.recordSet(recordSet.Label)
+		if liveRecordSet == nil {
+			changes = append(changes, "create "+name)
+			continue
+		}
+		if idx >= len(live.RecordSets) || live.RecordSets[idx] != liveRecordSet {
+			changes = append(changes, fmt.Sprintf("move %s to position %d", name, idx))
+		}
+		if recordSet.RDataClass != liveRecordSet.RDataClass || recordSet.TTL != liveRecordSet.TTL || recordSet.MonitorID != liveRecordSet.MonitorID {
+			changes = append(changes, "update "+name)
+		}
+
+		if len(recordSet.Records) != len(liveRecordSet.Records) {
+			changes = append(changes, "update records of "+name)
+		}
+		for _, record := range recordSet.Records {
+			liveRecord := liveRecordSet.record(record)
+			switch {
+			case liveRecord == nil:
+				changes = append(changes, fmt.Sprintf("create record %q in %s", record.MasterLine, name))
+			case !masterLinesEquivalent(record.MasterLine, liveRecord.MasterLine) ||
+				record.Weight != liveRecord.Weight ||
+				record.Eligible != liveRecord.Eligible ||
+				record.Automation != liveRecord.Automation:
+				changes = append(changes, fmt.Sprintf("update record %q in %s", record.MasterLine, name))
+			}
+		}
+	}
+
+	return changes
+}
+
+func indexOfTreeRuleset(rulesets []*trafficDirectorTreeRuleset, ruleset *trafficDirectorTreeRuleset) int {
+	for idx, r := range rulesets {
+		if r == ruleset {
+			return idx
+		}
+	}
+
+	return -1
+}
+
+func geolocationsEqual(a, b map[string][]string) bool {
+	normalize := func(m map[string][]string) map[string][]string {
+		n := make(map[string][]string)
+		for k, v := range m {
+			if len(v) == 0 {
+				continue
+			}
+			sorted := append([]string(nil), v...)
+			sort.Strings(sorted)
+			n[k] = sorted
+		}
+		return n
+	}
+
+	return reflect.DeepEqual(normalize(a), normalize(b))
+}
+
+// rulesetRequests returns the rulesets of the tree in the form expected by
+// TrafficDirectorCURequest.Rulesets. Each response pool is fully described
+// the first time a ruleset points at it; later rulesets only reference it,
+// by ID when it already exists or by label when it's created by the same
+// request.
+func (t *trafficDirectorTree) rulesetRequests() []dyn.TrafficDirectorRulesetCURequest {
+	described := make(map[string]bool)
+	requests := make([]dyn.TrafficDirectorRulesetCURequest, len(t.Rulesets))
+
+	for idx, ruleset := range t.Rulesets {
+		req := dyn.TrafficDirectorRulesetCURequest{
+			RulesetID: ruleset.ID,
+			Label:     ruleset.Label,
+			Ordering:  idx,
+		}
+		req.SetGeolocation(ruleset.Geolocation)
+
+		for _, label := range ruleset.ResponsePools {
+			responsePool := t.responsePool(label)
+			if responsePool == nil {
+				continue
+			}
+
+			if described[label] {
+				req.AddResponsePool(dyn.TrafficDirectorResponsePoolCURequest{
+					ResponsePoolID: responsePool.ID,
+					Label:          responsePool.Label,
+				})
+				continue
+			}
+			described[label] = true
+
+			req.AddResponsePool(responsePool.request())
+		}
+
+		requests[idx] = req
+	}
+
+	return requests
+}
+
+func (rp *trafficDirectorTreeResponsePool) request() dyn.TrafficDirectorResponsePoolCURequest {
+	chain := dyn.TrafficDirectorRecordSetChainCURequest{
+		Label:      rp.Label,
+		RecordSets: make([]dyn.TrafficDirectorRecordSetCURequest, len(rp.RecordSets)),
+	}
+
+	for idx, recordSet := range rp.RecordSets {
+		rsReq := dyn.TrafficDirectorRecordSetCURequest{
+			RecordSetID: recordSet.ID,
+			Label:       recordSet.Label,
+			RDataClass:  recordSet.RDataClass,
+			MonitorID:   recordSet.MonitorID,
+			Records:     make([]dyn.TrafficDirectorRecordCURequest, len(recordSet.Records)),
+		}
+		if recordSet.TTL > 0 {
+			rsReq.TTL = strconv.Itoa(recordSet.TTL)
+		}
+
+		for ridx, record := range recordSet.Records {
+			rsReq.Records[ridx] = dyn.TrafficDirectorRecordCURequest{
+				RecordID:   record.ID,
+				Label:      record.Label,
+				MasterLine: record.MasterLine,
+				Weight:     record.Weight,
+				Eligible:   strconv.FormatBool(record.Eligible),
+				Automation: record.Automation,
+			}
+		}
+
+		chain.RecordSets[idx] = rsReq
+	}
+
+	return dyn.TrafficDirectorResponsePoolCURequest{
+		ResponsePoolID:  rp.ID,
+		Label:           rp.Label,
+		RecordSetChains: []dyn.TrafficDirectorRecordSetChainCURequest{chain},
+	}
+}
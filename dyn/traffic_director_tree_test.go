@@ -0,0 +1,135 @@
+package dyn
+
+import (
+	"testing"
+)
+
+func testTrafficDirectorTree() *trafficDirectorTree {
+	return &trafficDirectorTree{
+		Rulesets: []*trafficDirectorTreeRuleset{
+			{Label: "eu", ResponsePools: []string{"primary", "backup"}, Geolocation: map[string][]string{"region": {"15", "16"}}},
+			{Label: "default", ResponsePools: []string{"backup"}},
+		},
+		ResponsePools: []*trafficDirectorTreeResponsePool{
+			{
+				Label: "primary",
+				RecordSets: []*trafficDirectorTreeRecordSet{
+					{
+						Label:      "web",
+						RDataClass: "A",
+						Records: []*trafficDirectorTreeRecord{
+							{MasterLine: "192.0.2.1", Weight: 1, Eligible: true, Automation: "auto"},
+							{MasterLine: "192.0.2.2", Weight: 1, Eligible: true, Automation: "auto"},
+						},
+					},
+				},
+			},
+			{
+				Label: "backup",
+				RecordSets: []*trafficDirectorTreeRecordSet{
+					{
+						Label:      "web",
+						RDataClass: "A",
+						Records: []*trafficDirectorTreeRecord{
+							{Label: "fallback", MasterLine: "198.51.100.1", Weight: 1, Eligible: true, Automation: "auto"},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestTrafficDirectorTreeChanges(t *testing.T) {
+	live := testTrafficDirectorTree()
+
+	desired := testTrafficDirectorTree()
+	desired.Rulesets[0].Geolocation = map[string][]string{"region": {"16", "15"}}
+	desired.ResponsePools[0].RecordSets[0].Records[0].MasterLine = " 192.0.2.1 "
+	if changes := trafficDirectorTreeChanges(desired, live); len(changes) != 0 {
+		t.Errorf("expected no changes, got %v", changes)
+	}
+
+	desired = testTrafficDirectorTree()
+	desired.Rulesets[0], desired.Rulesets[1] = desired.Rulesets[1], desired.Rulesets[0]
+	desired.ResponsePools[1].RecordSets[0].Records[0].Weight = 5
+	desired.ResponsePools[0].RecordSets[0].Records = desired.ResponsePools[0].RecordSets[0].Records[:1]
+	changes := trafficDirectorTreeChanges(desired, live)
+	if len(changes) != 4 {
+		t.Errorf("expected 4 changes, got %v", changes)
+	}
+}
+
+func TestTrafficDirectorTreeAdoptIDs(t *testing.T) {
+	live := testTrafficDirectorTree()
+	live.Rulesets[0].ID = "rs-eu"
+	live.ResponsePools[1].ID = "rp-backup"
+	live.ResponsePools[1].RecordSets[0].ID = "rs-web"
+	live.ResponsePools[1].RecordSets[0].Records[0].ID = "r-fallback"
+	live.ResponsePools[0].RecordSets[0].Records[1].ID = "r-2"
+
+	desired := testTrafficDirectorTree()
+	desired.ResponsePools[1].RecordSets[0].Records[0].MasterLine = "198.51.100.2"
+	desired.adoptIDs(live)
+
+	if desired.Rulesets[0].ID != "rs-eu" || desired.Rulesets[1].ID != "" {
+		t.Errorf("unexpected ruleset IDs: %q, %q", desired.Rulesets[0].ID, desired.Rulesets[1].ID)
+	}
+	if desired.ResponsePools[1].ID != "rp-backup" || desired.ResponsePools[1].RecordSets[0].ID != "rs-web" {
+		t.Errorf("unexpected response pool IDs: %q, %q", desired.ResponsePools[1].ID, desired.ResponsePools[1].RecordSets[0].ID)
+	}
+	if desired.ResponsePools[1].RecordSets[0].Records[0].ID != "r-fallback" {
+		t.Errorf("labeled record should keep its ID when its master line changes")
+	}
+	if desired.ResponsePools[0].RecordSets[0].Records[1].ID != "r-2" {
+		t.Errorf("unlabeled record should be matched by master line")
+	}
+}
+
+func TestTrafficDirectorTreeRulesetRequests(t *testing.T) {
+	requests := testTrafficDirectorTree().rulesetRequests()
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 rulesets, got %d", len(requests))
+	}
+
+	if requests[1].Ordering != 1 {
+		t.Errorf("expected second ruleset ordering to be 1, got %d", requests[1].Ordering)
+	}
+
+	backup := requests[0].ResponsePools[1].TrafficDirectorResponsePoolCURequest
+	if len(backup.RecordSetChains) != 1 || len(backup.RecordSetChains[0].RecordSets[0].Records) != 1 {
+		t.Errorf("expected backup to be fully described the first time it's referenced, got %#v", backup)
+	}
+
+	reference := requests[1].ResponsePools[0].TrafficDirectorResponsePoolCURequest
+	if reference.Label != "backup" || len(reference.RecordSetChains) != 0 {
+		t.Errorf("expected backup to only be referenced the second time, got %#v", reference)
+	}
+}
+
+func TestTrafficDirectorTreeIDMapping(t *testing.T) {
+	source := testTrafficDirectorTree()
+	source.Rulesets[0].ID = "rs-eu"
+	source.ResponsePools[0].ID = "rp-primary"
+	source.ResponsePools[0].RecordSets[0].Records[1].ID = "r-2"
+
+	clone := testTrafficDirectorTree()
+	clone.Rulesets[0].ID = "clone-rs-eu"
+	clone.ResponsePools[0].ID = "clone-rp-primary"
+	clone.ResponsePools[0].RecordSets[0].Records[1].ID = "clone-r-2"
+
+	mapping := trafficDirectorTreeIDMapping(source, clone)
+	expected := map[string]string{
+		"rs-eu":      "clone-rs-eu",
+		"rp-primary": "clone-rp-primary",
+		"r-2":        "clone-r-2",
+	}
+	if len(mapping) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, mapping)
+	}
+	for k, v := range expected {
+		if mapping[k] != v {
+			t.Errorf("expected %s to map to %s, got %q", k, v, mapping[k])
+		}
+	}
+}
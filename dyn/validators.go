@@ -0,0 +1,167 @@
+package dyn
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// validateStringInSlice returns a SchemaValidateFunc which checks that the
+// value is one of the valid strings.
+func validateStringInSlice(valid []string) schema.SchemaValidateFunc {
+	return func(i interface{}, k string) (s []string, es []error) {
+		v, ok := i.(string)
+		if !ok {
+			es = append(es, fmt.Errorf("expected type of %s to be string", k))
+			return
+		}
+
+		for _, str := range valid {
+			if v == str {
+				return
+			}
+		}
+
+		es = append(es, fmt.Errorf("expected %s to be one of %v, got %s", k, valid, v))
+		return
+	}
+}
+
+// validateIntAtLeast returns a SchemaValidateFunc which checks that the
+// value is at least min.
+func validateIntAtLeast(min int) schema.SchemaValidateFunc {
+	return func(i interface{}, k string) (s []string, es []error) {
+		v, ok := i.(int)
+		if !ok {
+			es = append(es, fmt.Errorf("expected type of %s to be int", k))
+			return
+		}
+
+		if v < min {
+			es = append(es, fmt.Errorf("expected %s to be at least (%d), got %d", k, min, v))
+			return
+		}
+
+		return
+	}
+}
+
+// validateIntBetween returns a SchemaValidateFunc which checks that the
+// value is between min and max, inclusive.
+func validateIntBetween(min, max int) schema.SchemaValidateFunc {
+	return func(i interface{}, k string) (s []string, es []error) {
+		v, ok := i.(int)
+		if !ok {
+			es = append(es, fmt.Errorf("expected type of %s to be int", k))
+			return
+		}
+
+		if v < min || v > max {
+			es = append(es, fmt.Errorf("expected %s to be in the range (%d - %d), got %d", k, min, max, v))
+			return
+		}
+
+		return
+	}
+}
+
+// validateIPv4Address is a SchemaValidateFunc which checks that the value is
+// an IPv4 address.
+func validateIPv4Address(i interface{}, k string) (s []string, es []error) {
+	v, ok := i.(string)
+	if !ok {
+		es = append(es, fmt.Errorf("expected type of %s to be string", k))
+		return
+	}
+
+	ip := net.ParseIP(v)
+	if ip == nil || ip.To4() == nil {
+		es = append(es, fmt.Errorf("expected %s to contain a valid IPv4 address, got: %s", k, v))
+	}
+
+	return
+}
+
+// validateIPv6Address is a SchemaValidateFunc which checks that the value is
+// an IPv6 address.
+func validateIPv6Address(i interface{}, k string) (s []string, es []error) {
+	v, ok := i.(string)
+	if !ok {
+		es = append(es, fmt.Errorf("expected type of %s to be string", k))
+		return
+	}
+
+	ip := net.ParseIP(v)
+	if ip == nil || ip.To4() != nil {
+		es = append(es, fmt.Errorf("expected %s to contain a valid IPv6 address, got: %s", k, v))
+	}
+
+	return
+}
+
+// validateHostname is a SchemaValidateFunc which checks that the value looks
+// like a DNS name, with or without the trailing dot.
+func validateHostname(i interface{}, k string) (s []string, es []error) {
+	v, ok := i.(string)
+	if !ok {
+		es = append(es, fmt.Errorf("expected type of %s to be string", k))
+		return
+	}
+
+	name := strings.TrimSuffix(v, ".")
+	if name == "" || len(name) > 253 {
+		es = append(es, fmt.Errorf("expected %s to be a valid hostname, got: %q", k, v))
+		return
+	}
+
+	for _, label := range strings.Split(name, ".") {
+		if label == "" || len(label) > 63 || strings.ContainsAny(label, " \t\"\\") {
+			es = append(es, fmt.Errorf("expected %s to be a valid hostname, got: %q", k, v))
+			return
+		}
+	}
+
+	return
+}
+
+// validateDuration is a SchemaValidateFunc which checks that the value can be
+// parsed by time.ParseDuration and is positive.
+func validateDuration(i interface{}, k string) (s []string, es []error) {
+	v, ok := i.(string)
+	if !ok {
+		es = append(es, fmt.Errorf("expected type of %s to be string", k))
+		return
+	}
+
+	duration, err := time.ParseDuration(v)
+	if err != nil {
+		es = append(es, fmt.Errorf("expected %s to be a duration, got %s: %w", k, v, err))
+		return
+	}
+
+	if duration <= 0 {
+		es = append(es, fmt.Errorf("expected %s to be a positive duration, got %s", k, v))
+	}
+
+	return
+}
+
+// validateRegexp is a SchemaValidateFunc which checks that the value is a
+// valid regular expression.
+func validateRegexp(i interface{}, k string) (s []string, es []error) {
+	v, ok := i.(string)
+	if !ok {
+		es = append(es, fmt.Errorf("expected type of %s to be string", k))
+		return
+	}
+
+	if _, err := regexp.Compile(v); err != nil {
+		es = append(es, fmt.Errorf("expected %s to be a regular expression, got %s: %w", k, v, err))
+	}
+
+	return
+}
@@ -2,7 +2,7 @@ package main
 
 import (
 	"github.com/Shopify/terraform-provider-dyn/dyn"
-	"github.com/hashicorp/terraform/plugin"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
 )
 
 func main() {
@@ -0,0 +1,3 @@
+module github.com/Shopify/go-dyn
+
+go 1.25.8
@@ -0,0 +1,348 @@
+package dyn
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/Shopify/go-dyn/pkg/version"
+)
+
+// BaseURL is the Dyn API base URL.
+const BaseURL = "https://api.dynect.net/"
+
+// JobPollInterval is how long to wait between checks of a job that an API
+// call was redirected to because it took too long to complete.
+var JobPollInterval = 5 * time.Second
+
+// Client is used to manage a Dyn API session.
+type Client struct {
+	BaseURL   *url.URL
+	UserAgent string
+	Logger    *log.Logger
+
+	// OnWarning, when set, is called with the WARN messages of the calls
+	// that succeed. Those of the calls that fail are part of their Error.
+	OnWarning func(method, resource string, m Message)
+
+	httpClient *http.Client
+	token      string
+	ctx        context.Context
+}
+
+// NewClient creates a new API client.
+func NewClient() *Client {
+	baseURL, _ := url.Parse(BaseURL)
+
+	c := &Client{
+		BaseURL:   baseURL,
+		UserAgent: fmt.Sprintf("go-dyn/%v", version.VERSION),
+
+		// Calls that take too long are redirected to their job, which
+		// needs to be polled rather than followed.
+		httpClient: &http.Client{
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+
+	return c
+}
+
+// WithContext returns a shallow copy of the client bound to ctx. Requests made
+// with it, along with their retries and job polling, are abandoned as soon as
+// ctx is done.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	c2 := *c
+	c2.ctx = ctx
+
+	return &c2
+}
+
+// Context returns the context the client is bound to.
+func (c *Client) Context() context.Context {
+	if c.ctx != nil {
+		return c.ctx
+	}
+
+	return context.Background()
+}
+
+func (c *Client) delete(resource string, requestData interface{}) error {
+	return c.perform(http.MethodDelete, resource, nil, requestData, nil)
+}
+
+func (c *Client) get(resource string, params url.Values, responseData interface{}) error {
+	return c.perform(http.MethodGet, resource, params, nil, responseData)
+}
+
+func (c *Client) post(resource string, requestData interface{}, responseData interface{}) error {
+	return c.perform(http.MethodPost, resource, nil, requestData, responseData)
+}
+
+func (c *Client) put(resource string, requestData interface{}, responseData interface{}) error {
+	return c.perform(http.MethodPut, resource, nil, requestData, responseData)
+}
+
+// perform does the actual work for the request/response cycle.
+func (c *Client) perform(method, resource string, params url.Values, requestData interface{}, responseData interface{}) error {
+	resp, err := c.do(c.Context(), method, resource, params, requestData)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if responseData == nil {
+		var h responseHeader
+
+		if err := c.decodeJSON(resp.Body, &h); err == nil {
+			c.warn(method, resource, &h)
+		}
+
+		return nil
+	}
+
+	if err := c.decodeJSON(resp.Body, responseData); err != nil {
+		return err
+	}
+
+	if r, ok := responseData.(interface{ header() *responseHeader }); ok {
+		c.warn(method, resource, r.header())
+	}
+
+	return nil
+}
+
+// warn hands the WARN messages of a successful call to OnWarning.
+func (c *Client) warn(method, resource string, h *responseHeader) {
+	if c.OnWarning == nil {
+		return
+	}
+
+	for _, m := range h.Messages {
+		if m.Level == responseMessageWarn {
+			c.OnWarning(method, resource, m)
+		}
+	}
+}
+
+// postRetrying works like post, but allows for the issues we've found with the
+// DynECT API: the first request may return a 'INVALID_REQUEST' for the POST
+// method, and either the first request or the following ones may receive an
+// 'OPERATION_FAILED' because 'This session already has a job running'. The
+// latter is retried every 5 seconds as recommended by the API specifications,
+// until the client's context is done or, when it has no deadline, 10 times.
+func (c *Client) postRetrying(resource string, requestData interface{}, responseData interface{}) error {
+	ctx := c.Context()
+	_, hasDeadline := ctx.Deadline()
+
+	for try := 0; ; try++ {
+		err := c.post(resource, requestData, responseData)
+		if err == nil {
+			return nil
+		}
+
+		apiErr, isAPIError := err.(*Error)
+		if !isAPIError {
+			return err
+		}
+
+		if try == 0 && apiErr.hasMessage(responseMessageInvalidRequest, "Resource does not support POST requests") {
+			continue
+		}
+
+		if !apiErr.hasMessage(responseMessageOperationFailed, "token: This session already has a job running") ||
+			(!hasDeadline && try >= 9) {
+			return err
+		}
+
+		// We cannot really use the JobID, as when we reached here during our tests it
+		// would only show us that same message over and over as 'this' job failed
+		if err := sleep(ctx, 5*time.Second); err != nil {
+			return fmt.Errorf("gave up retrying after %w: %w", apiErr, err)
+		}
+	}
+}
+
+// stream performs a GET request and hands the response body to decode as it
+// is read, so that large responses don't need to be held in memory. decode
+// fills h, whose warnings are handed to OnWarning once it succeeded.
+func (c *Client) stream(ctx context.Context, resource string, params url.Values, h *responseHeader, decode func(dec *json.Decoder) error) error {
+	resp, err := c.do(ctx, http.MethodGet, resource, params, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if c.Logger != nil {
+		c.Logger.Println("stream: decoding body")
+	}
+
+	if err := decode(json.NewDecoder(resp.Body)); err != nil {
+		return err
+	}
+
+	c.warn(http.MethodGet, resource, h)
+
+	return nil
+}
+
+// do sends a request and returns the response when it succeeded, in which
+// case the caller must close its body. When the API redirects to the job of
+// the request, the job is polled until it's complete.
+func (c *Client) do(ctx context.Context, method, resource string, params url.Values, requestData interface{}) (*http.Response, error) {
+	resp, err := c.send(ctx, method, resource, params, requestData)
+	if err != nil {
+		return nil, err
+	}
+
+	for resp.StatusCode == http.StatusTemporaryRedirect {
+		resp.Body.Close()
+
+		jobID := path.Base(resp.Header.Get("Location"))
+
+		if err := sleep(ctx, JobPollInterval); err != nil {
+			return nil, fmt.Errorf("gave up waiting for job %s: %w", jobID, err)
+		}
+
+		resp, err = c.send(ctx, http.MethodGet, fmt.Sprintf("Job/%s", jobID), nil, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+
+		return nil, c.decodeError(method, resource, resp)
+	}
+
+	return resp, nil
+}
+
+// send makes a single request.
+func (c *Client) send(ctx context.Context, method, resource string, params url.Values, requestData interface{}) (*http.Response, error) {
+	url := c.buildURL(resource, params)
+
+	body, err := c.marshalJSON(requestData)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Logger != nil {
+		c.Logger.Println(method, url, body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.token != "" {
+		req.Header.Set("Auth-Token", c.token)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Add("User-Agent", c.UserAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Logger != nil {
+		c.Logger.Println(resp.StatusCode, "RESPONSE")
+	}
+
+	return resp, nil
+}
+
+// sleep waits for d, or until ctx is done in which case it returns its error.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// buildURL creates a resource URL relative to the base URL.
+func (c *Client) buildURL(resource string, params url.Values) string {
+	path := fmt.Sprintf("/REST/%s", resource)
+
+	rel := &url.URL{Path: path}
+	rel.RawQuery = params.Encode()
+
+	url := c.BaseURL.ResolveReference(rel)
+
+	return url.String()
+}
+
+// marshalJSON converts a request object into JSON.
+func (c *Client) marshalJSON(data interface{}) (io.Reader, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewBuffer(b), nil
+}
+
+// decodeJSON converts JSON into a response object.
+func (c *Client) decodeJSON(r io.Reader, data interface{}) error {
+	if c.Logger != nil {
+		b, _ := ioutil.ReadAll(r)
+		c.Logger.Println("decodeJSON: body", string(b))
+		r = bytes.NewBuffer(b)
+	}
+
+	return json.NewDecoder(r).Decode(data)
+}
+
+// decodeError returns an *Error describing a failed call, when the API
+// responded with its usual JSON.
+func (c *Client) decodeError(method, resource string, resp *http.Response) error {
+	e := &Error{
+		Method:     method,
+		Resource:   resource,
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("%v - unable to read response body", e)
+	}
+
+	if c.Logger != nil {
+		c.Logger.Println("decodeError: body", string(body))
+	}
+
+	var h responseHeader
+
+	if err := json.Unmarshal(body, &h); err != nil {
+		return fmt.Errorf("%v: %v [%v]", err, e, string(body))
+	}
+
+	e.JobID = h.JobID
+	e.Messages = h.Messages
+
+	return e
+}
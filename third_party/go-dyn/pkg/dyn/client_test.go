@@ -0,0 +1,197 @@
+package dyn
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+)
+
+func writeFixture(w http.ResponseWriter, fixture string) error {
+	path := fmt.Sprintf("testdata/%s", fixture)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(w, f)
+
+	return err
+}
+
+func mockClient(fixture string, handlerFunc func(w http.ResponseWriter, r *http.Request, j interface{})) *Client {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var j interface{}
+
+		if r.Body != nil {
+			b, _ := ioutil.ReadAll(r.Body)
+			json.Unmarshal(b, &j)
+		}
+
+		handlerFunc(w, r, j)
+
+		if err := writeFixture(w, fixture); err != nil {
+			panic(err)
+		}
+	}))
+
+	c := NewClient()
+	c.BaseURL, _ = url.Parse(ts.URL)
+
+	return c
+}
+
+func assertEqual(t *testing.T, expected interface{}, actual interface{}, desc string) {
+	t.Helper()
+
+	if actual != expected {
+		t.Errorf("Expected %v of `%v`, got `%v`.", desc, expected, actual)
+	}
+}
+
+func assertHeader(t *testing.T, expected string, header string, r *http.Request) {
+	t.Helper()
+
+	assertEqual(t, expected, r.Header.Get(header), header)
+}
+
+func assertAuthToken(t *testing.T, expected string, r *http.Request) {
+	t.Helper()
+
+	assertHeader(t, expected, "Auth-Token", r)
+}
+
+func assertContentType(t *testing.T, expected string, r *http.Request) {
+	t.Helper()
+
+	assertHeader(t, expected, "Content-Type", r)
+}
+
+func assertUserAgent(t *testing.T, expected string, r *http.Request) {
+	t.Helper()
+
+	assertHeader(t, expected, "User-Agent", r)
+}
+
+func assertMethod(t *testing.T, expected string, r *http.Request) {
+	t.Helper()
+
+	assertEqual(t, expected, r.Method, "request method")
+}
+
+func assertPath(t *testing.T, expected string, r *http.Request) {
+	t.Helper()
+
+	assertEqual(t, expected, r.URL.EscapedPath(), "request path")
+}
+
+func assertParam(t *testing.T, expected string, name string, r *http.Request) {
+	t.Helper()
+
+	assertEqual(t, expected, r.URL.Query().Get(name), name)
+}
+
+func assertJSON(t *testing.T, expected interface{}, path string, j interface{}) {
+	t.Helper()
+
+	var m map[string]interface{}
+	m = j.(map[string]interface{})
+
+	assertEqual(t, expected, m[path], path)
+}
+
+func TestJobPolling(t *testing.T) {
+	zone := "go-dyn-test-publish.go-dyn.com"
+
+	defer func(interval time.Duration) { JobPollInterval = interval }(JobPollInterval)
+	JobPollInterval = time.Millisecond
+
+	polls := 0
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case fmt.Sprintf("/REST/Zone/%s", zone):
+			assertMethod(t, http.MethodPut, r)
+			w.Header().Set("Location", "/REST/Job/12345678")
+			w.WriteHeader(http.StatusTemporaryRedirect)
+		case "/REST/Job/12345678":
+			assertMethod(t, http.MethodGet, r)
+			polls++
+			if polls < 2 {
+				w.Header().Set("Location", "/REST/Job/12345678")
+				w.WriteHeader(http.StatusTemporaryRedirect)
+				return
+			}
+			if err := writeFixture(w, "zone/publish.json"); err != nil {
+				panic(err)
+			}
+		default:
+			t.Errorf("Unexpected request path `%v`.", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	c := NewClient()
+	c.BaseURL, _ = url.Parse(ts.URL)
+
+	z, err := c.PublishZone(zone, "insert notes here")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, 2, polls, "polls")
+	assertZone(t, 1, SerialStyleIncrement, zone, ZoneTypePrimary, z)
+}
+
+func TestWithContext(t *testing.T) {
+	c := mockClient("zone/publish.json", func(w http.ResponseWriter, r *http.Request, j interface{}) {
+		t.Error("Expected no request once canceled")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := c.WithContext(ctx).PublishZone("go-dyn.com", ""); err == nil {
+		t.Error("Expected PublishZone to fail")
+	}
+
+	if c.Context() != context.Background() {
+		t.Error("Expected the original client to stay unbound")
+	}
+
+	if err := sleep(ctx, time.Hour); err != context.Canceled {
+		t.Errorf("Expected sleep to be canceled, got %v", err)
+	}
+}
+
+func TestJobPollingDeadline(t *testing.T) {
+	defer func(interval time.Duration) { JobPollInterval = interval }(JobPollInterval)
+	JobPollInterval = time.Millisecond
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", "/REST/Job/12345678")
+		w.WriteHeader(http.StatusTemporaryRedirect)
+	}))
+	defer ts.Close()
+
+	c := NewClient()
+	c.BaseURL, _ = url.Parse(ts.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := c.WithContext(ctx).PublishZone("go-dyn.com", "")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the deadline to be exceeded, got %v", err)
+	}
+}
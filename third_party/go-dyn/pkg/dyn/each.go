@@ -0,0 +1,157 @@
+package dyn
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrStopIteration can be returned by the function passed to the Each methods
+// to stop iterating early. The Each method then returns without an error.
+var ErrStopIteration = errors.New("stop iteration")
+
+// decodeResponse walks an API response as it's decoded, handing the decoder
+// to data when it reaches the data member. The other members are decoded
+// into h.
+func decodeResponse(dec *json.Decoder, h *responseHeader, data func(dec *json.Decoder) error) error {
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		switch tok {
+		case "data":
+			err = data(dec)
+		case "status":
+			err = dec.Decode(&h.Status)
+		case "job_id":
+			err = dec.Decode(&h.JobID)
+		case "msgs":
+			err = dec.Decode(&h.Messages)
+		default:
+			err = skipValue(dec)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(dec, '}')
+}
+
+// decodeEach calls decode for each element of the JSON array at the decoder,
+// which decode must consume. A null is an empty array.
+func decodeEach(ctx context.Context, dec *json.Decoder, decode func() error) error {
+	if isNull, err := expectDelimOrNull(dec, '['); err != nil || isNull {
+		return err
+	}
+
+	for dec.More() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := decode(); err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(dec, ']')
+}
+
+// decodeEachMember calls decode with the name of each member of the JSON
+// object at the decoder, whose value decode must consume. A null is an empty
+// object.
+func decodeEachMember(dec *json.Decoder, decode func(name string) error) error {
+	if isNull, err := expectDelimOrNull(dec, '{'); err != nil || isNull {
+		return err
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		name, ok := tok.(string)
+		if !ok {
+			return fmt.Errorf("unexpected %v in JSON object", tok)
+		}
+
+		if err := decode(name); err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(dec, '}')
+}
+
+// skipValue consumes the next JSON value at the decoder without keeping it.
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if tok != delim {
+		return fmt.Errorf("expected %v in JSON, got %v", delim, tok)
+	}
+
+	return nil
+}
+
+func expectDelimOrNull(dec *json.Decoder, delim json.Delim) (bool, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return false, err
+	}
+
+	if tok == nil {
+		return true, nil
+	}
+
+	if tok != delim {
+		return false, fmt.Errorf("expected %v in JSON, got %v", delim, tok)
+	}
+
+	return false, nil
+}
+
+// eachResult turns the error that ended an iteration into the one the Each
+// methods return.
+func eachResult(n int, err error) (int, error) {
+	if err == ErrStopIteration {
+		return n, nil
+	}
+
+	return n, err
+}
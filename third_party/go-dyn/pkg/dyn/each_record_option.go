@@ -0,0 +1,38 @@
+package dyn
+
+import "strings"
+
+// EachRecordOption is a basic interface for passing optional parameters to EachRecord
+type EachRecordOption func(*eachRecordOptions)
+
+type eachRecordOptions struct {
+	byNode bool
+	types  map[string]bool
+}
+
+// wants reports whether records of the given type, in any case, are wanted.
+func (o *eachRecordOptions) wants(recordType string) bool {
+	return len(o.types) == 0 || o.types[strings.ToUpper(recordType)]
+}
+
+// ByNode provides an option to get the records of a zone one node at a time,
+// rather than all of them in a single response
+func ByNode() EachRecordOption {
+	return func(o *eachRecordOptions) {
+		o.byNode = true
+	}
+}
+
+// RecordTypes provides an option to only get the records of the given types,
+// the others are skipped without being decoded
+func RecordTypes(types ...string) EachRecordOption {
+	return func(o *eachRecordOptions) {
+		if o.types == nil {
+			o.types = make(map[string]bool)
+		}
+
+		for _, t := range types {
+			o.types[strings.ToUpper(t)] = true
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package dyn
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrorCode values of the messages of an Error that callers may want to act on
+const (
+	ErrorCodeInvalidData      = responseMessageInvalidData
+	ErrorCodeMissingData      = responseMessageMissingData
+	ErrorCodeNotFound         = responseMessageNotFound
+	ErrorCodePermissionDenied = responseMessagePermissionDenied
+)
+
+// Error is returned when an API call fails, with everything the API said
+// about it.
+type Error struct {
+	Method     string
+	Resource   string
+	StatusCode int
+	Status     string
+	JobID      int
+	Messages   []Message
+}
+
+// Error implements the error interface for the Error type. It lists the ERROR
+// and FATAL messages, then the WARN ones, then the call they're about.
+func (e *Error) Error() string {
+	parts := make([]string, 0, len(e.Messages))
+
+	for _, m := range e.Errors() {
+		parts = append(parts, m.Error())
+	}
+
+	if len(parts) == 0 {
+		parts = append(parts, e.Status)
+	}
+
+	for _, m := range e.Warnings() {
+		parts = append(parts, fmt.Sprintf("warning: %v", m.Info))
+	}
+
+	call := fmt.Sprintf("%s %s: %s", e.Method, e.Resource, e.Status)
+	if e.JobID != 0 {
+		call = fmt.Sprintf("%s, job %d", call, e.JobID)
+	}
+
+	return fmt.Sprintf("%s (%s)", strings.Join(parts, "; "), call)
+}
+
+// Errors returns the ERROR and FATAL messages.
+func (e *Error) Errors() []Message {
+	return e.messages(responseMessageError, responseMessageFatal)
+}
+
+// Warnings returns the WARN messages.
+func (e *Error) Warnings() []Message {
+	return e.messages(responseMessageWarn)
+}
+
+func (e *Error) messages(levels ...string) []Message {
+	messages := make([]Message, 0)
+
+	for _, m := range e.Messages {
+		for _, level := range levels {
+			if m.Level == level {
+				messages = append(messages, m)
+			}
+		}
+	}
+
+	return messages
+}
+
+// HasCode reports whether any message has the given ErrorCode.
+func (e *Error) HasCode(code string) bool {
+	for _, m := range e.Messages {
+		if m.ErrorCode == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e *Error) hasMessage(code, info string) bool {
+	for _, m := range e.Messages {
+		if m.ErrorCode == code && m.Info == info {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Command returns the API command the call was made to, such as DSFRecordSet
+// for a POST to DSFRecordSet/{service}. The API doesn't say which permission
+// a PERMISSION_DENIED error is about, only that the user may not make the
+// call.
+func (e *Error) Command() string {
+	return strings.SplitN(e.Resource, "/", 2)[0]
+}
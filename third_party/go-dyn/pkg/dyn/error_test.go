@@ -0,0 +1,49 @@
+package dyn
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestError(t *testing.T) {
+	c := mockClient("error/invalid_data.json", func(w http.ResponseWriter, r *http.Request, j interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+
+		w.WriteHeader(http.StatusBadRequest)
+	})
+
+	_, err := c.CreateTrafficDirectorRecordSet("service-1", "A")
+	if err == nil {
+		t.Fatal("Expected CreateTrafficDirectorRecordSet to fail")
+	}
+
+	apiErr, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("Expected an *Error, got %T", err)
+	}
+
+	assertEqual(t, "INVALID_DATA: label: Label must be unique; warning: ttl: TTL is below the recommended minimum (POST DSFRecordSet/service-1: 400 Bad Request, job 1388281378)", apiErr.Error(), "error")
+	assertEqual(t, 3, len(apiErr.Messages), "messages")
+	assertEqual(t, 1, len(apiErr.Warnings()), "warnings")
+	assertEqual(t, true, apiErr.HasCode(ErrorCodeInvalidData), "HasCode")
+	assertEqual(t, "label", apiErr.Errors()[0].Field(), "Field")
+	assertEqual(t, "DSFRecordSet", apiErr.Command(), "Command")
+}
+
+func TestOnWarning(t *testing.T) {
+	c := mockClient("error/warning.json", func(w http.ResponseWriter, r *http.Request, j interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+	})
+
+	warnings := make([]string, 0)
+	c.OnWarning = func(method, resource string, m Message) {
+		warnings = append(warnings, method+" "+resource+" "+m.Info)
+	}
+
+	if err := c.FreezeZone("go-dyn.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, 1, len(warnings), "warnings")
+	assertEqual(t, "PUT Zone/go-dyn.com ttl: TTL is below the recommended minimum", warnings[0], "warning")
+}
@@ -0,0 +1,290 @@
+package dyn
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Record represents a Dyn zone record.
+type Record struct {
+	recordData
+}
+
+func (r *Record) setTTL(ttl int) {
+	r.TTL = ttl
+}
+
+type recordData struct {
+	Zone        string `json:"zone"`
+	TTL         int    `json:"ttl"`
+	FQDN        string `json:"fqdn"`
+	RecordType  string `json:"record_type"`
+	RData       rData  `json:"rdata"`
+	RecordID    int    `json:"record_id"`
+	SerialStyle string `json:"serial_style,omitempty"`
+}
+
+type rData struct {
+	// A, AAAA
+	Address string `json:"address,omitempty"`
+	// ALIAS
+	Alias string `json:"alias,omitempty"`
+	// CAA
+	Flags int    `json:"flags,omitempty"`
+	Tag   string `json:"tag,omitempty"`
+	Value string `json:"value,omitempty"`
+	//CNAME
+	CName string `json:"cname,omitempty"`
+	// MX
+	Exchange   string `json:"exchange,omitempty"`
+	Preference int    `json:"preference,omitempty"`
+	// NS
+	NSDName string `json:"nsdname,omitempty"`
+	// SOA
+	RName string `json:"rname,omitempty"`
+	// SRV
+	Priority int    `json:"priority,omitempty"`
+	Weight   int    `json:"weight,omitempty"`
+	Port     int    `json:"port,omitempty"`
+	Target   string `json:"target,omitempty"`
+	// TXT
+	TXTData string `json:"txtdata,omitempty"`
+}
+
+// RDataValues provides a functional way to set rData
+type RDataValues func(r *rData)
+
+// NewRecord constructs a Record
+func NewRecord(zone, fqdn, recordType string, options ...TTLOption) *Record {
+	r := &Record{
+		recordData: recordData{
+			Zone:       zone,
+			FQDN:       fqdn,
+			RecordType: recordType,
+		},
+	}
+
+	for _, o := range options {
+		o(r)
+	}
+
+	return r
+}
+
+// NewARecord constructs an A record
+func NewARecord(zone, fqdn string, address string, options ...TTLOption) *Record {
+	r := NewRecord(zone, fqdn, "A", options...)
+
+	r.RData.Address = address
+
+	return r
+}
+
+// NewAAAARecord constructs an AAAA record
+func NewAAAARecord(zone, fqdn string, address string, options ...TTLOption) *Record {
+	r := NewRecord(zone, fqdn, "AAAA", options...)
+
+	r.RData.Address = address
+
+	return r
+}
+
+// NewALIASRecord constructs an ALIAS record
+func NewALIASRecord(zone, fqdn string, alias string, options ...TTLOption) *Record {
+	r := NewRecord(zone, fqdn, "ALIAS", options...)
+
+	r.RData.Alias = alias
+
+	return r
+}
+
+// NewCAARecord constructs a CAA record
+func NewCAARecord(zone, fqdn string, flags int, tag string, value string, options ...TTLOption) *Record {
+	r := NewRecord(zone, fqdn, "CAA", options...)
+
+	r.RData.Flags = flags
+	r.RData.Tag = tag
+	r.RData.Value = value
+
+	return r
+}
+
+// NewCNAMERecord constructs a CNAME record
+func NewCNAMERecord(zone, fqdn string, cname string, options ...TTLOption) *Record {
+	r := NewRecord(zone, fqdn, "CNAME", options...)
+
+	r.RData.CName = cname
+
+	return r
+}
+
+// NewMXRecord constructs an MX record
+func NewMXRecord(zone, fqdn string, preference int, exchange string, options ...TTLOption) *Record {
+	r := NewRecord(zone, fqdn, "MX", options...)
+
+	r.RData.Preference = preference
+	r.RData.Exchange = exchange
+
+	return r
+}
+
+// NewNSRecord constructs an NS record
+func NewNSRecord(zone, fqdn string, nsdname string, options ...TTLOption) *Record {
+	r := NewRecord(zone, fqdn, "NS", options...)
+
+	r.RData.NSDName = nsdname
+
+	return r
+}
+
+// NewSRVRecord constructs an SRV record
+func NewSRVRecord(zone, fqdn string, priority, weight, port int, target string, options ...TTLOption) *Record {
+	r := NewRecord(zone, fqdn, "SRV", options...)
+
+	r.RData.Priority = priority
+	r.RData.Weight = weight
+	r.RData.Port = port
+	r.RData.Target = target
+
+	return r
+}
+
+// NewTXTRecord constructs a TXT record
+func NewTXTRecord(zone, fqdn string, txtdata string, options ...TTLOption) *Record {
+	r := NewRecord(zone, fqdn, "TXT", options...)
+
+	r.RData.TXTData = txtdata
+
+	return r
+}
+
+// String returns a string representation of a Record
+func (r Record) String() string {
+	var rdata string
+
+	switch r.RecordType {
+	case "A", "AAAA":
+		rdata = r.RData.Address
+	case "ALIAS":
+		rdata = r.RData.Alias
+	case "CAA":
+		rdata = fmt.Sprintf("%d %s %q", r.RData.Flags, r.RData.Tag, r.RData.Value)
+	case "CNAME":
+		rdata = r.RData.CName
+	case "MX":
+		rdata = fmt.Sprintf("%d %s", r.RData.Preference, r.RData.Exchange)
+	case "NS":
+		rdata = r.RData.NSDName
+	case "SOA":
+		rdata = r.RData.RName
+	case "SRV":
+		rdata = fmt.Sprintf("%d %d %d %s", r.RData.Priority, r.RData.Weight, r.RData.Port, r.RData.Target)
+	case "TXT":
+		rdata = fmt.Sprintf("%q", r.RData.TXTData)
+	}
+
+	tabs := 3 - int((len(r.FQDN)+1)/8)
+
+	if tabs < 0 {
+		tabs = 0
+	}
+
+	return fmt.Sprintf("%s.%s%d\tIN\t%s\t%v", r.FQDN, "\t\t\t"[:tabs], r.TTL, r.RecordType, rdata)
+}
+
+// EachRecord calls the provided function once for each record in the zone, as
+// the response is read. Records come grouped by type, in the order the API
+// returns them. The whole zone is fetched in one response unless ByNode is
+// given, and RecordTypes skips the types that aren't wanted. Iterating stops
+// when f returns an error, or ErrStopIteration to stop without failing, or
+// when ctx is done.
+func (c *Client) EachRecord(ctx context.Context, zone string, f func(r *Record) error, options ...EachRecordOption) (int, error) {
+	o := &eachRecordOptions{}
+
+	for _, option := range options {
+		option(o)
+	}
+
+	n := 0
+
+	each := func(r *Record) error {
+		n++
+
+		return f(r)
+	}
+
+	if !o.byNode {
+		err := c.eachRecordPage(ctx, fmt.Sprintf("AllRecord/%s", zone), "", o, each)
+
+		return eachResult(n, err)
+	}
+
+	nodes, err := c.nodeList(ctx, zone)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, node := range nodes {
+		if err := ctx.Err(); err != nil {
+			return n, err
+		}
+
+		if err := c.eachRecordPage(ctx, fmt.Sprintf("ANYRecord/%s/%s", zone, node), node, o, each); err != nil {
+			return eachResult(n, err)
+		}
+	}
+
+	return n, nil
+}
+
+// eachRecordPage streams the records of a response whose data groups them by
+// type, like AllRecord and ANYRecord do. When node isn't empty, records of
+// other nodes are skipped.
+func (c *Client) eachRecordPage(ctx context.Context, resource, node string, o *eachRecordOptions, f func(r *Record) error) error {
+	var h responseHeader
+
+	params := url.Values{}
+	params.Set("detail", "Y")
+
+	return c.stream(ctx, resource, params, &h, func(dec *json.Decoder) error {
+		return decodeResponse(dec, &h, func(dec *json.Decoder) error {
+			return decodeEachMember(dec, func(name string) error {
+				if !o.wants(strings.TrimSuffix(name, "_records")) {
+					return skipValue(dec)
+				}
+
+				return decodeEach(ctx, dec, func() error {
+					r := &Record{}
+
+					if err := dec.Decode(&r.recordData); err != nil {
+						return err
+					}
+
+					if !o.wants(r.RecordType) || (node != "" && !strings.EqualFold(r.FQDN, node)) {
+						return nil
+					}
+
+					return f(r)
+				})
+			})
+		})
+	})
+}
+
+// nodeList returns the names of the nodes of a zone.
+func (c *Client) nodeList(ctx context.Context, zone string) ([]string, error) {
+	var h responseHeader
+
+	var nodes []string
+
+	err := c.stream(ctx, fmt.Sprintf("NodeList/%s", zone), nil, &h, func(dec *json.Decoder) error {
+		return decodeResponse(dec, &h, func(dec *json.Decoder) error {
+			return dec.Decode(&nodes)
+		})
+	})
+
+	return nodes, err
+}
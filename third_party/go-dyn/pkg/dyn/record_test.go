@@ -0,0 +1,178 @@
+package dyn
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func assertRecord(t *testing.T, expected *Record, r *Record) {
+	t.Helper()
+
+	assertEqual(t, expected.Zone, r.Zone, "Zone")
+	assertEqual(t, expected.TTL, r.TTL, "TTL")
+	assertEqual(t, expected.FQDN, r.FQDN, "FQDN")
+	assertEqual(t, expected.RecordType, r.RecordType, "RecordType")
+
+	assertEqual(t, expected.RData, r.RData, "RData")
+}
+
+func TestEachRecord(t *testing.T) {
+	zone := "go-dyn.com"
+
+	c := mockClient("record/each.json", func(w http.ResponseWriter, r *http.Request, j interface{}) {
+		assertMethod(t, http.MethodGet, r)
+		assertPath(t, "/REST/AllRecord/go-dyn.com", r)
+		assertParam(t, "Y", "detail", r)
+
+		assertUserAgent(t, "go-dyn/0.0.0", r)
+		assertContentType(t, "application/json", r)
+		assertAuthToken(t, "insert-token-here", r)
+
+		w.Header().Set("Content-Type", "application/json")
+	})
+
+	c.token = "insert-token-here"
+
+	i := 0
+
+	n, err := c.EachRecord(context.Background(), zone, func(r *Record) error {
+		switch i {
+		case 0:
+			assertRecord(t, NewARecord(zone, "a.go-dyn.com", "10.1.2.3", TTL(3600)), r)
+		case 1:
+			assertRecord(t, NewAAAARecord(zone, "aaaa.go-dyn.com", "fdfe:972e:46c1:6ed0:0000:0001:0002:0003", TTL(3600)), r)
+		case 2:
+			assertRecord(t, NewALIASRecord(zone, "alias.go-dyn.com", "go-dyn.com.", TTL(30)), r)
+		case 3:
+			assertRecord(t, NewCAARecord(zone, "caa.go-dyn.com", 0, "issue", "letsencrypt.org", TTL(1800)), r)
+		case 4:
+			assertRecord(t, NewCNAMERecord(zone, "cname.go-dyn.com", "go-dyn.example.com.", TTL(900)), r)
+		case 5:
+			assertRecord(t, NewMXRecord(zone, "mx.go-dyn.com", 10, "mail.example.com.", TTL(3600)), r)
+		case 6:
+			assertRecord(t, NewNSRecord(zone, "go-dyn.com", "ns1.p19.dynect.net.", TTL(86400)), r)
+		case 7:
+			assertRecord(t, NewNSRecord(zone, "go-dyn.com", "ns2.p19.dynect.net.", TTL(86400)), r)
+		case 8:
+			assertRecord(t, NewNSRecord(zone, "go-dyn.com", "ns3.p19.dynect.net.", TTL(86400)), r)
+		case 9:
+			assertRecord(t, NewNSRecord(zone, "go-dyn.com", "ns4.p19.dynect.net.", TTL(86400)), r)
+		case 10:
+			soa := NewRecord(zone, "go-dyn.com", "SOA", TTL(3600))
+			soa.RData.RName = "admin@example.com."
+			assertRecord(t, soa, r)
+		case 11:
+			assertRecord(t, NewSRVRecord(zone, "srv.go-dyn.com", 10, 100, 443, "www.example.com.", TTL(3600)), r)
+		case 12:
+			assertRecord(t, NewTXTRecord(zone, "txt.go-dyn.com", "hello world", TTL(7200)), r)
+		}
+		i++
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, 13, n, "count")
+}
+
+func TestEachRecordStop(t *testing.T) {
+	c := mockClient("record/each.json", func(w http.ResponseWriter, r *http.Request, j interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+	})
+
+	n, err := c.EachRecord(context.Background(), "go-dyn.com", func(r *Record) error {
+		if r.RecordType == "AAAA" {
+			return ErrStopIteration
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, 2, n, "count")
+}
+
+func TestEachRecordTypes(t *testing.T) {
+	c := mockClient("record/each.json", func(w http.ResponseWriter, r *http.Request, j interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+	})
+
+	n, err := c.EachRecord(context.Background(), "go-dyn.com", func(r *Record) error {
+		assertEqual(t, "NS", r.RecordType, "RecordType")
+		return nil
+	}, RecordTypes("ns"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, 4, n, "count")
+}
+
+func TestEachRecordByNode(t *testing.T) {
+	fixtures := map[string]string{
+		"/REST/NodeList/go-dyn.com":               "record/node_list.json",
+		"/REST/ANYRecord/go-dyn.com/go-dyn.com":   "record/any_apex.json",
+		"/REST/ANYRecord/go-dyn.com/a.go-dyn.com": "record/any_a.json",
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, http.MethodGet, r)
+
+		fixture, ok := fixtures[r.URL.Path]
+		if !ok {
+			t.Errorf("Unexpected request path `%v`.", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := writeFixture(w, fixture); err != nil {
+			panic(err)
+		}
+	}))
+	defer ts.Close()
+
+	c := NewClient()
+	c.BaseURL, _ = url.Parse(ts.URL)
+
+	fqdns := make([]string, 0)
+
+	n, err := c.EachRecord(context.Background(), "go-dyn.com", func(r *Record) error {
+		fqdns = append(fqdns, r.FQDN+" "+r.RecordType)
+		return nil
+	}, ByNode())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, 4, n, "count")
+	assertEqual(t, "go-dyn.com NS,go-dyn.com NS,go-dyn.com SOA,a.go-dyn.com A", strings.Join(fqdns, ","), "records")
+}
+
+func TestEachRecordCanceled(t *testing.T) {
+	c := mockClient("record/each.json", func(w http.ResponseWriter, r *http.Request, j interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := c.EachRecord(ctx, "go-dyn.com", func(r *Record) error {
+		t.Error("Expected no record once canceled")
+		return nil
+	})
+
+	if err == nil {
+		t.Fatal("Expected EachRecord to fail")
+	}
+}
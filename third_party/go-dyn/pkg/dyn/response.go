@@ -0,0 +1,80 @@
+package dyn
+
+import (
+	"fmt"
+	"strings"
+)
+
+// values for responseHeader.Status
+const (
+	responseSuccess    = "success"
+	responseFailure    = "failure"
+	responseIncomplete = "incomplete"
+)
+
+// values for Message.Level
+const (
+	responseMessageFatal = "FATAL"
+	responseMessageError = "ERROR"
+	responseMessageWarn  = "WARN"
+	responseMessageInfo  = "INFO"
+)
+
+// values for Message.ErrorCode
+const (
+	responseMessageDeprecatedRequest  = "DEPRECATED_REQUEST"  // The requested command is deprecated
+	responseMessageIllegalOperation   = "ILLEGAL_OPERATION"   // The operation is not allowed with this data set
+	responseMessageInternalError      = "INTERNAL_ERROR"      // An error occurred that cannot be classified.
+	responseMessageInvalidData        = "INVALID_DATA"        // A field contained data that was invalid
+	responseMessageInvalidRequest     = "INVALID_REQUEST"     // The request was not recognized as a valid command
+	responseMessageInvalidVersion     = "INVALID_VERSION"     // The version number passed in was invalid
+	responseMessageMissingData        = "MISSING_DATA"        // A required field was not provided
+	responseMessageNotFound           = "NOT_FOUND"           // No results were found
+	responseMessageOperationFailed    = "OPERATION_FAILED"    // The operation failed to complete successfully
+	responseMessagePermissionDenied   = "PERMISSION_DENIED"   // This user does not have permission to perform this action
+	responseMessageServiceUnavailable = "SERVICE_UNAVAILABLE" // The requested service is currently unavailable.
+	responseMessageTargetExists       = "TARGET_EXISTS"       // Attempted to add a duplicate resource
+	responseMessageUnknownError       = "UNKNOWN_ERROR"       // An error occurred that cannot be classified
+)
+
+// common header for API responses
+type responseHeader struct {
+	JobID    int       `json:"job_id,omitempty"`
+	Status   string    `json:"status"`
+	Messages []Message `json:"msgs,omitempty"`
+}
+
+func (h *responseHeader) header() *responseHeader {
+	return h
+}
+
+// Message is one of the messages the API returns about a call.
+type Message struct {
+	Source    string `json:"SOURCE"`
+	Level     string `json:"LVL"`
+	Info      string `json:"INFO"`
+	ErrorCode string `json:"ERR_CD"`
+}
+
+// Error implements the error interface for the Message type.
+func (m Message) Error() string {
+	return fmt.Sprintf("%v: %v", m.ErrorCode, m.Info)
+}
+
+// Field returns the request field the message is about, when its INFO starts
+// with one as in "label: Label must be unique", or an empty string.
+func (m Message) Field() string {
+	idx := strings.Index(m.Info, ": ")
+	if idx <= 0 {
+		return ""
+	}
+
+	field := m.Info[:idx]
+	for _, r := range field {
+		if r != '_' && (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return ""
+		}
+	}
+
+	return field
+}
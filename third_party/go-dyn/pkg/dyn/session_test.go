@@ -35,7 +35,7 @@ func TestSessionLogInError(t *testing.T) {
 	})
 
 	if err := c.LogIn("insert-customer-here", "insert-user-here", "insert-password-here"); err != nil {
-		assertEqual(t, "INVALID_DATA: login: Invalid credentials", err.Error(), "error")
+		assertEqual(t, "INVALID_DATA: login: Invalid credentials (POST Session: 400 Bad Request, job 12345678)", err.Error(), "error")
 	} else {
 		t.Error("Expected LogIn to fail")
 	}
@@ -71,7 +71,7 @@ func TestSessionIsActiveError(t *testing.T) {
 
 	if ok, err := c.IsActive(); err != nil {
 		assertEqual(t, false, ok, "IsActive")
-		assertEqual(t, "INVALID_DATA: login: Bad or expired credentials", err.Error(), "error")
+		assertEqual(t, "INVALID_DATA: login: Bad or expired credentials (GET Session: 400 Bad Request, job 12345678)", err.Error(), "error")
 	} else {
 		t.Error("Expected IsActive to fail")
 	}
@@ -104,7 +104,7 @@ func TestSessionKeepAliveError(t *testing.T) {
 	})
 
 	if err := c.KeepAlive(); err != nil {
-		assertEqual(t, "INVALID_DATA: login: Bad or expired credentials", err.Error(), "error")
+		assertEqual(t, "INVALID_DATA: login: Bad or expired credentials (PUT Session: 400 Bad Request, job 12345678)", err.Error(), "error")
 	} else {
 		t.Error("Expected KeepAlive to fail")
 	}
@@ -137,7 +137,7 @@ func TestSessionLogOutError(t *testing.T) {
 	})
 
 	if err := c.LogOut(); err != nil {
-		assertEqual(t, "INVALID_DATA: login: Bad or expired credentials", err.Error(), "error")
+		assertEqual(t, "INVALID_DATA: login: Bad or expired credentials (DELETE Session: 400 Bad Request, job 12345678)", err.Error(), "error")
 	} else {
 		t.Error("Expected LogOut to fail")
 	}
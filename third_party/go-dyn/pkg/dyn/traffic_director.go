@@ -0,0 +1,312 @@
+package dyn
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// TrafficDirector represents a Dyn Traffic Director service.
+type TrafficDirector struct {
+	ServiceID     string
+	Label         string
+	Active        bool
+	TTL           int
+	Nodes         []TrafficDirectorNode
+	Rulesets      []*TrafficDirectorRuleset
+	ResponsePools []*TrafficDirectorResponsePool
+	PendingChange string
+}
+
+type trafficDirectorData struct {
+	ServiceID     string                       `json:"service_id"`
+	Label         string                       `json:"label"`
+	Active        string                       `json:"active"`
+	TTL           string                       `json:"ttl"`
+	Notifiers     []trafficDirectorNotifier    `json:"notifiers"`
+	Rulesets      []trafficDirectorRulesetData `json:"rulesets"`
+	Nodes         []TrafficDirectorNode        `json:"nodes"`
+	PendingChange string                       `json:"pending_change"`
+}
+
+// TrafficDirectorNode represents a zone and FQDN served by a Traffic Director service.
+type TrafficDirectorNode struct {
+	Zone string `json:"zone"`
+	FQDN string `json:"fqdn"`
+}
+
+type trafficDirectorNotifier struct{}
+
+type TrafficDirectorCURequest struct {
+	Label     string                            `json:"label"`
+	TTL       int                               `json:"ttl,omitempty"`
+	Active    string                            `json:"active,omitempty"`
+	Publish   string                            `json:"publish,omitempty"`
+	Notes     string                            `json:"notes,omitempty"`
+	Nodes     []TrafficDirectorNode             `json:"nodes,omitempty"`
+	Notifiers []trafficDirectorNotifier         `json:"notifiers,omitempty"`
+	Rulesets  []TrafficDirectorRulesetCURequest `json:"rulesets,omitempty"`
+}
+
+func (tdreq *TrafficDirectorCURequest) AddNode(node map[string]string) {
+	tdreq.Nodes = append(tdreq.Nodes, TrafficDirectorNode{
+		Zone: node["zone"],
+		FQDN: node["fqdn"],
+	})
+}
+
+type trafficDirectorResponse struct {
+	responseHeader
+	trafficDirectorData `json:"data"`
+}
+
+type trafficDirectorJSONResponse struct {
+	responseHeader
+	Data json.RawMessage `json:"data"`
+}
+
+type trafficDirectorAllResponse struct {
+	responseHeader
+	TrafficDirectors []trafficDirectorData `json:"data"`
+}
+
+type TrafficDirectorOptionSetter func(*TrafficDirectorCURequest)
+
+func (tdd trafficDirectorData) newTrafficDirector() *TrafficDirector {
+	ttl, _ := strconv.Atoi(tdd.TTL)
+
+	td := TrafficDirector{
+		ServiceID:     tdd.ServiceID,
+		Label:         tdd.Label,
+		Active:        tdd.Active == "Y",
+		TTL:           ttl,
+		Nodes:         tdd.Nodes,
+		PendingChange: tdd.PendingChange,
+		Rulesets:      make([]*TrafficDirectorRuleset, len(tdd.Rulesets)),
+	}
+
+	responsePools := make(map[string]*TrafficDirectorResponsePool)
+	for idx, ruleset := range tdd.Rulesets {
+		td.Rulesets[idx] = ruleset.newTrafficDirectorRuleset()
+		for _, responsePool := range td.Rulesets[idx].ResponsePools {
+			responsePools[responsePool.ResponsePoolID] = responsePool
+		}
+	}
+
+	td.ResponsePools = make([]*TrafficDirectorResponsePool, 0, len(responsePools))
+	for _, responsePool := range responsePools {
+		td.ResponsePools = append(td.ResponsePools, responsePool)
+	}
+
+	return &td
+}
+
+// CreateTrafficDirector creates a new instance of Traffic Director.
+func (c *Client) CreateTrafficDirector(label string, options ...TrafficDirectorOptionSetter) (*TrafficDirector, error) {
+	req := TrafficDirectorCURequest{
+		Label:   label,
+		Publish: "Y",
+	}
+
+	for _, o := range options {
+		o(&req)
+	}
+
+	var resp trafficDirectorResponse
+
+	if err := c.post("DSF", req, &resp); err != nil {
+		return nil, err
+	}
+
+	td := resp.newTrafficDirector()
+
+	return td, nil
+}
+
+// UpdateTrafficDirector updates an instance of Traffic Director.
+func (c *Client) UpdateTrafficDirector(serviceID string, label string, options ...TrafficDirectorOptionSetter) (*TrafficDirector, error) {
+	req := TrafficDirectorCURequest{
+		Label:   label,
+		Publish: "Y",
+	}
+
+	for _, o := range options {
+		o(&req)
+	}
+
+	var resp trafficDirectorResponse
+
+	if err := c.put(fmt.Sprintf("DSF/%s", serviceID), req, &resp); err != nil {
+		return nil, err
+	}
+
+	td := resp.newTrafficDirector()
+
+	return td, nil
+}
+
+type trafficDirectorPublishRequest struct {
+	Publish string `json:"publish"`
+	Notes   string `json:"notes,omitempty"`
+}
+
+// PublishTrafficDirector publishes the pending changes of a Traffic Director service instance.
+func (c *Client) PublishTrafficDirector(serviceID string) (*TrafficDirector, error) {
+	req := trafficDirectorPublishRequest{
+		Publish: "Y",
+	}
+
+	var resp trafficDirectorResponse
+
+	if err := c.put(fmt.Sprintf("DSF/%s", serviceID), req, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.newTrafficDirector(), nil
+}
+
+// DeleteTrafficDirector deletes an instance of Traffic Director.
+func (c *Client) DeleteTrafficDirector(serviceID string) error {
+	if err := c.delete(fmt.Sprintf("DSF/%s", serviceID), nil); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// EachTrafficDirector calls the provided function for every existing Traffic Director service instance,
+// as the response is read. Iterating stops when f returns an error, or
+// ErrStopIteration to stop without failing, or when ctx is done.
+func (c *Client) EachTrafficDirector(ctx context.Context, f func(td *TrafficDirector) error) (int, error) {
+	var h responseHeader
+
+	params := url.Values{}
+	params.Set("detail", "Y")
+
+	n := 0
+
+	err := c.stream(ctx, "DSF", params, &h, func(dec *json.Decoder) error {
+		return decodeResponse(dec, &h, func(dec *json.Decoder) error {
+			return decodeEach(ctx, dec, func() error {
+				var tdd trafficDirectorData
+
+				if err := dec.Decode(&tdd); err != nil {
+					return err
+				}
+
+				n++
+
+				return f(tdd.newTrafficDirector())
+			})
+		})
+	})
+
+	return eachResult(n, err)
+}
+
+// FindTrafficDirectors returns every Traffic Director service instance with the specified label.
+func (c *Client) FindTrafficDirectors(label string) ([]*TrafficDirector, error) {
+	params := url.Values{}
+	params.Set("label", label)
+	params.Set("detail", "Y")
+
+	var resp trafficDirectorAllResponse
+
+	if err := c.get("DSF", params, &resp); err != nil {
+		return nil, err
+	}
+
+	tds := make([]*TrafficDirector, 0, len(resp.TrafficDirectors))
+	for _, td := range resp.TrafficDirectors {
+		if td.Label == label {
+			tds = append(tds, td.newTrafficDirector())
+		}
+	}
+
+	return tds, nil
+}
+
+// FindTrafficDirector returns the Traffic Director service instance with the specified label.
+// It fails when more than one has that label.
+func (c *Client) FindTrafficDirector(label string) (*TrafficDirector, error) {
+	tds, err := c.FindTrafficDirectors(label)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(tds) {
+	case 0:
+		return nil, fmt.Errorf("Unable to find a traffic director for label: %s", label)
+	case 1:
+		return c.GetTrafficDirector(tds[0].ServiceID)
+	}
+
+	ids := make([]string, len(tds))
+	for idx, td := range tds {
+		ids[idx] = td.ServiceID
+	}
+
+	return nil, fmt.Errorf("Found %d traffic directors for label %s: %s", len(tds), label, strings.Join(ids, ", "))
+}
+
+// GetTrafficDirector returns an existing Traffic Director service instance.
+func (c *Client) GetTrafficDirector(serviceID string) (*TrafficDirector, error) {
+	var resp trafficDirectorResponse
+
+	if err := c.get(fmt.Sprintf("DSF/%s", serviceID), nil, &resp); err != nil {
+		return nil, err
+	}
+
+	// return nil, fmt.Errorf("BLIH: %#v", resp)
+
+	return resp.newTrafficDirector(), nil
+}
+
+// GetTrafficDirectorJSON returns the DSF document of an existing Traffic Director service
+// instance, as sent by Dyn.
+func (c *Client) GetTrafficDirectorJSON(serviceID string) (json.RawMessage, error) {
+	var resp trafficDirectorJSONResponse
+
+	if err := c.get(fmt.Sprintf("DSF/%s", serviceID), nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Data, nil
+}
+
+// CreateTrafficDirectorJSON creates a new instance of Traffic Director from a DSF document.
+func (c *Client) CreateTrafficDirectorJSON(document map[string]interface{}) (*TrafficDirector, error) {
+	req := make(map[string]interface{}, len(document)+1)
+	for k, v := range document {
+		req[k] = v
+	}
+	req["publish"] = "Y"
+
+	var resp trafficDirectorResponse
+
+	if err := c.post("DSF", req, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.newTrafficDirector(), nil
+}
+
+// UpdateTrafficDirectorJSON updates an instance of Traffic Director from a DSF document.
+func (c *Client) UpdateTrafficDirectorJSON(serviceID string, document map[string]interface{}) (*TrafficDirector, error) {
+	req := make(map[string]interface{}, len(document)+1)
+	for k, v := range document {
+		req[k] = v
+	}
+	req["publish"] = "Y"
+
+	var resp trafficDirectorResponse
+
+	if err := c.put(fmt.Sprintf("DSF/%s", serviceID), req, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.newTrafficDirector(), nil
+}
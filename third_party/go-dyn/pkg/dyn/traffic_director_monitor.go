@@ -0,0 +1,272 @@
+package dyn
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// values for TrafficDirectorMonitor.Protocol
+const (
+	MonitorProtocolHTTP  = "HTTP"
+	MonitorProtocolHTTPS = "HTTPS"
+	MonitorProtocolPing  = "PING"
+	MonitorProtocolSMTP  = "SMTP"
+	MonitorProtocolTCP   = "TCP"
+)
+
+// TrafficDirectorMonitor represents a Dyn Traffic Director Monitor.
+type TrafficDirectorMonitor struct {
+	MonitorID     string
+	Label         string
+	Retries       int
+	Protocol      string
+	ResponseCount int
+	ProbeInterval int
+	Active        bool
+	Options       TrafficDirectorMonitorOptions
+	Services      []string
+}
+
+type TrafficDirectorMonitorOptions struct {
+	Header   string
+	Host     string
+	Expected string
+	Path     string
+	Port     int
+	Timeout  int
+}
+
+type trafficDirectorMonitorData struct {
+	MonitorID     string                            `json:"dsf_monitor_id"`
+	Label         string                            `json:"label"`
+	Retries       string                            `json:"retries"`
+	Protocol      string                            `json:"protocol"`
+	ResponseCount string                            `json:"response_count"`
+	ProbeInterval string                            `json:"probe_interval"`
+	Active        string                            `json:"active"`
+	Options       trafficDirectorMonitorOptionsData `json:"options"`
+	Services      []string                          `json:"services"`
+}
+
+type trafficDirectorMonitorOptionsData struct {
+	Header   string `json:"header"`
+	Host     string `json:"host"`
+	Expected string `json:"expected"`
+	Path     string `json:"path"`
+	Port     string `json:"port"`
+	Timeout  string `json:"timeout"`
+}
+
+type TrafficDirectorMonitorCURequest struct {
+	Label         string                                 `json:"label"`
+	Retries       int                                    `json:"retries"`
+	Protocol      string                                 `json:"protocol"`
+	ResponseCount int                                    `json:"response_count"`
+	ProbeInterval int                                    `json:"probe_interval"`
+	Active        string                                 `json:"active,omitempty"`
+	Options       trafficDirectorMonitorCURequestOptions `json:"options,omitempty"`
+	Publish       string                                 `json:"publish,omitempty"`
+	Notes         string                                 `json:"notes,omitempty"`
+}
+
+type trafficDirectorMonitorCURequestOptions struct {
+	Header   string `json:"header,omitempty"`
+	Host     string `json:"host,omitempty"`
+	Expected string `json:"expected,omitempty"`
+	Path     string `json:"path,omitempty"`
+	Port     int    `json:"port,omitempty"`
+	Timeout  int    `json:"timeout,omitempty"`
+}
+
+type trafficDirectorMonitorDeleteRequest struct {
+	Publish string `json:"publish,omitempty"`
+	Notes   string `json:"notes,omitempty"`
+}
+
+type trafficDirectorMonitorResponse struct {
+	responseHeader
+	trafficDirectorMonitorData `json:"data"`
+}
+
+type trafficDirectorMonitorAllResponse struct {
+	responseHeader
+	TrafficDirectorMonitors []trafficDirectorMonitorData `json:"data"`
+}
+
+type TrafficDirectorMonitorOptionSetter func(*TrafficDirectorMonitorCURequest)
+
+func (tdmd trafficDirectorMonitorData) newTrafficDirectorMonitor() *TrafficDirectorMonitor {
+	retries, _ := strconv.Atoi(tdmd.Retries)
+	responseCount, _ := strconv.Atoi(tdmd.ResponseCount)
+	probeInterval, _ := strconv.Atoi(tdmd.ProbeInterval)
+	port, _ := strconv.Atoi(tdmd.Options.Port)
+	timeout, _ := strconv.Atoi(tdmd.Options.Timeout)
+
+	tdrs := TrafficDirectorMonitor{
+		MonitorID:     tdmd.MonitorID,
+		Label:         tdmd.Label,
+		Retries:       retries,
+		Protocol:      tdmd.Protocol,
+		ResponseCount: responseCount,
+		ProbeInterval: probeInterval,
+		Active:        tdmd.Active == "Y",
+		Options: TrafficDirectorMonitorOptions{
+			Header:   tdmd.Options.Header,
+			Host:     tdmd.Options.Host,
+			Expected: tdmd.Options.Expected,
+			Path:     tdmd.Options.Path,
+			Port:     port,
+			Timeout:  timeout,
+		},
+		Services: tdmd.Services,
+	}
+
+	return &tdrs
+}
+
+// CreateTrafficDirectorMonitor creates a new instance of Traffic Director Monitor.
+func (c *Client) CreateTrafficDirectorMonitor(label string, options ...TrafficDirectorMonitorOptionSetter) (*TrafficDirectorMonitor, error) {
+	req := TrafficDirectorMonitorCURequest{
+		Label:   label,
+		Publish: "Y",
+	}
+
+	for _, o := range options {
+		o(&req)
+	}
+
+	var resp trafficDirectorMonitorResponse
+
+	if err := c.post("DSFMonitor", req, &resp); err != nil {
+		return nil, err
+	}
+
+	tdrs := resp.newTrafficDirectorMonitor()
+
+	return tdrs, nil
+}
+
+// UpdateTrafficDirectorMonitor updates an instance of Traffic Director Monitor.
+func (c *Client) UpdateTrafficDirectorMonitor(monitorID string, label string, options ...TrafficDirectorMonitorOptionSetter) (*TrafficDirectorMonitor, error) {
+	req := TrafficDirectorMonitorCURequest{
+		Label:   label,
+		Publish: "Y",
+	}
+
+	for _, o := range options {
+		o(&req)
+	}
+
+	var resp trafficDirectorMonitorResponse
+
+	if err := c.put(fmt.Sprintf("DSFMonitor/%s", monitorID), req, &resp); err != nil {
+		return nil, err
+	}
+
+	tdrs := resp.newTrafficDirectorMonitor()
+
+	return tdrs, nil
+}
+
+// DeleteTrafficDirectorMonitor deletes an instance of Traffic Director Monitor.
+func (c *Client) DeleteTrafficDirectorMonitor(monitorID string) error {
+	req := trafficDirectorMonitorDeleteRequest{
+		Publish: "Y",
+	}
+
+	if err := c.delete(fmt.Sprintf("DSFMonitor/%s", monitorID), req); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// EachTrafficDirectorMonitor calls the provided function for every existing Traffic Director Monitor instance,
+// as the response is read. Iterating stops when f returns an error, or
+// ErrStopIteration to stop without failing, or when ctx is done.
+func (c *Client) EachTrafficDirectorMonitor(ctx context.Context, f func(tdm *TrafficDirectorMonitor) error) (int, error) {
+	var h responseHeader
+
+	params := url.Values{}
+	params.Set("detail", "Y")
+
+	n := 0
+
+	err := c.stream(ctx, "DSFMonitor", params, &h, func(dec *json.Decoder) error {
+		return decodeResponse(dec, &h, func(dec *json.Decoder) error {
+			return decodeEach(ctx, dec, func() error {
+				var tdmd trafficDirectorMonitorData
+
+				if err := dec.Decode(&tdmd); err != nil {
+					return err
+				}
+
+				n++
+
+				return f(tdmd.newTrafficDirectorMonitor())
+			})
+		})
+	})
+
+	return eachResult(n, err)
+}
+
+// FindTrafficDirectorMonitors returns every Traffic Director Monitor instance with the specified label.
+func (c *Client) FindTrafficDirectorMonitors(label string) ([]*TrafficDirectorMonitor, error) {
+	params := url.Values{}
+	params.Set("label", label)
+	params.Set("detail", "Y")
+
+	var resp trafficDirectorMonitorAllResponse
+
+	if err := c.get("DSFMonitor", params, &resp); err != nil {
+		return nil, err
+	}
+
+	tdms := make([]*TrafficDirectorMonitor, 0, len(resp.TrafficDirectorMonitors))
+	for _, tdm := range resp.TrafficDirectorMonitors {
+		if tdm.Label == label {
+			tdms = append(tdms, tdm.newTrafficDirectorMonitor())
+		}
+	}
+
+	return tdms, nil
+}
+
+// FindTrafficDirectorMonitor returns the existing Traffic Director Monitor instance with the specified label.
+// It fails when more than one has that label.
+func (c *Client) FindTrafficDirectorMonitor(label string) (*TrafficDirectorMonitor, error) {
+	tdms, err := c.FindTrafficDirectorMonitors(label)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(tdms) {
+	case 0:
+		return nil, fmt.Errorf("Unable to find a traffic director monitor for label: %s", label)
+	case 1:
+		return tdms[0], nil
+	}
+
+	ids := make([]string, len(tdms))
+	for idx, tdm := range tdms {
+		ids[idx] = tdm.MonitorID
+	}
+
+	return nil, fmt.Errorf("Found %d traffic director monitors for label %s: %s", len(tdms), label, strings.Join(ids, ", "))
+}
+
+// GetTrafficDirectorMonitor returns an existing Traffic Director Monitor instance.
+func (c *Client) GetTrafficDirectorMonitor(monitorID string) (*TrafficDirectorMonitor, error) {
+	var resp trafficDirectorMonitorResponse
+
+	if err := c.get(fmt.Sprintf("DSFMonitor/%s", monitorID), nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.newTrafficDirectorMonitor(), nil
+}
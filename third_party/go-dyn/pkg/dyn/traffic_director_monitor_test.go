@@ -0,0 +1,38 @@
+package dyn
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestFindTrafficDirectorMonitors(t *testing.T) {
+	requests := 0
+
+	c := mockClient("traffic_director_monitor/find.json", func(w http.ResponseWriter, r *http.Request, j interface{}) {
+		requests++
+
+		assertMethod(t, http.MethodGet, r)
+		assertPath(t, "/REST/DSFMonitor", r)
+		assertParam(t, "web", "label", r)
+		assertParam(t, "Y", "detail", r)
+
+		assertUserAgent(t, "go-dyn/0.0.0", r)
+		assertContentType(t, "application/json", r)
+		assertAuthToken(t, "insert-token-here", r)
+
+		w.Header().Set("Content-Type", "application/json")
+	})
+
+	c.token = "insert-token-here"
+
+	tdms, err := c.FindTrafficDirectorMonitors("web")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, 1, requests, "request count")
+	assertEqual(t, 1, len(tdms), "monitor count")
+	assertEqual(t, "monitor-1", tdms[0].MonitorID, "MonitorID")
+	assertEqual(t, "/health", tdms[0].Options.Path, "Options.Path")
+	assertEqual(t, 80, tdms[0].Options.Port, "Options.Port")
+}
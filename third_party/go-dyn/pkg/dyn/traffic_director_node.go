@@ -0,0 +1,60 @@
+package dyn
+
+import (
+	"fmt"
+)
+
+type trafficDirectorNodeRequest struct {
+	Zone    string `json:"zone"`
+	FQDN    string `json:"fqdn"`
+	Publish string `json:"publish,omitempty"`
+	Notes   string `json:"notes,omitempty"`
+}
+
+type trafficDirectorNodeAllResponse struct {
+	responseHeader
+	Nodes []TrafficDirectorNode `json:"data"`
+}
+
+// AddTrafficDirectorNode attaches a node to an instance of Traffic Director, leaving its other nodes untouched.
+func (c *Client) AddTrafficDirectorNode(serviceID string, zone string, fqdn string) ([]TrafficDirectorNode, error) {
+	req := trafficDirectorNodeRequest{
+		Zone:    zone,
+		FQDN:    fqdn,
+		Publish: "Y",
+	}
+
+	var resp trafficDirectorNodeAllResponse
+
+	if err := c.post(fmt.Sprintf("DSFNode/%s", serviceID), req, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Nodes, nil
+}
+
+// DeleteTrafficDirectorNode detaches a node from an instance of Traffic Director, leaving its other nodes untouched.
+func (c *Client) DeleteTrafficDirectorNode(serviceID string, zone string, fqdn string) error {
+	req := trafficDirectorNodeRequest{
+		Zone:    zone,
+		FQDN:    fqdn,
+		Publish: "Y",
+	}
+
+	if err := c.delete(fmt.Sprintf("DSFNode/%s", serviceID), req); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetTrafficDirectorNodes returns the nodes attached to an instance of Traffic Director.
+func (c *Client) GetTrafficDirectorNodes(serviceID string) ([]TrafficDirectorNode, error) {
+	var resp trafficDirectorNodeAllResponse
+
+	if err := c.get(fmt.Sprintf("DSFNode/%s", serviceID), nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Nodes, nil
+}
@@ -0,0 +1,154 @@
+package dyn
+
+import (
+	"fmt"
+)
+
+// TrafficDirectorRecord represents a Dyn Traffic Director Record.
+type TrafficDirectorRecord struct {
+	RecordID        string
+	MasterLine      string
+	Label           string
+	Weight          int
+	Endpoints       []string
+	EndpointUpCount int
+	Eligible        bool
+	Automation      string
+	Status          string
+	LastMonitored   string
+	PendingChange   string
+}
+
+type trafficDirectorRecordReference struct {
+	RecordID string `json:"dsf_record_id,omitempty"`
+}
+
+type trafficDirectorRecordData struct {
+	RecordID        string   `json:"dsf_record_id"`
+	MasterLine      string   `json:"master_line"`
+	Label           string   `json:"label"`
+	Weight          int      `json:"weight"`
+	Endpoints       []string `json:"endpoints"`
+	EndpointUpCount int      `json:"endpoint_up_count"`
+	Eligible        string   `json:"eligible"`
+	Automation      string   `json:"automation"`
+	Status          string   `json:"status"`
+	LastMonitored   string   `json:"last_monitored"`
+	PendingChange   string   `json:"pending_change"`
+}
+
+type TrafficDirectorRecordCURequest struct {
+	RecordID        string   `json:"dsf_record_id,omitempty"`
+	MasterLine      string   `json:"master_line"`
+	Label           string   `json:"label,omitempty"`
+	Weight          int      `json:"weight,omitempty"`
+	Endpoints       []string `json:"endpoints,omitempty"`
+	EndpointUpCount int      `json:"endpoint_up_count,omitempty"`
+	Eligible        string   `json:"eligible,omitempty"`
+	Automation      string   `json:"automation,omitempty"`
+	Publish         string   `json:"publish,omitempty"`
+	Notes           string   `json:"notes,omitempty"`
+}
+
+type trafficDirectorRecordDeleteRequest struct {
+	Publish string `json:"publish,omitempty"`
+	Notes   string `json:"notes,omitempty"`
+}
+
+type trafficDirectorRecordResponse struct {
+	responseHeader
+	trafficDirectorRecordData `json:"data"`
+}
+
+type trafficDirectorRecordAllResponse struct {
+	responseHeader
+	TrafficDirectorRecords []trafficDirectorRecordData `json:"data"`
+}
+
+type TrafficDirectorRecordOptionSetter func(*TrafficDirectorRecordCURequest)
+
+func (tdrd trafficDirectorRecordData) newTrafficDirectorRecord() *TrafficDirectorRecord {
+	tdr := TrafficDirectorRecord{
+		RecordID:        tdrd.RecordID,
+		MasterLine:      tdrd.MasterLine,
+		Label:           tdrd.Label,
+		Weight:          tdrd.Weight,
+		Endpoints:       tdrd.Endpoints,
+		EndpointUpCount: tdrd.EndpointUpCount,
+		Eligible:        tdrd.Eligible == "true",
+		Automation:      tdrd.Automation,
+		Status:          tdrd.Status,
+		LastMonitored:   tdrd.LastMonitored,
+		PendingChange:   tdrd.PendingChange,
+	}
+
+	return &tdr
+}
+
+// CreateTrafficDirectorRecord creates a new instance of Traffic Director Record.
+func (c *Client) CreateTrafficDirectorRecord(serviceID string, recordSetID string, masterLine string, options ...TrafficDirectorRecordOptionSetter) (*TrafficDirectorRecord, error) {
+	req := TrafficDirectorRecordCURequest{
+		MasterLine: masterLine,
+		Publish:    "Y",
+	}
+
+	for _, o := range options {
+		o(&req)
+	}
+
+	var resp trafficDirectorRecordResponse
+
+	if err := c.postRetrying(fmt.Sprintf("DSFRecord/%s/%s", serviceID, recordSetID), req, &resp); err != nil {
+		return nil, err
+	}
+
+	tdr := resp.newTrafficDirectorRecord()
+
+	return tdr, nil
+}
+
+// UpdateTrafficDirectorRecord updates an instance of Traffic Director Record.
+func (c *Client) UpdateTrafficDirectorRecord(serviceID string, recordID string, masterLine string, options ...TrafficDirectorRecordOptionSetter) (*TrafficDirectorRecord, error) {
+	req := TrafficDirectorRecordCURequest{
+		MasterLine: masterLine,
+		Publish:    "Y",
+	}
+
+	for _, o := range options {
+		o(&req)
+	}
+
+	var resp trafficDirectorRecordResponse
+
+	if err := c.put(fmt.Sprintf("DSFRecord/%s/%s", serviceID, recordID), req, &resp); err != nil {
+		return nil, err
+	}
+
+	tdr := resp.newTrafficDirectorRecord()
+
+	return tdr, nil
+}
+
+// DeleteTrafficDirectorRecord deletes an instance of Traffic Director Record.
+func (c *Client) DeleteTrafficDirectorRecord(serviceID string, recordID string) error {
+	req := trafficDirectorRecordDeleteRequest{
+		Publish: "Y",
+	}
+
+	if err := c.delete(fmt.Sprintf("DSFRecord/%s/%s", serviceID, recordID), req); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetTrafficDirectorRecord returns an existing Traffic Director Record instance.
+func (c *Client) GetTrafficDirectorRecord(serviceID string, recordID string) (*TrafficDirectorRecord, error) {
+	var resp trafficDirectorRecordResponse
+
+	if err := c.get(fmt.Sprintf("DSFRecord/%s/%s", serviceID, recordID), nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.newTrafficDirectorRecord(), nil
+}
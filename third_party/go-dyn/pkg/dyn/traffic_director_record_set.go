@@ -0,0 +1,182 @@
+package dyn
+
+import (
+	"fmt"
+)
+
+// TrafficDirectorRecordSet represents a Dyn Traffic Director Record Set.
+type TrafficDirectorRecordSet struct {
+	RecordSetID    string
+	ResponsePoolID string
+	Label          string
+	RDataClass     string
+	TTL            string
+	Eligible       bool
+	Automation     string
+	MonitorID      string
+	Status         string
+	LastMonitored  string
+	PendingChange  string
+	Records        []*TrafficDirectorRecord
+}
+
+type trafficDirectorRecordSetReference struct {
+	RecordSetID string `json:"dsf_record_set_id,omitempty"`
+}
+
+type trafficDirectorRecordSetData struct {
+	RecordSetID    string                      `json:"dsf_record_set_id"`
+	ResponsePoolID string                      `json:"dsf_response_pool_id"`
+	Label          string                      `json:"label"`
+	RDataClass     string                      `json:"rdata_class"`
+	TTL            string                      `json:"ttl"`
+	Status         string                      `json:"status"`
+	LastMonitored  string                      `json:"last_monitored"`
+	MonitorID      string                      `json:"dsf_monitor_id"`
+	PendingChange  string                      `json:"pending_change"`
+	Eligible       string                      `json:"eligible"`
+	Automation     string                      `json:"automation"`
+	Records        []trafficDirectorRecordData `json:"records"`
+}
+
+type TrafficDirectorRecordSetCURequest struct {
+	RecordSetID    string                           `json:"dsf_record_set_id,omitempty"`
+	ResponsePoolID string                           `json:"dsf_response_pool_id,omitempty"`
+	Label          string                           `json:"label,omitempty"`
+	RDataClass     string                           `json:"rdata_class"`
+	TTL            string                           `json:"ttl,omitempty"`
+	MonitorID      string                           `json:"dsf_monitor_id,omitempty"`
+	Publish        string                           `json:"publish,omitempty"`
+	Notes          string                           `json:"notes,omitempty"`
+	Eligible       string                           `json:"eligible,omitempty"`
+	Automation     string                           `json:"automation,omitempty"`
+	Records        []TrafficDirectorRecordCURequest `json:"records,omitempty"`
+}
+
+type trafficDirectorRecordSetMonitorRequest struct {
+	MonitorID string `json:"dsf_monitor_id"`
+	Publish   string `json:"publish,omitempty"`
+	Notes     string `json:"notes,omitempty"`
+}
+
+type trafficDirectorRecordSetDeleteRequest struct {
+	Publish string `json:"publish,omitempty"`
+	Notes   string `json:"notes,omitempty"`
+}
+
+type trafficDirectorRecordSetResponse struct {
+	responseHeader
+	trafficDirectorRecordSetData `json:"data"`
+}
+
+type trafficDirectorRecordSetAllResponse struct {
+	responseHeader
+	TrafficDirectorRecordSets []trafficDirectorRecordSetData `json:"data"`
+}
+
+type TrafficDirectorRecordSetOptionSetter func(*TrafficDirectorRecordSetCURequest)
+
+func (tdrsd trafficDirectorRecordSetData) newTrafficDirectorRecordSet() *TrafficDirectorRecordSet {
+	tdrs := TrafficDirectorRecordSet{
+		RecordSetID:    tdrsd.RecordSetID,
+		ResponsePoolID: tdrsd.ResponsePoolID,
+		Label:          tdrsd.Label,
+		RDataClass:     tdrsd.RDataClass,
+		TTL:            tdrsd.TTL,
+		Eligible:       tdrsd.Eligible == "true",
+		Automation:     tdrsd.Automation,
+		MonitorID:      tdrsd.MonitorID,
+		Status:         tdrsd.Status,
+		LastMonitored:  tdrsd.LastMonitored,
+		PendingChange:  tdrsd.PendingChange,
+		Records:        make([]*TrafficDirectorRecord, len(tdrsd.Records)),
+	}
+
+	for idx, record := range tdrsd.Records {
+		tdrs.Records[idx] = record.newTrafficDirectorRecord()
+	}
+
+	return &tdrs
+}
+
+// CreateTrafficDirectorRecordSet creates a new instance of Traffic Director Record Set.
+func (c *Client) CreateTrafficDirectorRecordSet(serviceID string, rDataClass string, options ...TrafficDirectorRecordSetOptionSetter) (*TrafficDirectorRecordSet, error) {
+	req := TrafficDirectorRecordSetCURequest{
+		RDataClass: rDataClass,
+		Publish:    "Y",
+	}
+
+	for _, o := range options {
+		o(&req)
+	}
+
+	var resp trafficDirectorRecordSetResponse
+
+	if err := c.postRetrying(fmt.Sprintf("DSFRecordSet/%s", serviceID), req, &resp); err != nil {
+		return nil, err
+	}
+
+	tdrs := resp.newTrafficDirectorRecordSet()
+
+	return tdrs, nil
+}
+
+// UpdateTrafficDirectorRecordSet updates an instance of Traffic Director Record Set.
+func (c *Client) UpdateTrafficDirectorRecordSet(serviceID string, recordSetID string, rDataClass string, options ...TrafficDirectorRecordSetOptionSetter) (*TrafficDirectorRecordSet, error) {
+	req := TrafficDirectorRecordSetCURequest{
+		RDataClass: rDataClass,
+		Publish:    "Y",
+	}
+
+	for _, o := range options {
+		o(&req)
+	}
+
+	var resp trafficDirectorRecordSetResponse
+
+	if err := c.put(fmt.Sprintf("DSFRecordSet/%s/%s", serviceID, recordSetID), req, &resp); err != nil {
+		return nil, err
+	}
+
+	tdrs := resp.newTrafficDirectorRecordSet()
+
+	return tdrs, nil
+}
+
+// DetachTrafficDirectorRecordSetMonitor removes the monitor from an instance of Traffic Director Record Set.
+func (c *Client) DetachTrafficDirectorRecordSetMonitor(serviceID string, recordSetID string) error {
+	req := trafficDirectorRecordSetMonitorRequest{
+		MonitorID: "",
+		Publish:   "Y",
+	}
+
+	if err := c.put(fmt.Sprintf("DSFRecordSet/%s/%s", serviceID, recordSetID), req, nil); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteTrafficDirectorRecordSet deletes an instance of Traffic Director Record Set.
+func (c *Client) DeleteTrafficDirectorRecordSet(serviceID string, recordSetID string) error {
+	req := trafficDirectorRecordSetDeleteRequest{
+		Publish: "Y",
+	}
+
+	if err := c.delete(fmt.Sprintf("DSFRecordSet/%s/%s", serviceID, recordSetID), req); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetTrafficDirectorRecordSet returns an existing Traffic Director Record Set instance.
+func (c *Client) GetTrafficDirectorRecordSet(serviceID string, recordSetID string) (*TrafficDirectorRecordSet, error) {
+	var resp trafficDirectorRecordSetResponse
+
+	if err := c.get(fmt.Sprintf("DSFRecordSet/%s/%s", serviceID, recordSetID), nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.newTrafficDirectorRecordSet(), nil
+}
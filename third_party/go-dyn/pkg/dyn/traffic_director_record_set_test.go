@@ -0,0 +1,35 @@
+package dyn
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestUpdateTrafficDirectorRecordSetResponsePool(t *testing.T) {
+	c := mockClient("traffic_director_record_set/update.json", func(w http.ResponseWriter, r *http.Request, j interface{}) {
+		assertMethod(t, http.MethodPut, r)
+		assertPath(t, "/REST/DSFRecordSet/service-1/record-set-1", r)
+
+		assertUserAgent(t, "go-dyn/0.0.0", r)
+		assertContentType(t, "application/json", r)
+		assertAuthToken(t, "insert-token-here", r)
+
+		assertJSON(t, "A", "rdata_class", j)
+		assertJSON(t, "response-pool-2", "dsf_response_pool_id", j)
+		assertJSON(t, "Y", "publish", j)
+
+		w.Header().Set("Content-Type", "application/json")
+	})
+
+	c.token = "insert-token-here"
+
+	tdrs, err := c.UpdateTrafficDirectorRecordSet("service-1", "record-set-1", "A", func(req *TrafficDirectorRecordSetCURequest) {
+		req.ResponsePoolID = "response-pool-2"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, "record-set-1", tdrs.RecordSetID, "RecordSetID")
+	assertEqual(t, "response-pool-2", tdrs.ResponsePoolID, "ResponsePoolID")
+}
@@ -0,0 +1,181 @@
+package dyn
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// TrafficDirectorResponsePool represents a Dyn Traffic Director Response Pool.
+type TrafficDirectorResponsePool struct {
+	ResponsePoolID string
+	Label          string
+	Eligible       bool
+	Automation     string
+	Status         string
+	LastMonitored  string
+	PendingChange  string
+	RecordSets     []*TrafficDirectorRecordSet
+}
+
+type trafficDirectorResponsePoolReference struct {
+	ResponsePoolID string `json:"dsf_response_pool_id,omitempty"`
+	*TrafficDirectorResponsePoolCURequest
+}
+
+type trafficDirectorResponsePoolData struct {
+	ResponsePoolID  string                              `json:"dsf_response_pool_id"`
+	Label           string                              `json:"label"`
+	Rulesets        []trafficDirectorRulesetData        `json:"rulesets"`
+	RecordSetChains []trafficDirectorRecordSetChainData `json:"rs_chains"`
+	Status          string                              `json:"status"`
+	LastMonitored   string                              `json:"last_monitored"`
+	PendingChange   string                              `json:"pending_change"`
+	Eligible        string                              `json:"eligible"`
+	Automation      string                              `json:"automation"`
+}
+
+type trafficDirectorRecordSetChainData struct {
+	RecordSets []trafficDirectorRecordSetData `json:"record_sets"`
+}
+
+type TrafficDirectorResponsePoolCURequest struct {
+	ResponsePoolID  string                                   `json:"dsf_response_pool_id,omitempty"`
+	Label           string                                   `json:"label"`
+	Publish         string                                   `json:"publish,omitempty"`
+	Notes           string                                   `json:"notes,omitempty"`
+	Eligible        string                                   `json:"eligible,omitempty"`
+	Automation      string                                   `json:"automation,omitempty"`
+	RecordSetChains []TrafficDirectorRecordSetChainCURequest `json:"rs_chains,omitempty"`
+}
+
+// TrafficDirectorRecordSetChainCURequest describes a chain of record sets within a response pool.
+type TrafficDirectorRecordSetChainCURequest struct {
+	Label      string                              `json:"label,omitempty"`
+	RecordSets []TrafficDirectorRecordSetCURequest `json:"record_sets"`
+}
+
+type trafficDirectorResponsePoolDeleteRequest struct {
+	Publish string `json:"publish,omitempty"`
+	Notes   string `json:"notes,omitempty"`
+}
+
+type trafficDirectorResponsePoolResponse struct {
+	responseHeader
+	trafficDirectorResponsePoolData `json:"data"`
+}
+
+type trafficDirectorResponsePoolAllResponse struct {
+	responseHeader
+	TrafficDirectorResponsePools []trafficDirectorResponsePoolData `json:"data"`
+}
+
+type TrafficDirectorResponsePoolOptionSetter func(*TrafficDirectorResponsePoolCURequest)
+
+func (tdrpd trafficDirectorResponsePoolData) newTrafficDirectorResponsePool() *TrafficDirectorResponsePool {
+	tdrp := TrafficDirectorResponsePool{
+		ResponsePoolID: tdrpd.ResponsePoolID,
+		Label:          tdrpd.Label,
+		Eligible:       tdrpd.Eligible == "true",
+		Automation:     tdrpd.Automation,
+		Status:         tdrpd.Status,
+		LastMonitored:  tdrpd.LastMonitored,
+		PendingChange:  tdrpd.PendingChange,
+		RecordSets:     make([]*TrafficDirectorRecordSet, 0),
+	}
+
+	for _, recordSetChain := range tdrpd.RecordSetChains {
+		for _, recordSet := range recordSetChain.RecordSets {
+			tdrp.RecordSets = append(tdrp.RecordSets, recordSet.newTrafficDirectorRecordSet())
+		}
+	}
+
+	return &tdrp
+}
+
+// CreateTrafficDirectorResponsePool creates a new instance of Traffic Director Response Pool.
+func (c *Client) CreateTrafficDirectorResponsePool(serviceID string, label string, options ...TrafficDirectorResponsePoolOptionSetter) (*TrafficDirectorResponsePool, error) {
+	req := TrafficDirectorResponsePoolCURequest{
+		Label:   label,
+		Publish: "Y",
+	}
+
+	for _, o := range options {
+		o(&req)
+	}
+
+	var resp trafficDirectorResponsePoolResponse
+
+	if err := c.postRetrying(fmt.Sprintf("DSFResponsePool/%s", serviceID), req, &resp); err != nil {
+		return nil, err
+	}
+
+	tdrp := resp.newTrafficDirectorResponsePool()
+
+	return tdrp, nil
+}
+
+// UpdateTrafficDirectorResponsePool updates an instance of Traffic Director Response Pool.
+func (c *Client) UpdateTrafficDirectorResponsePool(serviceID string, responsePoolID string, label string, options ...TrafficDirectorResponsePoolOptionSetter) (*TrafficDirectorResponsePool, error) {
+	req := TrafficDirectorResponsePoolCURequest{
+		Label:   label,
+		Publish: "Y",
+	}
+
+	for _, o := range options {
+		o(&req)
+	}
+
+	var resp trafficDirectorResponsePoolResponse
+
+	if err := c.put(fmt.Sprintf("DSFResponsePool/%s/%s", serviceID, responsePoolID), req, &resp); err != nil {
+		return nil, err
+	}
+
+	tdrp := resp.newTrafficDirectorResponsePool()
+
+	return tdrp, nil
+}
+
+// DeleteTrafficDirectorResponsePool deletes an instance of Traffic Director Response Pool.
+func (c *Client) DeleteTrafficDirectorResponsePool(serviceID string, responsePoolID string) error {
+	req := trafficDirectorResponsePoolDeleteRequest{
+		Publish: "Y",
+	}
+
+	if err := c.delete(fmt.Sprintf("DSFResponsePool/%s/%s", serviceID, responsePoolID), req); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetTrafficDirectorResponsePool returns an existing Traffic Director Response Pool instance.
+func (c *Client) GetTrafficDirectorResponsePool(serviceID string, responsePoolID string) (*TrafficDirectorResponsePool, error) {
+	var resp trafficDirectorResponsePoolResponse
+
+	if err := c.get(fmt.Sprintf("DSFResponsePool/%s/%s", serviceID, responsePoolID), nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.newTrafficDirectorResponsePool(), nil
+}
+
+// GetTrafficDirectorResponsePools returns every Traffic Director Response Pool instance of a service,
+// including those that aren't used by any ruleset.
+func (c *Client) GetTrafficDirectorResponsePools(serviceID string) ([]*TrafficDirectorResponsePool, error) {
+	var resp trafficDirectorResponsePoolAllResponse
+
+	params := url.Values{}
+	params.Set("detail", "Y")
+
+	if err := c.get(fmt.Sprintf("DSFResponsePool/%s", serviceID), params, &resp); err != nil {
+		return nil, err
+	}
+
+	tdrps := make([]*TrafficDirectorResponsePool, len(resp.TrafficDirectorResponsePools))
+	for idx, tdrp := range resp.TrafficDirectorResponsePools {
+		tdrps[idx] = tdrp.newTrafficDirectorResponsePool()
+	}
+
+	return tdrps, nil
+}
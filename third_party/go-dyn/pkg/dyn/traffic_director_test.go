@@ -1,6 +1,7 @@
 package dyn
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
@@ -84,9 +85,10 @@ func TestEachTrafficDirector(t *testing.T) {
 
 	i := 1
 
-	n, err := c.EachTrafficDirector(func(td *TrafficDirector) {
+	n, err := c.EachTrafficDirector(context.Background(), func(td *TrafficDirector) error {
 		assertTrafficDirector(t, fmt.Sprintf("service-%d", i), fmt.Sprintf("service %d", i), 15, i != 5, td)
 		i++
+		return nil
 	})
 
 	if err != nil {
@@ -143,3 +145,43 @@ func TestGetTrafficDirector(t *testing.T) {
 		assertTrafficDirector(t, "insert-service-id-here", "insert-label-here", 60, false, td)
 	}
 }
+
+func TestPublishTrafficDirector(t *testing.T) {
+	serviceID := "service-1"
+
+	c := mockClient("traffic_director/get.json", func(w http.ResponseWriter, r *http.Request, j interface{}) {
+		assertMethod(t, http.MethodPut, r)
+		assertPath(t, fmt.Sprintf("/REST/DSF/%s", serviceID), r)
+
+		assertJSON(t, "Y", "publish", j)
+		assertJSON(t, nil, "label", j)
+
+		w.Header().Set("Content-Type", "application/json")
+	})
+
+	if _, err := c.PublishTrafficDirector(serviceID); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUpdateTrafficDirectorRulesetOrdering(t *testing.T) {
+	for _, publish := range []bool{false, true} {
+		c := mockClient("traffic_director/get.json", func(w http.ResponseWriter, r *http.Request, j interface{}) {
+			assertMethod(t, http.MethodPut, r)
+			assertPath(t, "/REST/DSFRuleset/service-1/ruleset-1", r)
+
+			assertJSON(t, float64(0), "ordering", j)
+			if publish {
+				assertJSON(t, "Y", "publish", j)
+			} else {
+				assertJSON(t, nil, "publish", j)
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+		})
+
+		if _, err := c.UpdateTrafficDirectorRulesetOrdering("service-1", "ruleset-1", 0, publish); err != nil {
+			t.Error(err)
+		}
+	}
+}
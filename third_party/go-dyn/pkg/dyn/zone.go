@@ -0,0 +1,160 @@
+package dyn
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// SerialStyle values
+const (
+	SerialStyleDefault   = ""
+	SerialStyleIncrement = "increment" // Serial incremented by 1 on every change. Default setting.
+	SerialStyleEpoch     = "epoch"     // Serial is UNIX timestamp at the time of the publish.
+	SerialStyleDay       = "day"       // Serial form of YYYYMMDDxx where xx is incremented for each change in a day.
+	SerialStyleMinute    = "minute"    // Serial form of YYMMDDHHMM.
+)
+
+// ZoneType values
+const (
+	ZoneTypePrimary   = "Primary"
+	ZoneTypeSecondary = "Secondary"
+)
+
+// Zone represents a Dyn zone.
+type Zone struct {
+	Serial      int    `json:"serial"`
+	SerialStyle string `json:"serial_style"`
+	Zone        string `json:"zone"`
+	ZoneType    string `json:"zone_type"`
+}
+
+type zoneCreateRequest struct {
+	RName       string `json:"rname"`
+	SerialStyle string `json:"serial_style,omitempty"`
+	TTL         string `json:"ttl"`
+}
+
+type zoneUpdateRequest struct {
+	Freeze  bool   `json:"freeze,omitempty"`
+	Thaw    bool   `json:"thaw,omitempty"`
+	Publish bool   `json:"publish,omitempty"`
+	Notes   string `json:"notes,omitempty"`
+}
+
+type zoneResponseData struct {
+	TaskID string `json:"task_id"`
+	Zone
+}
+
+type zoneResponse struct {
+	responseHeader
+	zoneResponseData `json:"data"`
+}
+
+type zoneGetResponse struct {
+	responseHeader
+	Zone `json:"data"`
+}
+
+// CreateZone creates a new Zone.
+func (c *Client) CreateZone(zone string, rName string, ttl int, options ...ZoneOption) (*Zone, error) {
+	req := zoneCreateRequest{
+		RName: rName,
+		TTL:   strconv.Itoa(ttl),
+	}
+
+	for _, o := range options {
+		o(&req)
+	}
+
+	var resp zoneResponse
+
+	if err := c.post(fmt.Sprintf("Zone/%s", zone), req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.Zone, nil
+}
+
+// GetZone returns an existing Zone.
+func (c *Client) GetZone(zone string) (*Zone, error) {
+	var resp zoneGetResponse
+
+	if err := c.get(fmt.Sprintf("Zone/%s", zone), nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.Zone, nil
+}
+
+// EachZone calls the provided function for every existing Dyn Managed DNS zone,
+// as the response is read. Iterating stops when f returns an error, or
+// ErrStopIteration to stop without failing, or when ctx is done.
+func (c *Client) EachZone(ctx context.Context, f func(z *Zone) error) (int, error) {
+	var h responseHeader
+
+	params := url.Values{}
+	params.Set("detail", "Y")
+
+	n := 0
+
+	err := c.stream(ctx, "Zone", params, &h, func(dec *json.Decoder) error {
+		return decodeResponse(dec, &h, func(dec *json.Decoder) error {
+			return decodeEach(ctx, dec, func() error {
+				var z Zone
+
+				if err := dec.Decode(&z); err != nil {
+					return err
+				}
+
+				n++
+
+				return f(&z)
+			})
+		})
+	})
+
+	return eachResult(n, err)
+}
+
+// PublishZone causes pending changes to become part of the Zone.
+func (c *Client) PublishZone(zone, notes string) (*Zone, error) {
+	req := zoneUpdateRequest{
+		Publish: true,
+		Notes:   notes,
+	}
+
+	var resp zoneResponse
+
+	if err := c.put(fmt.Sprintf("Zone/%s", zone), req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.Zone, nil
+}
+
+// FreezeZone prevents changes to the Zone.
+func (c *Client) FreezeZone(zone string) error {
+	req := zoneUpdateRequest{
+		Freeze: true,
+	}
+
+	return c.put(fmt.Sprintf("Zone/%s", zone), req, nil)
+}
+
+// ThawZone allows changes to again be made to the Zone.
+func (c *Client) ThawZone(zone string) error {
+	req := zoneUpdateRequest{
+		Thaw: true,
+	}
+
+	return c.put(fmt.Sprintf("Zone/%s", zone), req, nil)
+}
+
+// DeleteZone removes the Zone.
+func (c *Client) DeleteZone(zone string) error {
+	return c.delete(fmt.Sprintf("Zone/%s", zone), nil)
+}
@@ -1,6 +1,7 @@
 package dyn
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
@@ -81,7 +82,7 @@ func TestCreateZoneError(t *testing.T) {
 	c.token = "insert-token-here"
 
 	if _, err := c.CreateZone(zone, "admin@example.com", 24*60*60); err != nil {
-		assertEqual(t, "TARGET_EXISTS: name: Name already exists", err.Error(), "error")
+		assertEqual(t, "TARGET_EXISTS: name: Name already exists (POST Zone/go-dyn-test-create.go-dyn.com: 400 Bad Request, job 12345678)", err.Error(), "error")
 	} else {
 		t.Error("Expected Create to fail")
 	}
@@ -120,7 +121,17 @@ func TestGetZoneError(t *testing.T) {
 	})
 
 	if _, err := c.GetZone(zone); err != nil {
-		assertEqual(t, "NOT_FOUND: zone: No such zone", err.Error(), "error")
+		assertEqual(t, "NOT_FOUND: zone: No such zone (GET Zone/missing.go-dyn.com: 404 Not Found, job 12345678)", err.Error(), "error")
+
+		apiErr, ok := err.(*Error)
+		if !ok {
+			t.Fatalf("Expected an *Error, got %T", err)
+		}
+
+		assertEqual(t, http.StatusNotFound, apiErr.StatusCode, "StatusCode")
+		assertEqual(t, true, apiErr.HasCode(ErrorCodeNotFound), "HasCode")
+		assertEqual(t, "zone", apiErr.Errors()[0].Field(), "Field")
+		assertEqual(t, "Zone", apiErr.Command(), "Command")
 	} else {
 		t.Error("Expected Get to fail")
 	}
@@ -143,9 +154,10 @@ func TestEachZone(t *testing.T) {
 
 	i := 1
 
-	n, err := c.EachZone(func(z *Zone) {
+	n, err := c.EachZone(context.Background(), func(z *Zone) error {
 		assertZone(t, i*11, SerialStyleIncrement, fmt.Sprintf("zone-%d.co", i), ZoneTypePrimary, z)
 		i++
+		return nil
 	})
 
 	if err != nil {
@@ -0,0 +1,5 @@
+package version
+
+// VERSION is the app-global version string, which should be substituted with a
+// real value during build.
+var VERSION = "0.0.0"